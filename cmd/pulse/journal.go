@@ -1,210 +1,848 @@
 // ABOUTME: CLI commands for journal operations.
-// ABOUTME: Provides write, search, list, and read subcommands for the journal.
+// ABOUTME: Provides write, edit, append, rm, trash, search, list, read, reindex, and embed subcommands for the journal.
 package main
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/spf13/cobra"
 
+	"github.com/2389-research/pulse/internal/config"
+	"github.com/2389-research/pulse/internal/digest"
+	"github.com/2389-research/pulse/internal/embeddings"
 	"github.com/2389-research/pulse/internal/models"
+	"github.com/2389-research/pulse/internal/query"
+	"github.com/2389-research/pulse/internal/redact"
+	"github.com/2389-research/pulse/internal/storage"
 )
 
 var journalCmd = &cobra.Command{
 	Use:   "journal",
 	Short: "Manage journal entries",
-	Long:  "Write, search, list, and read private journal entries.",
+	Long:  "Write, edit, search, list, and read private journal entries.",
 }
 
 var journalWriteCmd = &cobra.Command{
 	Use:   "write",
 	Short: "Write a journal entry",
-	Long:  "Create a journal entry with one or more sections.",
-	RunE:  runJournalWrite,
+	Long: `Create a journal entry with one or more sections.
+
+Sections are routed like process_thoughts: each goes to the journal its
+configured route names, so one call may write several entries.
+Use --type to put every section in one journal, and --linked to have split
+entries record each other's IDs.
+
+--template standup opens a template in $EDITOR instead: write under the section
+headings, and the guiding questions in <!-- --> comments are dropped on save.
+Sections left empty are skipped. See "journal templates" for the list.
+
+Tags, mood, valence, agent, git context, and --attr key=value pairs are stored
+in the frontmatter and can be filtered on by list and search.`,
+	RunE: runJournalWrite,
+}
+
+var journalEditCmd = &cobra.Command{
+	Use:   "edit <path>",
+	Short: "Edit sections of a journal entry",
+	Long: `Replace sections of an existing journal entry. Only the sections passed as
+flags change; pass an empty value (e.g. --feelings "") to remove a section.
+The entry keeps its ID and records when it was updated.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runJournalEdit,
+}
+
+var journalAppendCmd = &cobra.Command{
+	Use:   "append <path> <section> <text>",
+	Short: "Append text to a section of a journal entry",
+	Long:  "Append text to one section of an existing journal entry, creating the section if it is missing.",
+	Args:  cobra.ExactArgs(3),
+	RunE:  runJournalAppend,
+}
+
+var journalRmCmd = &cobra.Command{
+	Use:   "rm <path>",
+	Short: "Move a journal entry to the trash",
+	Long: `Move a journal entry and its embedding sidecar into the .trash/ directory of
+its journal root. Use "pulse journal trash restore" to undo.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runJournalRm,
+}
+
+var journalTrashCmd = &cobra.Command{
+	Use:   "trash",
+	Short: "Manage deleted journal entries",
+	Long:  "List, restore, and permanently remove journal entries that were moved to the trash.",
+}
+
+var journalTrashListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List trashed entries",
+	Args:  cobra.NoArgs,
+	RunE:  runJournalTrashList,
+}
+
+var journalTrashRestoreCmd = &cobra.Command{
+	Use:   "restore <path>",
+	Short: "Restore a trashed entry",
+	Long:  "Move a trashed entry back to its original location. Accepts the trash path or the original path.",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runJournalTrashRestore,
+}
+
+var journalTrashEmptyCmd = &cobra.Command{
+	Use:   "empty",
+	Short: "Permanently remove old trashed entries",
+	Long: `Permanently remove trashed entries older than the retention period
+(journal.trash_retention_days in config, default 30). Use --all to remove everything.`,
+	Args: cobra.NoArgs,
+	RunE: runJournalTrashEmpty,
 }
 
 var journalSearchCmd = &cobra.Command{
 	Use:   "search <query>",
 	Short: "Search journal entries",
-	Long:  "Search journal entries by substring matching.",
-	Args:  cobra.ExactArgs(1),
-	RunE:  runJournalSearch,
+	Long: `Search journal entries, ranked by keyword relevance blended with semantic similarity.
+
+Plain words are ranked by relevance. The query may also use:
+
+  "exact phrase"          the phrase must appear
+  a AND b, a OR b         both / either (juxtaposed terms are ANDed)
+  NOT a, -a               exclude entries matching a
+  ( ... )                 grouping
+  section:feelings        entries with that section
+  type:project            entries in that journal
+  tag:incident            entries with that tag
+  author:name             entries written by that agent
+  before:2026-03-01       entries created before that day
+  after:2026-03-01        entries created after that day
+
+With any of these, only entries matching the whole expression are returned.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runJournalSearch,
 }
 
 var journalListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List recent journal entries",
-	Long:  "List journal entries sorted by date.",
-	RunE:  runJournalList,
+	Long: `List journal entries sorted by date, newest first unless --sort oldest.
+
+--since and --until take a date (2026-03-01), a timestamp (2026-03-01T15:04),
+today, yesterday, or an age such as 3h, 3d, 2w, 6m, or 1y, all in local time.
+A date given to --until includes that whole day. --days is shorthand for
+--since Nd and is ignored when --since is set.`,
+	RunE: runJournalList,
+}
+
+var journalDigestCmd = &cobra.Command{
+	Use:   "digest",
+	Short: "Summarize recent journal entries",
+	Long: `Summarize the journal entries written over a period: entry counts per
+section, type, and day, the most frequent terms, new technical insights,
+recurring themes in feelings, and links to every source entry.
+
+The digest is computed locally from the entries; nothing is sent anywhere.
+--since and --until take the same dates and ages as journal list, e.g.
+--since 7d for a weekly digest or --since today for a daily one.`,
+	Args: cobra.NoArgs,
+	RunE: runJournalDigest,
+}
+
+var journalExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export journal entries",
+	Long: `Export journal entries, oldest first, in one of three formats:
+
+  jsonl      one JSON entry per line, suitable for journal import
+  markdown   a single document with a table of contents by day
+  html       a self-contained static site with a page per day and in-browser search
+
+jsonl and markdown go to stdout unless --output names a file; html needs
+--output to name a directory. Narrow the export with --type, --since, --until,
+--section, and the metadata filters.`,
+	Args: cobra.NoArgs,
+	RunE: runJournalExport,
+}
+
+var journalImportCmd = &cobra.Command{
+	Use:   "import <path>",
+	Short: "Import journal entries from other tools",
+	Long: `Import journal entries, keeping their original IDs and timestamps.
+
+Formats (guessed from the path unless --format is given):
+
+  jsonl      the output of journal export
+  markdown   a .md file or a directory of them, with "## Section" headings;
+             frontmatter dates, older private-journal layouts, and
+             YYYY-MM-DD/HH-MM-SS file paths supply the timestamps
+  csv        one entry per row; columns named after sections or fields map
+             themselves, others map with --map field=column
+
+Entries whose ID or sections match an existing entry are skipped. Entries
+without a type go to the journal named by --type. Use --dry-run to see what
+would be imported.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runJournalImport,
 }
 
 var journalReadCmd = &cobra.Command{
 	Use:   "read <path>",
 	Short: "Read a journal entry",
-	Long:  "Read a specific journal entry by file path.",
-	Args:  cobra.ExactArgs(1),
-	RunE:  runJournalRead,
+	Long: `Read a specific journal entry by file path.
+
+With --thought, also print every entry split from the same write, so a thought
+divided between the project and user journals reads as one.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runJournalRead,
+}
+
+var journalReindexCmd = &cobra.Command{
+	Use:   "reindex",
+	Short: "Rebuild the journal index",
+	Long: `Rebuild the search and listing index in every journal root from the entries on disk.
+
+The index normally updates itself as entries are written. Run this after editing
+entry files in place or copying entries in from elsewhere.`,
+	Args: cobra.NoArgs,
+	RunE: runJournalReindex,
+}
+
+var journalEmbedCmd = &cobra.Command{
+	Use:   "embed",
+	Short: "Backfill embedding sidecars",
+	Long: `Generate .embedding sidecars for journal entries that have none, or whose
+sidecar came from a different embedder or predates the entry's last change.`,
+	Args: cobra.NoArgs,
+	RunE: runJournalEmbed,
+}
+
+var journalEncryptAllCmd = &cobra.Command{
+	Use:   "encrypt-all",
+	Short: "Encrypt the body of every journal entry",
+	Long: `Encrypt the body of every entry in every journal root, including the trash,
+with the passphrase from PULSE_JOURNAL_PASSPHRASE or journal.passphrase_file.
+
+Frontmatter stays readable, so listing and metadata filters keep working without
+the passphrase. Embedding sidecars of encrypted entries are removed, since they
+hold the entry's text. Set journal.encrypt in config to encrypt new entries too.`,
+	Args: cobra.NoArgs,
+	RunE: runJournalEncryptAll,
+}
+
+var journalDecryptAllCmd = &cobra.Command{
+	Use:   "decrypt-all",
+	Short: "Decrypt the body of every journal entry",
+	Long: `Rewrite every encrypted entry in every journal root, including the trash,
+with a plaintext body. Unset journal.encrypt in config first, or new entries
+will still be encrypted.`,
+	Args: cobra.NoArgs,
+	RunE: runJournalDecryptAll,
+}
+
+var journalGCCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Apply journal retention rules",
+	Long: `Apply the retention rules in journal.retention: permanently delete entries, or
+one section of them, past an age, and archive old date directories into
+compressed .archive/YYYY-MM-DD.tar.zst bundles in their root.
+
+Archived entries keep their paths and can still be read, listed, searched and
+exported, but not edited. The trash is emptied separately by "trash empty".`,
+	Args: cobra.NoArgs,
+	RunE: runJournalGC,
+}
+
+var journalDoctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the journal for common problems",
+	Long: `Check each journal root for problems. For now this reports journal roots inside
+a git work tree that git does not ignore, and journal files git already tracks.
+
+Exits non-zero when journal files are tracked, so it can guard CI.`,
+	Args: cobra.NoArgs,
+	RunE: runJournalDoctor,
 }
 
 // Flags
 var (
-	feelings          string
-	projectNotes      string
-	userContext       string
-	technicalInsights string
-	worldKnowledge    string
-	journalLimit      int
-	journalDays       int
-	journalType       string
+	sectionValues  = make(map[string]*string) // section name -> flag value, see configureJournal
+	journalLimit   int
+	journalDays    int
+	journalSince   string
+	journalUntil   string
+	journalOffset  int
+	journalSort    string
+	digestSince    string
+	digestUntil    string
+	digestFormat   string
+	digestTop      int
+	exportFormat   string
+	exportOutput   string
+	exportSince    string
+	exportUntil    string
+	exportSections []string
+	importFormat   string
+	importType     string
+	importMapping  map[string]string
+	importDryRun   bool
+	searchDays     int
+	writeType      string
+	writeLinked    bool
+	journalType    string
+	embedDryRun    bool
+	embedForce     bool
+	embedWorkers   int
+	deleteReason   string
+	trashOlderThan int
+	trashAll       bool
+	gcDryRun       bool
+	readThought    bool
+
+	// Entry metadata: recorded by write, filtered on by list and search
+	metaTags       []string
+	metaMood       string
+	metaValence    float64
+	metaMinValence float64
+	metaMaxValence float64
+	metaAgent      string
+	metaRepo       string
+	metaBranch     string
+	metaCommit     string
+	metaAttributes map[string]string
 )
 
 func init() {
 	rootCmd.AddCommand(journalCmd)
 	journalCmd.AddCommand(journalWriteCmd)
+	journalCmd.AddCommand(journalEditCmd)
+	journalCmd.AddCommand(journalAppendCmd)
+	journalCmd.AddCommand(journalRmCmd)
+	journalCmd.AddCommand(journalTrashCmd)
+	journalTrashCmd.AddCommand(journalTrashListCmd)
+	journalTrashCmd.AddCommand(journalTrashRestoreCmd)
+	journalTrashCmd.AddCommand(journalTrashEmptyCmd)
 	journalCmd.AddCommand(journalSearchCmd)
 	journalCmd.AddCommand(journalListCmd)
+	journalCmd.AddCommand(journalDigestCmd)
+	journalCmd.AddCommand(journalExportCmd)
+	journalCmd.AddCommand(journalImportCmd)
 	journalCmd.AddCommand(journalReadCmd)
+	journalCmd.AddCommand(journalReindexCmd)
+	journalCmd.AddCommand(journalEmbedCmd)
+	journalCmd.AddCommand(journalEncryptAllCmd)
+	journalCmd.AddCommand(journalDecryptAllCmd)
+	journalCmd.AddCommand(journalGCCmd)
+	journalCmd.AddCommand(journalDoctorCmd)
 
-	journalWriteCmd.Flags().StringVar(&feelings, "feelings", "", "Feelings section content")
-	journalWriteCmd.Flags().StringVar(&projectNotes, "project-notes", "", "Project notes section content")
-	journalWriteCmd.Flags().StringVar(&userContext, "user-context", "", "User context section content")
-	journalWriteCmd.Flags().StringVar(&technicalInsights, "technical-insights", "", "Technical insights section content")
-	journalWriteCmd.Flags().StringVar(&worldKnowledge, "world-knowledge", "", "World knowledge section content")
+	journalWriteCmd.Flags().StringVar(&writeType, "type", "", "Write every section to one journal, e.g. project or user (default: route each section)")
+	journalWriteCmd.Flags().BoolVar(&writeLinked, "linked", false, "Record each entry's ID in the other when sections are split")
+	journalWriteCmd.Flags().StringSliceVar(&metaTags, "tag", nil, "Tag the entry (repeatable)")
+	journalWriteCmd.Flags().StringVar(&metaMood, "mood", "", "One word for the mood, e.g. frustrated")
+	journalWriteCmd.Flags().Float64Var(&metaValence, "valence", 0, "How positive the mood is, from -1 to 1")
+	journalWriteCmd.Flags().StringVar(&metaAgent, "agent", "", "Agent identity (default: the social identity)")
+	journalWriteCmd.Flags().StringVar(&metaRepo, "repo", "", "Repository the entry is about (default for project entries: the enclosing git repository)")
+	journalWriteCmd.Flags().StringVar(&metaBranch, "branch", "", "Git branch the entry is about (default for project entries: the current branch)")
+	journalWriteCmd.Flags().StringVar(&metaCommit, "commit", "", "Git commit the entry is about (default for project entries: HEAD)")
+	journalWriteCmd.Flags().StringToStringVar(&metaAttributes, "attr", nil, "Free-form key=value attribute (repeatable)")
+
+	journalRmCmd.Flags().StringVar(&deleteReason, "reason", "", "Why the entry is being deleted")
+
+	journalTrashListCmd.Flags().StringVar(&journalType, "type", "all", "Journals: a name, comma-separated names, or all")
+	journalTrashEmptyCmd.Flags().IntVar(&trashOlderThan, "older-than", 0, "Remove entries trashed more than this many days ago (default: configured retention)")
+	journalTrashEmptyCmd.Flags().BoolVar(&trashAll, "all", false, "Remove every trashed entry regardless of age")
+	journalGCCmd.Flags().BoolVar(&gcDryRun, "dry-run", false, "Report what would change without changing anything")
+
+	journalReadCmd.Flags().BoolVar(&readThought, "thought", false, "Include sibling entries from the same thought in every journal")
 
 	journalListCmd.Flags().IntVar(&journalLimit, "limit", 10, "Maximum number of entries to show")
-	journalListCmd.Flags().IntVar(&journalDays, "days", 30, "Number of days back to search")
-	journalListCmd.Flags().StringVar(&journalType, "type", "both", "Entry type: project, user, or both")
+	journalListCmd.Flags().IntVar(&journalDays, "days", 30, "Number of days back to search; ignored with --since, and with --until unless given")
+	journalListCmd.Flags().StringVar(&journalSince, "since", "", "Earliest entries to show: a date, timestamp, today, yesterday, or an age like 2w")
+	journalListCmd.Flags().StringVar(&journalUntil, "until", "", "Latest entries to show, in the same forms as --since")
+	journalListCmd.Flags().IntVar(&journalOffset, "offset", 0, "Number of entries to skip")
+	journalListCmd.Flags().StringVar(&journalSort, "sort", "newest", "Sort order: newest or oldest")
+	journalListCmd.Flags().StringVar(&journalType, "type", "all", "Journals: a name, comma-separated names, or all")
 
 	journalSearchCmd.Flags().IntVar(&journalLimit, "limit", 10, "Maximum number of results")
-	journalSearchCmd.Flags().StringVar(&journalType, "type", "both", "Entry type: project, user, or both")
+	journalSearchCmd.Flags().StringVar(&journalType, "type", "all", "Journals: a name, comma-separated names, or all")
+	journalSearchCmd.Flags().IntVar(&searchDays, "days", 0, "Only search entries from this many days back (default: all)")
+
+	journalDigestCmd.Flags().StringVar(&digestSince, "since", "7d", "Start of the period: a date, timestamp, today, yesterday, or an age like 7d")
+	journalDigestCmd.Flags().StringVar(&digestUntil, "until", "", "End of the period, in the same forms as --since (default: now)")
+	journalDigestCmd.Flags().StringVar(&journalType, "type", "all", "Journals: a name, comma-separated names, or all")
+	journalDigestCmd.Flags().StringVar(&digestFormat, "format", "markdown", "Output format: markdown or json")
+	journalDigestCmd.Flags().IntVar(&digestTop, "top", digest.DefaultTopTerms, "Number of frequent terms to show")
+
+	journalExportCmd.Flags().StringVar(&exportFormat, "format", storage.ExportJSONL, "Export format: jsonl, markdown, or html")
+	journalExportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "File to write (directory for html; default: stdout)")
+	journalExportCmd.Flags().StringVar(&journalType, "type", "all", "Journals: a name, comma-separated names, or all")
+	journalExportCmd.Flags().StringVar(&exportSince, "since", "", "Earliest entries to export: a date, timestamp, today, yesterday, or an age like 2w")
+	journalExportCmd.Flags().StringVar(&exportUntil, "until", "", "Latest entries to export, in the same forms as --since")
+	journalExportCmd.Flags().StringSliceVar(&exportSections, "section", nil, "Only export these sections (repeatable)")
+
+	journalImportCmd.Flags().StringVar(&importFormat, "format", "", "Import format: jsonl, markdown, or csv (default: guessed from the path)")
+	journalImportCmd.Flags().StringVar(&importType, "type", "user", "Journal for entries that record no type, e.g. project or user")
+	journalImportCmd.Flags().StringToStringVar(&importMapping, "map", nil, "CSV mapping from entry field or section to column header, e.g. feelings=Body (repeatable)")
+	journalImportCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "Report what would be imported without writing")
+
+	addMetaFilterFlags(journalListCmd)
+	addMetaFilterFlags(journalExportCmd)
+	addMetaFilterFlags(journalSearchCmd)
+	addMetaFilterFlags(journalDigestCmd)
+
+	journalEmbedCmd.Flags().BoolVar(&embedDryRun, "dry-run", false, "Report missing and outdated sidecars without writing")
+	journalEmbedCmd.Flags().BoolVar(&embedForce, "force", false, "Regenerate every sidecar")
+	journalEmbedCmd.Flags().IntVar(&embedWorkers, "workers", 0, "Number of parallel workers (default: CPU count)")
+}
+
+// addMetaFilterFlags registers the entry metadata filters on a listing command.
+func addMetaFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(&metaTags, "tag", nil, "Only entries with this tag (repeatable; all must match)")
+	cmd.Flags().StringVar(&metaMood, "mood", "", "Only entries with this mood")
+	cmd.Flags().Float64Var(&metaMinValence, "min-valence", 0, "Only entries with valence at least this")
+	cmd.Flags().Float64Var(&metaMaxValence, "max-valence", 0, "Only entries with valence at most this")
+	cmd.Flags().StringVar(&metaAgent, "agent", "", "Only entries written by this agent")
+	cmd.Flags().StringVar(&metaRepo, "repo", "", "Only entries about this repository")
+	cmd.Flags().StringVar(&metaBranch, "branch", "", "Only entries from this git branch")
+	cmd.Flags().StringVar(&metaCommit, "commit", "", "Only entries from this commit (prefix match)")
+	cmd.Flags().StringToStringVar(&metaAttributes, "attr", nil, "Only entries with this key=value attribute (repeatable)")
+}
+
+// metaFilterFromFlags builds a metadata filter from the flags set on cmd.
+func metaFilterFromFlags(cmd *cobra.Command) models.MetaFilter {
+	filter := models.MetaFilter{
+		Tags:       metaTags,
+		Mood:       metaMood,
+		Agent:      metaAgent,
+		Repo:       metaRepo,
+		Branch:     metaBranch,
+		Commit:     metaCommit,
+		Attributes: metaAttributes,
+	}
+	if cmd.Flags().Changed("min-valence") {
+		filter.MinValence = &metaMinValence
+	}
+	if cmd.Flags().Changed("max-valence") {
+		filter.MaxValence = &metaMaxValence
+	}
+	return filter
+}
+
+// configureJournal loads the config into globalConfig, applies its journal
+// section schema, and registers one flag per section on the write and edit
+// commands. It runs before flag parsing, so it always registers flags, from
+// the default sections if the config is broken, and leaves the error for
+// PersistentPreRunE to report. The journals themselves, and the routes naming
+// them, are checked when PersistentPreRunE opens the store.
+func configureJournal() error {
+	cfg, err := config.Load()
+	if err != nil {
+		_ = registerSectionFlags()
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	globalConfig = cfg
+	if err := models.SetSections(cfg.JournalSections()); err != nil {
+		_ = registerSectionFlags()
+		return fmt.Errorf("invalid journal sections in config: %w", err)
+	}
+	return registerSectionFlags()
+}
+
+// registerSectionFlags registers a flag for each section in the schema on the
+// write and edit commands. A section whose flag would shadow an existing one
+// is skipped and reported.
+func registerSectionFlags() error {
+	var conflict error
+	for _, def := range models.Sections() {
+		flag := sectionFlagName(def.Name)
+		if journalWriteCmd.Flags().Lookup(flag) != nil || journalEditCmd.Flags().Lookup(flag) != nil {
+			if conflict == nil {
+				conflict = fmt.Errorf("journal section %q conflicts with the --%s flag", def.Name, flag)
+			}
+			continue
+		}
+		usage := models.SectionTitle(def.Name) + " section content"
+		if def.Required {
+			usage += " (required)"
+		}
+		value := new(string)
+		sectionValues[def.Name] = value
+		journalWriteCmd.Flags().StringVar(value, flag, "", usage)
+		journalEditCmd.Flags().StringVar(value, flag, "", usage)
+	}
+	return conflict
+}
+
+// sectionFlagName converts a section name to its kebab-case flag name.
+func sectionFlagName(section string) string {
+	return strings.ReplaceAll(section, "_", "-")
+}
+
+// sectionFlagList lists every section flag, for error messages.
+func sectionFlagList() string {
+	flags := make([]string, 0, len(sectionValues))
+	for _, name := range models.GetValidSections() {
+		flags = append(flags, "--"+sectionFlagName(name))
+	}
+	return strings.Join(flags, ", ")
 }
 
 func runJournalWrite(cmd *cobra.Command, args []string) error {
+	if journals := globalJournalStore.Journals(); writeType != "" && !slices.Contains(journals, writeType) {
+		return fmt.Errorf("invalid --type %q: must be one of: %s", writeType, strings.Join(journals, ", "))
+	}
+
 	sections := make(map[string]string)
-	if feelings != "" {
-		sections["feelings"] = feelings
+	for _, name := range models.GetValidSections() {
+		if value := sectionValues[name]; value != nil && *value != "" {
+			sections[name] = *value
+		}
+	}
+
+	if writeTemplate != "" {
+		if len(sections) > 0 {
+			return fmt.Errorf("--template cannot be combined with section flags")
+		}
+		filled, tags, err := fillTemplate(writeTemplate)
+		if err != nil {
+			return err
+		}
+		sections = filled
+		metaTags = append(append([]string{}, tags...), metaTags...)
+	}
+
+	if len(sections) == 0 {
+		return fmt.Errorf("at least one section is required (%s, or --template)", sectionFlagList())
+	}
+	for _, name := range models.RequiredSections() {
+		if _, ok := sections[name]; !ok {
+			return fmt.Errorf("section --%s is required", sectionFlagName(name))
+		}
+	}
+
+	meta := models.EntryMeta{
+		Tags:       metaTags,
+		Mood:       metaMood,
+		Agent:      metaAgent,
+		Repo:       metaRepo,
+		Branch:     metaBranch,
+		Commit:     metaCommit,
+		Attributes: metaAttributes,
+	}
+	if cmd.Flags().Changed("valence") {
+		meta.Valence = &metaValence
+	}
+	if meta.Agent == "" && globalSocialStore != nil {
+		if identity, err := globalSocialStore.GetIdentity(); err == nil {
+			meta.Agent = identity
+		}
+	}
+
+	entries, err := globalJournalStore.WriteSections(sections, storage.RouteOptions{
+		Type:   writeType,
+		Linked: writeLinked,
+		Meta:   meta,
+	})
+	for _, entry := range entries {
+		fmt.Printf("Journal entry written [%s]: %s\n", entry.Type, entry.FilePath)
+		fmt.Printf("Sections: %s\n", strings.Join(orderedSectionNames(entry.Sections), ", "))
+		printRedactions(entry.Redactions)
 	}
-	if projectNotes != "" {
-		sections["project_notes"] = projectNotes
+	if err != nil {
+		return fmt.Errorf("failed to write entry: %w", err)
+	}
+	return nil
+}
+
+// printRedactions reports what the redactor found in written text.
+func printRedactions(findings []models.Redaction) {
+	for _, line := range redact.Report(findings) {
+		fmt.Println(line)
 	}
-	if userContext != "" {
-		sections["user_context"] = userContext
+}
+
+// hashTags formats tags as a " #a #b" suffix, or "" without tags.
+func hashTags(tags []string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	return " #" + strings.Join(tags, " #")
+}
+
+// sectionSummary lists an entry's sections for a listing line, or notes that
+// its body is encrypted and could not be read.
+func sectionSummary(entry *models.JournalEntry) string {
+	if entry.Locked {
+		return "encrypted"
 	}
-	if technicalInsights != "" {
-		sections["technical_insights"] = technicalInsights
+	return strings.Join(orderedSectionNames(entry.Sections), ", ")
+}
+
+// orderedSectionNames returns the names in sections in schema order.
+func orderedSectionNames(sections map[string]string) []string {
+	names := make([]string, 0, len(sections))
+	for _, name := range models.GetValidSections() {
+		if _, ok := sections[name]; ok {
+			names = append(names, name)
+		}
 	}
-	if worldKnowledge != "" {
-		sections["world_knowledge"] = worldKnowledge
+	return names
+}
+
+func runJournalEdit(cmd *cobra.Command, args []string) error {
+	// Changed rather than non-empty, so an explicit "" removes the section
+	sections := make(map[string]string)
+	for _, name := range models.GetValidSections() {
+		if cmd.Flags().Changed(sectionFlagName(name)) {
+			sections[name] = *sectionValues[name]
+		}
 	}
 
 	if len(sections) == 0 {
-		return fmt.Errorf("at least one section is required (--feelings, --project-notes, --user-context, --technical-insights, --world-knowledge)")
+		return fmt.Errorf("at least one section flag is required (%s)", sectionFlagList())
 	}
 
-	entry := models.NewJournalEntry(sections, "user")
-	if err := globalJournalStore.WriteEntry(entry); err != nil {
-		return fmt.Errorf("failed to write entry: %w", err)
+	entry, err := globalJournalStore.UpdateEntry(args[0], sections)
+	if err != nil {
+		return fmt.Errorf("failed to update entry: %w", err)
+	}
+
+	fmt.Printf("Journal entry updated: %s\n", entry.FilePath)
+	fmt.Printf("Sections: %s\n", strings.Join(orderedSectionNames(entry.Sections), ", "))
+	printRedactions(entry.Redactions)
+	return nil
+}
+
+func runJournalAppend(cmd *cobra.Command, args []string) error {
+	path, section, text := args[0], args[1], args[2]
+	if !models.IsValidSection(section) {
+		return fmt.Errorf("invalid section %q: must be one of: %s", section, models.ValidSectionList())
+	}
+
+	entry, err := globalJournalStore.AppendToSection(path, section, text)
+	if err != nil {
+		return fmt.Errorf("failed to append to entry: %w", err)
+	}
+
+	fmt.Printf("Appended to %s: %s\n", section, entry.FilePath)
+	printRedactions(entry.Redactions)
+	return nil
+}
+
+func runJournalRm(cmd *cobra.Command, args []string) error {
+	entry, err := globalJournalStore.DeleteEntry(args[0], deleteReason)
+	if err != nil {
+		return fmt.Errorf("failed to delete entry: %w", err)
+	}
+	fmt.Printf("Moved to trash: %s\n", entry.FilePath)
+	return nil
+}
+
+func runJournalTrashList(cmd *cobra.Command, args []string) error {
+	if _, err := models.SelectJournals(journalType, globalJournalStore.Journals()); err != nil {
+		return fmt.Errorf("invalid --type: %w", err)
+	}
+
+	entries, err := globalJournalStore.ListTrash(journalType)
+	if err != nil {
+		return fmt.Errorf("failed to list trash: %w", err)
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("Trash is empty.")
+		return nil
+	}
+
+	for _, entry := range entries {
+		fmt.Printf("%s [%s] deleted %s %s\n",
+			entry.CreatedAt.Format("2006-01-02 15:04:05"),
+			entry.Type,
+			entry.DeletedAt.Format("2006-01-02 15:04:05"),
+			entry.FilePath,
+		)
+		if entry.DeleteReason != "" {
+			fmt.Printf("  Reason: %s\n", entry.DeleteReason)
+		}
+	}
+	return nil
+}
+
+func runJournalTrashRestore(cmd *cobra.Command, args []string) error {
+	entry, err := globalJournalStore.RestoreEntry(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to restore entry: %w", err)
+	}
+	fmt.Printf("Restored: %s\n", entry.FilePath)
+	return nil
+}
+
+func runJournalTrashEmpty(cmd *cobra.Command, args []string) error {
+	if trashOlderThan < 0 {
+		return fmt.Errorf("--older-than must be non-negative, got %d", trashOlderThan)
+	}
+
+	olderThan := globalConfig.GetTrashRetention()
+	switch {
+	case trashAll:
+		olderThan = 0
+	case trashOlderThan > 0:
+		olderThan = time.Duration(trashOlderThan) * 24 * time.Hour
 	}
 
-	sectionNames := make([]string, 0, len(sections))
-	for name := range sections {
-		sectionNames = append(sectionNames, name)
+	removed, err := globalJournalStore.EmptyTrash(olderThan)
+	if err != nil {
+		return fmt.Errorf("failed to empty trash: %w", err)
 	}
-	fmt.Printf("Journal entry written: %s\n", entry.FilePath)
-	fmt.Printf("Sections: %s\n", strings.Join(sectionNames, ", "))
+	fmt.Printf("Removed %d trashed entries\n", removed)
 	return nil
 }
 
 func runJournalSearch(cmd *cobra.Command, args []string) error {
-	query := args[0]
+	queryString := args[0]
 
-	validTypes := map[string]bool{"project": true, "user": true, "both": true, "": true}
-	if !validTypes[journalType] {
-		return fmt.Errorf("invalid --type %q: must be one of: project, user, both", journalType)
+	if _, err := models.SelectJournals(journalType, globalJournalStore.Journals()); err != nil {
+		return fmt.Errorf("invalid --type: %w", err)
 	}
 	if journalLimit < 0 {
 		return fmt.Errorf("--limit must be non-negative, got %d", journalLimit)
 	}
 
-	entries, err := globalJournalStore.ListEntries(journalType, 0, 0)
+	opts := embeddings.SearchOptions{
+		Limit:  journalLimit,
+		Type:   journalType,
+		Filter: metaFilterFromFlags(cmd),
+	}
+	if searchDays > 0 {
+		opts.Since = query.DaysAgo(searchDays, time.Now())
+	}
+	results, err := globalJournalStore.Search(queryString, opts)
 	if err != nil {
-		return fmt.Errorf("failed to list entries: %w", err)
+		return fmt.Errorf("failed to search entries: %w", err)
 	}
 
-	// Include remote entries in search if configured
+	// Include remote entries in search if configured. Remote entries have no
+	// sidecars, so they are ranked lexically and listed after local hits.
 	if globalRemoteClient != nil {
 		remoteEntries, err := globalRemoteClient.ReadJournalEntries(cmd.Context(), 0)
 		if err != nil {
 			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Warning: failed to fetch remote entries: %v\n", err)
 		} else {
-			entries = append(entries, remoteEntries...)
-			sort.Slice(entries, func(i, j int) bool {
-				return entries[i].CreatedAt.After(entries[j].CreatedAt)
+			remoteResults, err := embeddings.Search(nil, remoteEntries, queryString, embeddings.SearchOptions{
+				Limit:  journalLimit,
+				Since:  opts.Since,
+				Filter: opts.Filter,
 			})
-		}
-	}
-
-	queryLower := strings.ToLower(query)
-	count := 0
-
-	for _, entry := range entries {
-		if count >= journalLimit {
-			break
-		}
-
-		matched := false
-		for _, content := range entry.Sections {
-			if strings.Contains(strings.ToLower(content), queryLower) {
-				matched = true
-				break
+			if err != nil {
+				return fmt.Errorf("failed to search remote entries: %w", err)
 			}
+			results = append(results, remoteResults...)
 		}
+	}
 
-		if matched {
-			count++
-			fmt.Printf("--- %s [%s] %s\n", entry.CreatedAt.Format("2006-01-02 15:04:05"), entry.Type, entry.FilePath)
-			for name, content := range entry.Sections {
-				fmt.Printf("  ## %s\n  %s\n", name, truncate(content, 100))
-			}
-			fmt.Println()
-		}
+	if journalLimit > 0 && len(results) > journalLimit {
+		results = results[:journalLimit]
 	}
 
-	if count == 0 {
+	if len(results) == 0 {
 		fmt.Println("No matching entries found.")
+		return nil
+	}
+
+	// Hits from the same thought are printed together under the best-scoring one
+	groups := models.GroupByThought(results, func(r embeddings.SearchResult) *models.JournalEntry { return r.Entry })
+	for _, group := range groups {
+		entry := group[0].Entry
+		fmt.Printf("--- %s [%s] %.3f %s%s\n", entry.CreatedAt.Format("2006-01-02 15:04:05"), entry.Type, group[0].Score, entry.FilePath, hashTags(entry.Tags))
+		for _, sibling := range group[1:] {
+			fmt.Printf("    + [%s] %.3f %s\n", sibling.Entry.Type, sibling.Score, sibling.Entry.FilePath)
+		}
+		fmt.Printf("  %s\n\n", group[0].Snippet)
 	}
 	return nil
 }
 
 func runJournalList(cmd *cobra.Command, args []string) error {
-	validTypes := map[string]bool{"project": true, "user": true, "both": true, "": true}
-	if !validTypes[journalType] {
-		return fmt.Errorf("invalid --type %q: must be one of: project, user, both", journalType)
+	if _, err := models.SelectJournals(journalType, globalJournalStore.Journals()); err != nil {
+		return fmt.Errorf("invalid --type: %w", err)
 	}
 	if journalLimit < 0 {
 		return fmt.Errorf("--limit must be non-negative, got %d", journalLimit)
 	}
+	if journalOffset < 0 {
+		return fmt.Errorf("--offset must be non-negative, got %d", journalOffset)
+	}
+	if journalSort != "newest" && journalSort != "oldest" {
+		return fmt.Errorf("invalid --sort %q: must be newest or oldest", journalSort)
+	}
+
+	now := time.Now()
+	since, until, err := query.ParseBounds(journalSince, journalUntil, now)
+	if err != nil {
+		return err
+	}
+	// --days defaults to 30, but only an explicit --days narrows an --until range
+	if journalSince == "" && journalDays > 0 && (journalUntil == "" || cmd.Flags().Changed("days")) {
+		since = query.DaysAgo(journalDays, now)
+		if !until.IsZero() && !since.Before(until) {
+			return fmt.Errorf("--days (%d) must start before --until (%s)", journalDays, journalUntil)
+		}
+	}
+
+	opts := storage.ListOptions{
+		Type:   journalType,
+		Since:  since,
+		Until:  until,
+		Offset: journalOffset,
+		Limit:  journalLimit,
+		Filter: metaFilterFromFlags(cmd),
+	}
+	if journalSort == "oldest" {
+		opts.Order = storage.OldestFirst
+	}
 
-	entries, err := globalJournalStore.ListEntries(journalType, journalLimit, journalDays)
+	// With a remote to merge, page over the combined list rather than the local one
+	local := opts
+	if globalRemoteClient != nil {
+		local.Offset = 0
+		if local.Limit > 0 {
+			local.Limit = opts.Offset + opts.Limit
+		}
+	}
+	entries, err := globalJournalStore.ListEntries(local)
 	if err != nil {
 		return fmt.Errorf("failed to list entries: %w", err)
 	}
 
 	// Merge remote entries if configured
 	if globalRemoteClient != nil {
-		remoteEntries, err := globalRemoteClient.ReadJournalEntries(cmd.Context(), journalLimit)
+		remoteEntries, err := globalRemoteClient.ReadJournalEntries(cmd.Context(), local.Limit)
 		if err != nil {
 			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Warning: failed to fetch remote entries: %v\n", err)
 		} else {
-			entries = append(entries, remoteEntries...)
-			sort.Slice(entries, func(i, j int) bool {
-				return entries[i].CreatedAt.After(entries[j].CreatedAt)
-			})
-			if journalLimit > 0 && len(entries) > journalLimit {
-				entries = entries[:journalLimit]
+			for _, entry := range remoteEntries {
+				if inListRange(entry, opts) {
+					entries = append(entries, entry)
+				}
+			}
+		}
+		sort.SliceStable(entries, func(i, j int) bool {
+			if opts.Order == storage.OldestFirst {
+				return entries[i].CreatedAt.Before(entries[j].CreatedAt)
 			}
+			return entries[i].CreatedAt.After(entries[j].CreatedAt)
+		})
+		if opts.Offset >= len(entries) {
+			entries = nil
+		} else {
+			entries = entries[opts.Offset:]
+		}
+		if opts.Limit > 0 && len(entries) > opts.Limit {
+			entries = entries[:opts.Limit]
 		}
 	}
 
@@ -213,48 +851,391 @@ func runJournalList(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	for _, entry := range entries {
-		sectionNames := make([]string, 0, len(entry.Sections))
-		for name := range entry.Sections {
-			sectionNames = append(sectionNames, name)
+	// Entries split from one write share a thought ID and are listed together
+	for _, group := range models.GroupByThought(entries, func(e *models.JournalEntry) *models.JournalEntry { return e }) {
+		for i, entry := range group {
+			prefix := entry.CreatedAt.Format("2006-01-02 15:04:05")
+			if i > 0 {
+				prefix = "  +"
+			}
+			fmt.Printf("%s [%s] (%s) %s%s\n",
+				prefix,
+				entry.Type,
+				sectionSummary(entry),
+				entry.FilePath,
+				hashTags(entry.Tags),
+			)
 		}
-		fmt.Printf("%s [%s] (%s) %s\n",
-			entry.CreatedAt.Format("2006-01-02 15:04:05"),
-			entry.Type,
-			strings.Join(sectionNames, ", "),
-			entry.FilePath,
-		)
 	}
 	return nil
 }
 
+func runJournalDigest(cmd *cobra.Command, args []string) error {
+	if _, err := models.SelectJournals(journalType, globalJournalStore.Journals()); err != nil {
+		return fmt.Errorf("invalid --type: %w", err)
+	}
+	if digestFormat != "markdown" && digestFormat != "json" {
+		return fmt.Errorf("invalid --format %q: must be markdown or json", digestFormat)
+	}
+
+	since, until, err := query.ParseBounds(digestSince, digestUntil, time.Now())
+	if err != nil {
+		return err
+	}
+	entries, err := globalJournalStore.ListEntries(storage.ListOptions{
+		Type:   journalType,
+		Since:  since,
+		Until:  until,
+		Order:  storage.OldestFirst,
+		Filter: metaFilterFromFlags(cmd),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list entries: %w", err)
+	}
+
+	d := digest.Build(entries, digest.Options{Since: since, Until: until, TopTerms: digestTop})
+	if digestFormat == "json" {
+		data, err := d.JSON()
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	fmt.Print(d.Markdown())
+	return nil
+}
+
+func runJournalExport(cmd *cobra.Command, args []string) error {
+	if _, err := models.SelectJournals(journalType, globalJournalStore.Journals()); err != nil {
+		return fmt.Errorf("invalid --type: %w", err)
+	}
+	switch exportFormat {
+	case storage.ExportJSONL, storage.ExportMarkdown:
+	case storage.ExportHTML:
+		if exportOutput == "" {
+			return fmt.Errorf("--format html needs --output to name a directory")
+		}
+	default:
+		return fmt.Errorf("invalid --format %q: must be jsonl, markdown, or html", exportFormat)
+	}
+	for _, name := range exportSections {
+		if !models.IsValidSection(name) {
+			return fmt.Errorf("unknown section %q: valid sections are %s", name, models.ValidSectionList())
+		}
+	}
+
+	since, until, err := query.ParseBounds(exportSince, exportUntil, time.Now())
+	if err != nil {
+		return err
+	}
+	entries, err := globalJournalStore.ListEntries(storage.ListOptions{
+		Type:   journalType,
+		Since:  since,
+		Until:  until,
+		Order:  storage.OldestFirst,
+		Filter: metaFilterFromFlags(cmd),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list entries: %w", err)
+	}
+	entries = storage.SelectSections(entries, exportSections)
+
+	if exportFormat == storage.ExportHTML {
+		if err := storage.WriteHTMLSite(exportOutput, entries); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Exported %d entries to %s\n", len(entries), filepath.Join(exportOutput, "index.html"))
+		return nil
+	}
+
+	out := cmd.OutOrStdout()
+	if exportOutput != "" {
+		f, err := os.Create(exportOutput)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", exportOutput, err)
+		}
+		defer func() { _ = f.Close() }()
+		out = f
+	}
+	if exportFormat == storage.ExportJSONL {
+		err = storage.WriteJSONL(out, entries)
+	} else {
+		err = storage.WriteMarkdown(out, entries)
+	}
+	if err != nil {
+		return err
+	}
+	if f, ok := out.(*os.File); ok && exportOutput != "" {
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("failed to write %s: %w", exportOutput, err)
+		}
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Exported %d entries to %s\n", len(entries), exportOutput)
+	}
+	return nil
+}
+
+func runJournalImport(cmd *cobra.Command, args []string) error {
+	path := args[0]
+	format := importFormat
+	if format == "" {
+		format = storage.DetectImportFormat(path)
+	}
+
+	var importer storage.Importer
+	switch format {
+	case storage.ImportJSONL:
+		importer = storage.JSONLImporter{}
+	case storage.ImportMarkdown:
+		importer = storage.MarkdownImporter{}
+	case storage.ImportCSV:
+		importer = storage.CSVImporter{Mapping: importMapping}
+	default:
+		return fmt.Errorf("invalid --format %q: must be jsonl, markdown, or csv", format)
+	}
+	if len(importMapping) > 0 && format != storage.ImportCSV {
+		return fmt.Errorf("--map only applies to csv imports")
+	}
+
+	entries, err := importer.Read(path)
+	if err != nil {
+		return err
+	}
+	report, err := globalJournalStore.ImportEntries(entries, storage.ImportOptions{
+		DefaultType: importType,
+		DryRun:      importDryRun,
+	})
+	if err != nil {
+		return err
+	}
+
+	verb := "Imported"
+	if importDryRun {
+		verb = "Would import"
+		for _, entry := range report.Imported {
+			fmt.Printf("%s [%s] (%s) %s\n",
+				entry.CreatedAt.Format("2006-01-02 15:04:05"),
+				entry.Type,
+				strings.Join(orderedSectionNames(entry.Sections), ", "),
+				entry.ID,
+			)
+		}
+	}
+	fmt.Printf("%s %d of %d entries from %s (%s)\n", verb, len(report.Imported), report.Read, path, format)
+	if report.DuplicateID+report.DuplicateContent > 0 {
+		fmt.Printf("Skipped %d duplicates (%d by ID, %d by content)\n",
+			report.DuplicateID+report.DuplicateContent, report.DuplicateID, report.DuplicateContent)
+	}
+	if len(report.Invalid) > 0 {
+		fmt.Printf("Skipped %d invalid entries:\n", len(report.Invalid))
+		for _, problem := range report.Invalid {
+			fmt.Printf("  %s\n", problem)
+		}
+	}
+	if len(report.Redacted) > 0 {
+		fmt.Printf("Redacted %d entries:\n", len(report.Redacted))
+		for _, line := range report.Redacted {
+			fmt.Printf("  %s\n", line)
+		}
+	}
+	return nil
+}
+
+// inListRange reports whether a remote entry falls within the time range and
+// metadata filter of opts, as ListEntries applies them to local entries.
+func inListRange(entry *models.JournalEntry, opts storage.ListOptions) bool {
+	if !opts.Since.IsZero() && entry.CreatedAt.Before(opts.Since) {
+		return false
+	}
+	if !opts.Until.IsZero() && !entry.CreatedAt.Before(opts.Until) {
+		return false
+	}
+	return opts.Filter.Matches(entry.EntryMeta)
+}
+
 func runJournalRead(cmd *cobra.Command, args []string) error {
 	path := args[0]
 
+	if readThought {
+		entries, err := globalJournalStore.ReadThought(path)
+		if err != nil {
+			return fmt.Errorf("failed to read thought: %w", err)
+		}
+		for i, entry := range entries {
+			if i > 0 {
+				fmt.Println("---")
+			}
+			fmt.Printf("Entry: %s\n", entry.FilePath)
+			printJournalEntry(entry)
+		}
+		return nil
+	}
+
 	entry, err := globalJournalStore.ReadEntry(path)
 	if err != nil {
 		return fmt.Errorf("failed to read entry: %w", err)
 	}
+	printJournalEntry(entry)
+	return nil
+}
 
+// printJournalEntry prints an entry's metadata followed by its sections.
+func printJournalEntry(entry *models.JournalEntry) {
 	fmt.Printf("Date: %s\n", entry.CreatedAt.Format("2006-01-02 15:04:05"))
+	if !entry.UpdatedAt.IsZero() {
+		fmt.Printf("Updated: %s\n", entry.UpdatedAt.Format("2006-01-02 15:04:05"))
+	}
 	fmt.Printf("Type: %s\n", entry.Type)
+	if entry.ThoughtID != uuid.Nil {
+		fmt.Printf("Thought: %s\n", entry.ThoughtID)
+	}
+	for _, id := range entry.LinkedIDs {
+		fmt.Printf("Linked: %s\n", id)
+	}
+	for _, field := range entry.Fields() {
+		fmt.Printf("%s: %s\n", field[0], field[1])
+	}
 	fmt.Println()
 
-	for _, name := range models.GetValidSections() {
-		content, ok := entry.Sections[name]
-		if !ok || content == "" {
-			continue
+	// Sections the schema no longer declares are printed after the rest
+	for _, name := range models.SectionNames(entry.Sections) {
+		if content := entry.Sections[name]; content != "" {
+			fmt.Printf("## %s\n%s\n\n", name, content)
 		}
-		fmt.Printf("## %s\n%s\n\n", name, content)
+	}
+}
+
+func runJournalReindex(cmd *cobra.Command, args []string) error {
+	count, err := globalJournalStore.Reindex()
+	if err != nil {
+		return fmt.Errorf("failed to reindex journal: %w", err)
+	}
+	fmt.Printf("Indexed %d entries\n", count)
+	return nil
+}
+
+func runJournalEmbed(cmd *cobra.Command, args []string) error {
+	stderr := cmd.ErrOrStderr()
+	report, err := globalJournalStore.BackfillEmbeddings(storage.EmbedOptions{
+		Workers: embedWorkers,
+		DryRun:  embedDryRun,
+		Force:   embedForce,
+		Progress: func(done, total int, path string) {
+			_, _ = fmt.Fprintf(stderr, "\rEmbedding %d/%d", done, total)
+			if done == total {
+				_, _ = fmt.Fprintln(stderr)
+			}
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to embed entries: %w", err)
+	}
+
+	fmt.Printf("Scanned %d entries: %d missing, %d outdated\n", report.Scanned, report.Missing, report.Outdated)
+	if embedDryRun {
+		fmt.Println("Dry run: no sidecars written.")
+		return nil
+	}
+	fmt.Printf("Wrote %d sidecars", report.Written)
+	if report.Failed > 0 {
+		fmt.Printf(", %d failed", report.Failed)
+	}
+	fmt.Println()
+	return nil
+}
+
+func runJournalEncryptAll(cmd *cobra.Command, args []string) error {
+	report, err := globalJournalStore.EncryptAll()
+	if err != nil {
+		return fmt.Errorf("failed to encrypt entries: %w", err)
+	}
+	fmt.Printf("Encrypted %d of %d entries\n", report.Changed, report.Scanned)
+	if !globalConfig.Journal.Encrypt {
+		fmt.Println("New entries are not encrypted; set journal.encrypt in config to encrypt them.")
+	}
+	return nil
+}
+
+func runJournalDecryptAll(cmd *cobra.Command, args []string) error {
+	report, err := globalJournalStore.DecryptAll()
+	if err != nil {
+		return fmt.Errorf("failed to decrypt entries: %w", err)
+	}
+	fmt.Printf("Decrypted %d of %d entries\n", report.Changed, report.Scanned)
+	if globalConfig.Journal.Encrypt {
+		fmt.Println("New entries are still encrypted; unset journal.encrypt in config to stop.")
+	}
+	return nil
+}
+
+func runJournalGC(cmd *cobra.Command, args []string) error {
+	rules := retentionRules()
+	if len(rules) == 0 {
+		fmt.Println("No retention rules configured; add them under journal.retention.")
+		return nil
+	}
+	report, err := globalJournalStore.GC(rules, time.Now(), gcDryRun)
+	if err != nil {
+		return fmt.Errorf("failed to apply retention rules: %w", err)
+	}
+	if gcDryRun {
+		fmt.Println("Dry run: would have " + gcSummary(report))
+	} else {
+		fmt.Println("Retention applied: " + gcSummary(report))
+	}
+	if report.Skipped > 0 {
+		fmt.Printf("Skipped %d encrypted entries; set the journal passphrase to apply section rules to them\n", report.Skipped)
 	}
 	return nil
 }
 
-// truncate shortens a string to maxLen runes, adding "..." if truncated.
-func truncate(s string, maxLen int) string {
-	runes := []rune(s)
-	if len(runes) <= maxLen {
-		return s
+// retentionRules converts the configured retention rules for the store.
+func retentionRules() []storage.RetentionRule {
+	var rules []storage.RetentionRule
+	for _, rc := range globalConfig.Journal.Retention {
+		rules = append(rules, storage.RetentionRule{
+			Type:      rc.Type,
+			Section:   rc.Section,
+			OlderThan: rc.OlderThan,
+			Action:    storage.RetentionAction(rc.Action),
+		})
+	}
+	return rules
+}
+
+// gcSummary describes what a GC run changed in one line.
+func gcSummary(report *storage.GCReport) string {
+	return fmt.Sprintf("deleted %d entries and %d sections, archived %d entries from %d days",
+		report.DeletedEntries, report.DeletedSections, report.ArchivedEntries, report.ArchivedDays)
+}
+
+func runJournalDoctor(cmd *cobra.Command, args []string) error {
+	exposures := globalJournalStore.CheckGitExposure()
+	if len(exposures) == 0 {
+		fmt.Println("OK: no journal root is inside a git work tree.")
+		return nil
 	}
-	return string(runes[:maxLen]) + "..."
+
+	tracked := 0
+	for _, exp := range exposures {
+		fmt.Printf("%s is inside the git work tree %s\n", exp.Root, exp.Repo)
+		if exp.Ignored {
+			fmt.Println("  OK: ignored by git")
+		} else {
+			fmt.Printf("  Not ignored: add %s to .git/info/exclude or .gitignore\n", exp.Pattern)
+		}
+		if len(exp.Tracked) == 0 {
+			continue
+		}
+		tracked += len(exp.Tracked)
+		fmt.Printf("  %d files tracked by git:\n", len(exp.Tracked))
+		for _, path := range exp.Tracked {
+			fmt.Printf("    %s\n", path)
+		}
+		fmt.Printf("  Untrack them with: git -C %s rm -r --cached %s\n", exp.Repo, strings.Trim(exp.Pattern, "/"))
+	}
+	if tracked > 0 {
+		return fmt.Errorf("%d journal files are tracked by git", tracked)
+	}
+	return nil
 }
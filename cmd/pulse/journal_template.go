@@ -0,0 +1,134 @@
+// ABOUTME: Journal templates on the CLI: listing them and filling one in with $EDITOR for journal write.
+// ABOUTME: Templates live in ~/.config/pulse/templates/ alongside the built-in standup, postmortem, and learning.
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/2389-research/pulse/internal/config"
+	"github.com/2389-research/pulse/internal/models"
+	"github.com/2389-research/pulse/internal/templates"
+)
+
+var journalTemplatesCmd = &cobra.Command{
+	Use:   "templates",
+	Short: "List journal templates",
+	Long: `List the templates "journal write --template" can start from.
+
+Besides the built-in standup, postmortem and learning templates, every <name>.md
+file in ~/.config/pulse/templates/ is a template; one named like a built-in
+replaces it. A template has a "## Title" heading per section, each followed by
+guiding questions in <!-- --> comments, and optional frontmatter with a
+description and tags for the entries written from it.`,
+	Args: cobra.NoArgs,
+	RunE: runJournalTemplates,
+}
+
+var writeTemplate string
+
+func init() {
+	journalCmd.AddCommand(journalTemplatesCmd)
+	journalWriteCmd.Flags().StringVar(&writeTemplate, "template", "", "Fill in a template in $EDITOR instead of passing sections as flags, e.g. standup")
+}
+
+// loadTemplates returns the built-in templates merged with those in the
+// config directory.
+func loadTemplates() ([]templates.Template, error) {
+	dir, err := config.TemplatesDir()
+	if err != nil {
+		return nil, err
+	}
+	return templates.Load(dir)
+}
+
+func runJournalTemplates(cmd *cobra.Command, args []string) error {
+	all, err := loadTemplates()
+	if err != nil {
+		return err
+	}
+	for _, t := range all {
+		source := "built-in"
+		if t.Path != "" {
+			source = t.Path
+		}
+		fmt.Printf("%-12s %s\n", t.Name, t.Description)
+		fmt.Printf("%-12s sections: %s (%s)\n", "", strings.Join(t.Sections(), ", "), source)
+		if err := t.Validate(); err != nil {
+			fmt.Printf("%-12s warning: %v\n", "", err)
+		}
+	}
+	return nil
+}
+
+// fillTemplate opens the named template in the user's editor and returns the
+// sections written and the template's tags. The file is kept, and its path
+// reported, if the result cannot be used.
+func fillTemplate(name string) (map[string]string, []string, error) {
+	all, err := loadTemplates()
+	if err != nil {
+		return nil, nil, err
+	}
+	t, err := templates.Find(all, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := t.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	f, err := os.CreateTemp("", "pulse-"+t.Name+"-*.md")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create template file: %w", err)
+	}
+	path := f.Name()
+	_, err = f.WriteString(t.Body)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to write template file: %w", err)
+	}
+
+	if err := runEditor(path); err != nil {
+		return nil, nil, fmt.Errorf("%w (template kept at %s)", err, path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read template file: %w", err)
+	}
+	sections := templates.Fill(string(data))
+	if len(sections) == 0 {
+		_ = os.Remove(path)
+		return nil, nil, fmt.Errorf("aborting: the template was left empty")
+	}
+	for name := range sections {
+		if !models.IsValidSection(name) {
+			return nil, nil, fmt.Errorf("unknown section %q: valid sections are %s (template kept at %s)", name, models.ValidSectionList(), path)
+		}
+	}
+	_ = os.Remove(path)
+	return sections, t.Tags, nil
+}
+
+// runEditor opens path in $VISUAL or $EDITOR, falling back to vi. The
+// variable may carry arguments, e.g. "code --wait".
+func runEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	cmd := exec.Command("sh", "-c", editor+` "$1"`, "sh", path)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("editor %q failed: %w", editor, err)
+	}
+	return nil
+}
@@ -16,6 +16,7 @@ var (
 
 func main() {
 	rootCmd.Version = fmt.Sprintf("%s (commit: %s, built: %s)", version, commit, date)
+	globalConfigErr = configureJournal()
 	if err := rootCmd.Execute(); err != nil {
 		os.Exit(1)
 	}
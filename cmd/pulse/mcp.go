@@ -4,13 +4,16 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	mcppkg "github.com/2389-research/pulse/internal/mcp"
+	"github.com/2389-research/pulse/internal/templates"
 )
 
 var mcpCmd = &cobra.Command{
@@ -36,6 +39,35 @@ func runMCP(cmd *cobra.Command, args []string) error {
 		opts = append(opts, mcppkg.WithRemoteClient(globalRemoteClient))
 	}
 
+	// stdout carries the protocol, so retention results go to stderr
+	if globalConfig.Journal.GCOnStart {
+		if rules := retentionRules(); len(rules) > 0 {
+			report, err := globalJournalStore.GC(rules, time.Now(), false)
+			if err != nil {
+				_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Warning: journal retention failed: %v\n", err)
+			} else {
+				_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Journal retention: %s\n", gcSummary(report))
+			}
+		}
+	}
+
+	// A template that does not fit the section schema is left out rather than
+	// stopping the server
+	all, err := loadTemplates()
+	if err != nil {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %v; serving built-in templates only\n", err)
+		all = templates.Builtin()
+	}
+	var usable []templates.Template
+	for _, t := range all {
+		if err := t.Validate(); err != nil {
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %v; skipping it\n", err)
+			continue
+		}
+		usable = append(usable, t)
+	}
+	opts = append(opts, mcppkg.WithTemplates(usable))
+
 	server, err := mcppkg.NewServer(globalJournalStore, globalSocialStore, version, opts...)
 	if err != nil {
 		return err
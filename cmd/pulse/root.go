@@ -4,14 +4,21 @@ package main
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 
 	"github.com/2389-research/pulse/internal/config"
+	"github.com/2389-research/pulse/internal/embeddings"
+	"github.com/2389-research/pulse/internal/redact"
 	"github.com/2389-research/pulse/internal/storage"
 )
 
-var globalConfig *config.Config //nolint:unused // reserved for future use
+// globalConfig is loaded once by configureJournal before flag parsing.
+// globalConfigErr holds why it could not be loaded or applied; commands
+// other than help and setup report it from PersistentPreRunE.
+var globalConfig *config.Config
+var globalConfigErr error
 var globalJournalStore storage.JournalStore
 var globalSocialStore storage.SocialStore
 var globalRemoteClient *storage.RemoteClient
@@ -36,11 +43,10 @@ Local-first with optional remote sync.`,
 			return nil
 		}
 
-		cfg, err := config.Load()
-		if err != nil {
-			return fmt.Errorf("failed to load config: %w", err)
+		if globalConfigErr != nil {
+			return globalConfigErr
 		}
-		globalConfig = cfg
+		cfg := globalConfig
 
 		projectPath, err := cfg.GetJournalProjectPath()
 		if err != nil {
@@ -50,7 +56,36 @@ Local-first with optional remote sync.`,
 		if err != nil {
 			return fmt.Errorf("failed to resolve journal user path: %w", err)
 		}
-		journalStore, err := storage.NewJournalMDStore(projectPath, userPath)
+		journalPaths, err := cfg.GetJournalPaths()
+		if err != nil {
+			return fmt.Errorf("failed to resolve journal paths: %w", err)
+		}
+		var patterns []redact.Pattern
+		for _, p := range cfg.Redaction.Patterns {
+			patterns = append(patterns, redact.Pattern{Name: p.Name, Regex: p.Regex})
+		}
+		redactor, err := redact.New(redact.Mode(cfg.Redaction.Mode), patterns)
+		if err != nil {
+			return fmt.Errorf("failed to configure redaction: %w", err)
+		}
+
+		journalOpts := []storage.JournalOption{storage.WithJournals(journalPaths), storage.WithRedactor(redactor)}
+		embedder, err := embeddings.New(cfg.Journal.Embedder)
+		if err != nil {
+			return fmt.Errorf("failed to create embedder: %w", err)
+		}
+		if embedder != nil {
+			journalOpts = append(journalOpts, storage.WithEmbedder(embedder))
+		}
+		passphrase, err := cfg.GetJournalPassphrase()
+		if err != nil {
+			return fmt.Errorf("failed to load journal passphrase: %w", err)
+		}
+		journalOpts = append(journalOpts, storage.WithPassphrase(passphrase), storage.WithEncryption(cfg.Journal.Encrypt))
+		journalOpts = append(journalOpts, storage.WithWarnings(func(msg string) {
+			_, _ = fmt.Fprintf(os.Stderr, "Warning: %s\n", msg)
+		}))
+		journalStore, err := storage.NewJournalMDStore(projectPath, userPath, journalOpts...)
 		if err != nil {
 			return fmt.Errorf("failed to open journal store: %w", err)
 		}
@@ -60,14 +95,14 @@ Local-first with optional remote sync.`,
 		if err != nil {
 			return fmt.Errorf("failed to resolve social data dir: %w", err)
 		}
-		socialStore, err := storage.NewSocialMDStore(socialDataDir)
+		socialStore, err := storage.NewSocialMDStore(socialDataDir, storage.WithPostRedactor(redactor))
 		if err != nil {
 			return fmt.Errorf("failed to open social store: %w", err)
 		}
 		globalSocialStore = socialStore
 
 		if cfg.HasRemote() {
-			globalRemoteClient = storage.NewRemoteClient(cfg.Social.APIURL, cfg.Social.APIKey, cfg.Social.TeamID)
+			globalRemoteClient = storage.NewRemoteClient(cfg.Social.APIURL, cfg.Social.APIKey, cfg.Social.TeamID, storage.WithPayloadRedactor(redactor))
 		}
 
 		return nil
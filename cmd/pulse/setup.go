@@ -24,9 +24,11 @@ func init() {
 }
 
 func runSetup(cmd *cobra.Command, args []string) error {
-	cfg, err := config.Load()
-	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+	// Setup only needs the social credentials, so it runs with a config whose
+	// journal settings are invalid; it cannot run without a config at all.
+	cfg := globalConfig
+	if cfg == nil {
+		return globalConfigErr
 	}
 
 	model := tui.NewSetupModel(
@@ -108,6 +108,7 @@ func runSocialPost(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Printf("Post created (ID: %s)\n", post.ID.String()[:8])
+	printRedactions(post.Redactions)
 	return nil
 }
 
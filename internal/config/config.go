@@ -1,5 +1,5 @@
 // ABOUTME: Configuration management for pulse with YAML config loading.
-// ABOUTME: Handles social API settings, journal paths, and ~ expansion.
+// ABOUTME: Handles social API settings, journal paths and sections, and ~ expansion.
 package config
 
 import (
@@ -7,14 +7,18 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/2389-research/pulse/internal/models"
 )
 
 // Config stores pulse configuration loaded from ~/.config/pulse/config.yaml.
 type Config struct {
-	Social  SocialConfig  `yaml:"social"`
-	Journal JournalConfig `yaml:"journal"`
+	Social    SocialConfig    `yaml:"social"`
+	Journal   JournalConfig   `yaml:"journal"`
+	Redaction RedactionConfig `yaml:"redaction,omitempty"`
 }
 
 // SocialConfig holds remote social media API settings.
@@ -24,10 +28,96 @@ type SocialConfig struct {
 	APIURL string `yaml:"api_url"`
 }
 
-// JournalConfig holds optional path overrides for journal storage.
+// JournalConfig holds optional path overrides and search settings for journal storage.
 type JournalConfig struct {
 	ProjectPath string `yaml:"project_path"`
 	UserPath    string `yaml:"user_path"`
+	Embedder    string `yaml:"embedder,omitempty"` // "hash" (default) or "none"
+
+	// Journals declares named journals beyond project and user, such as
+	// work or oncall, mapping each name to its root directory.
+	Journals map[string]string `yaml:"journals,omitempty"`
+
+	// Sections adds journal sections or overrides the built-in ones by name.
+	Sections []SectionConfig `yaml:"sections,omitempty"`
+
+	// TrashRetentionDays is how long deleted entries stay in .trash/ before
+	// `pulse journal trash empty` purges them (default 30).
+	TrashRetentionDays int `yaml:"trash_retention_days,omitempty"`
+
+	// Encrypt stores the bodies of new entries encrypted with the journal
+	// passphrase, taken from PULSE_JOURNAL_PASSPHRASE or PassphraseFile.
+	// Frontmatter stays readable so listing and filters still work.
+	Encrypt        bool   `yaml:"encrypt,omitempty"`
+	PassphraseFile string `yaml:"passphrase_file,omitempty"`
+
+	// Retention deletes or archives old entries when `pulse journal gc` runs,
+	// and when the MCP server starts if GCOnStart is set.
+	Retention []RetentionConfig `yaml:"retention,omitempty"`
+	GCOnStart bool              `yaml:"gc_on_start,omitempty"`
+}
+
+// RetentionConfig declares a retention rule, e.g. delete feelings older than
+// 180d, or archive project entries older than 1y.
+type RetentionConfig struct {
+	Type      string `yaml:"type,omitempty"`    // journal name or comma-separated names; all journals if empty
+	Section   string `yaml:"section,omitempty"` // delete rules only: remove just this section
+	OlderThan string `yaml:"older_than"`        // an age like 180d, 12w, 6m, or 1y
+	Action    string `yaml:"action"`            // "delete" or "archive"
+}
+
+// RedactionConfig controls scanning of journal entries, posts, and remote
+// payloads for secrets and personal data.
+type RedactionConfig struct {
+	Mode     string             `yaml:"mode,omitempty"` // "off" (default), "warn", "mask", or "block"
+	Patterns []RedactionPattern `yaml:"patterns,omitempty"`
+}
+
+// RedactionPattern declares a custom detector by name and regular expression.
+type RedactionPattern struct {
+	Name  string `yaml:"name"`
+	Regex string `yaml:"regex"`
+}
+
+// SectionConfig declares a journal section.
+type SectionConfig struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description,omitempty"`
+	Route       string `yaml:"route,omitempty"` // journal name: "user" (default), "project", or a configured one
+	Sync        string `yaml:"sync,omitempty"`  // "always" (default), "opt-in", or "never"
+	Required    bool   `yaml:"required,omitempty"`
+}
+
+// JournalSections returns the built-in journal sections merged with those
+// declared in config. A declared section with a built-in name overrides its
+// description, route, sync policy, and required flag; new names are appended
+// in order.
+func (c *Config) JournalSections() []models.SectionDef {
+	defs := models.DefaultSections()
+	for _, sc := range c.Journal.Sections {
+		idx := -1
+		for i, def := range defs {
+			if def.Name == sc.Name {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			defs = append(defs, models.SectionDef{Name: sc.Name, Route: "user"})
+			idx = len(defs) - 1
+		}
+		if sc.Description != "" {
+			defs[idx].Description = sc.Description
+		}
+		if sc.Route != "" {
+			defs[idx].Route = sc.Route
+		}
+		if sc.Sync != "" {
+			defs[idx].Sync = sc.Sync
+		}
+		defs[idx].Required = sc.Required
+	}
+	return defs
 }
 
 // HasRemote returns true if remote social posting is configured.
@@ -59,6 +149,59 @@ func (c *Config) GetJournalUserPath() (string, error) {
 	return filepath.Join(home, ".private-journal"), nil
 }
 
+// GetJournalPaths returns the root directory of each journal declared beyond
+// project and user, with ~ expanded.
+func (c *Config) GetJournalPaths() (map[string]string, error) {
+	paths := make(map[string]string, len(c.Journal.Journals))
+	for name, path := range c.Journal.Journals {
+		if path == "" {
+			return nil, fmt.Errorf("journal %q needs a path", name)
+		}
+		expanded, err := ExpandPath(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to expand path of journal %q: %w", name, err)
+		}
+		paths[name] = expanded
+	}
+	return paths, nil
+}
+
+// DefaultTrashRetentionDays is used when trash_retention_days is unset.
+const DefaultTrashRetentionDays = 30
+
+// GetTrashRetention returns how long deleted journal entries are kept in the trash.
+func (c *Config) GetTrashRetention() time.Duration {
+	days := c.Journal.TrashRetentionDays
+	if days <= 0 {
+		days = DefaultTrashRetentionDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// PassphraseEnv names the environment variable holding the journal passphrase.
+const PassphraseEnv = "PULSE_JOURNAL_PASSPHRASE"
+
+// GetJournalPassphrase returns the passphrase for encrypted journal entries
+// from PULSE_JOURNAL_PASSPHRASE or, failing that, passphrase_file. Returns ""
+// when neither is set.
+func (c *Config) GetJournalPassphrase() (string, error) {
+	if v := os.Getenv(PassphraseEnv); v != "" {
+		return v, nil
+	}
+	if c.Journal.PassphraseFile == "" {
+		return "", nil
+	}
+	path, err := ExpandPath(c.Journal.PassphraseFile)
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read passphrase file: %w", err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
 // GetSocialDataDir returns the social media data directory.
 func (c *Config) GetSocialDataDir() (string, error) {
 	return SocialDataDir()
@@ -79,15 +222,34 @@ func SocialDataDir() (string, error) {
 
 // GetConfigPath returns the config file path.
 func GetConfigPath() (string, error) {
-	configDir := os.Getenv("XDG_CONFIG_HOME")
-	if configDir == "" {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.yaml"), nil
+}
+
+// TemplatesDir returns the directory holding journal templates, one
+// <name>.md file each: ~/.config/pulse/templates/.
+func TemplatesDir() (string, error) {
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "templates"), nil
+}
+
+// configDir returns pulse's config directory, honoring XDG_CONFIG_HOME.
+func configDir() (string, error) {
+	base := os.Getenv("XDG_CONFIG_HOME")
+	if base == "" {
 		home, err := os.UserHomeDir()
 		if err != nil {
 			return "", fmt.Errorf("failed to get home directory: %w", err)
 		}
-		configDir = filepath.Join(home, ".config")
+		base = filepath.Join(home, ".config")
 	}
-	return filepath.Join(configDir, "pulse", "config.yaml"), nil
+	return filepath.Join(base, "pulse"), nil
 }
 
 // ExpandPath expands a leading ~ to the user's home directory.
@@ -6,6 +6,9 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
+
+	"github.com/2389-research/pulse/internal/models"
 )
 
 func TestExpandPath(t *testing.T) {
@@ -72,6 +75,11 @@ func TestLoadYAMLConfig(t *testing.T) {
 journal:
   project_path: "~/my-journal"
   user_path: "~/global-journal"
+  retention:
+    - type: user
+      section: feelings
+      older_than: 180d
+      action: delete
 `
 	configPath := filepath.Join(configDir, "config.yaml")
 	if err := os.WriteFile(configPath, []byte(configData), 0600); err != nil {
@@ -95,6 +103,9 @@ journal:
 	if !cfg.HasRemote() {
 		t.Error("expected HasRemote() to be true")
 	}
+	if len(cfg.Journal.Retention) != 1 || cfg.Journal.Retention[0].Type != "user" || cfg.Journal.Retention[0].Section != "feelings" {
+		t.Errorf("expected one user retention rule, got %+v", cfg.Journal.Retention)
+	}
 
 	home, _ := os.UserHomeDir()
 	expectedProject := filepath.Join(home, "my-journal")
@@ -256,3 +267,116 @@ func TestDefaultPaths(t *testing.T) {
 		t.Errorf("GetJournalProjectPath() = %q, want %q", projectPath, expectedProject)
 	}
 }
+
+func TestGetJournalPaths(t *testing.T) {
+	home, _ := os.UserHomeDir()
+	cfg := &Config{Journal: JournalConfig{Journals: map[string]string{
+		"work":   "~/journals/work",
+		"oncall": "/srv/oncall",
+	}}}
+
+	paths, err := cfg.GetJournalPaths()
+	if err != nil {
+		t.Fatalf("GetJournalPaths() error: %v", err)
+	}
+	if paths["work"] != filepath.Join(home, "journals", "work") || paths["oncall"] != "/srv/oncall" {
+		t.Errorf("GetJournalPaths() = %v", paths)
+	}
+
+	cfg.Journal.Journals["empty"] = ""
+	if _, err := cfg.GetJournalPaths(); err == nil {
+		t.Error("expected a journal without a path to be rejected")
+	}
+}
+
+func TestGetTrashRetention(t *testing.T) {
+	cfg := &Config{}
+	if got := cfg.GetTrashRetention(); got != DefaultTrashRetentionDays*24*time.Hour {
+		t.Errorf("default retention = %v", got)
+	}
+	cfg.Journal.TrashRetentionDays = 7
+	if got := cfg.GetTrashRetention(); got != 7*24*time.Hour {
+		t.Errorf("configured retention = %v, want 168h", got)
+	}
+}
+
+func TestGetJournalPassphrase(t *testing.T) {
+	t.Setenv(PassphraseEnv, "")
+	cfg := &Config{}
+	if got, err := cfg.GetJournalPassphrase(); err != nil || got != "" {
+		t.Errorf("unset passphrase = %q, %v", got, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "passphrase")
+	if err := os.WriteFile(path, []byte("from file\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cfg.Journal.PassphraseFile = path
+	if got, err := cfg.GetJournalPassphrase(); err != nil || got != "from file" {
+		t.Errorf("file passphrase = %q, %v", got, err)
+	}
+
+	t.Setenv(PassphraseEnv, "from env")
+	if got, _ := cfg.GetJournalPassphrase(); got != "from env" {
+		t.Errorf("env passphrase = %q, want it to win over the file", got)
+	}
+}
+
+func TestJournalSectionsMergesDefaults(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", tmpDir)
+
+	configDir := filepath.Join(tmpDir, "pulse")
+	if err := os.MkdirAll(configDir, 0750); err != nil {
+		t.Fatalf("failed to create config dir: %v", err)
+	}
+	configData := `journal:
+  sections:
+    - name: decisions
+      description: "Decisions made and why"
+      route: project
+      required: true
+    - name: blockers
+    - name: feelings
+      description: "How it went"
+    - name: user_context
+      sync: never
+`
+	if err := os.WriteFile(filepath.Join(configDir, "config.yaml"), []byte(configData), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+
+	defs := cfg.JournalSections()
+	if len(defs) != 7 {
+		t.Fatalf("expected 5 built-in + 2 custom sections, got %d", len(defs))
+	}
+	byName := make(map[string]int)
+	for i, def := range defs {
+		byName[def.Name] = i
+	}
+
+	decisions := defs[byName["decisions"]]
+	if decisions.Route != "project" || !decisions.Required || decisions.Description != "Decisions made and why" {
+		t.Errorf("unexpected decisions section: %+v", decisions)
+	}
+	if blockers := defs[byName["blockers"]]; blockers.Route != "user" {
+		t.Errorf("expected custom sections to route to user by default, got %+v", blockers)
+	}
+	if feelings := defs[byName["feelings"]]; feelings.Description != "How it went" || feelings.Route != "user" {
+		t.Errorf("expected feelings description override only, got %+v", feelings)
+	}
+	if byName["feelings"] != 0 {
+		t.Errorf("expected overrides to keep their built-in position, got %d", byName["feelings"])
+	}
+	if feelings := defs[byName["feelings"]]; feelings.Sync != models.SyncOptIn {
+		t.Errorf("expected feelings to keep its opt-in sync policy, got %q", feelings.Sync)
+	}
+	if userContext := defs[byName["user_context"]]; userContext.Sync != models.SyncNever {
+		t.Errorf("expected the sync override to apply, got %q", userContext.Sync)
+	}
+}
@@ -0,0 +1,330 @@
+// ABOUTME: Local journal digests summarizing the entries written over a period.
+// ABOUTME: Counts entries by type, section, and day, and extracts frequent terms, new insights, and recurring feelings.
+package digest
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/2389-research/pulse/internal/embeddings"
+	"github.com/2389-research/pulse/internal/models"
+)
+
+// Default limits for the ranked lists in a digest.
+const (
+	DefaultTopTerms = 10
+	DefaultThemes   = 5
+)
+
+// Options bounds the period a digest covers and how much it reports.
+type Options struct {
+	Since    time.Time // start of the period, inclusive
+	Until    time.Time // end of the period, exclusive; zero for now
+	TopTerms int       // most frequent terms to report; DefaultTopTerms if zero
+	Themes   int       // recurring feelings themes to report; DefaultThemes if zero
+}
+
+// Digest summarizes the journal entries written over a period. Every count
+// and list is computed from the entries themselves; nothing is generated.
+type Digest struct {
+	Since             time.Time      `json:"since"`
+	Until             time.Time      `json:"until"`
+	Entries           int            `json:"entries"`
+	Thoughts          int            `json:"thoughts"` // entries split from one write count once
+	ByType            map[string]int `json:"by_type"`
+	BySection         []Count        `json:"by_section"`
+	ByDay             []Count        `json:"by_day"`
+	TopTerms          []Count        `json:"top_terms"`
+	TechnicalInsights []Excerpt      `json:"technical_insights"`
+	FeelingsThemes    []Theme        `json:"feelings_themes"`
+	Sources           []Source       `json:"sources"`
+}
+
+// Count is a named tally.
+type Count struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// Excerpt is the opening of one entry's section.
+type Excerpt struct {
+	Date time.Time `json:"date"`
+	Path string    `json:"path"`
+	Text string    `json:"text"`
+}
+
+// Theme is a term that recurs across the feelings of several entries.
+type Theme struct {
+	Term    string   `json:"term"`
+	Entries int      `json:"entries"`
+	Paths   []string `json:"paths"`
+}
+
+// Source is an entry the digest was built from.
+type Source struct {
+	Date     time.Time `json:"date"`
+	Type     string    `json:"type"`
+	Sections []string  `json:"sections"`
+	Path     string    `json:"path"`
+}
+
+// Build summarizes entries, which should already be limited to the period in
+// opts. Entries are reported oldest first.
+func Build(entries []*models.JournalEntry, opts Options) *Digest {
+	if opts.Until.IsZero() {
+		opts.Until = time.Now()
+	}
+	if opts.TopTerms <= 0 {
+		opts.TopTerms = DefaultTopTerms
+	}
+	if opts.Themes <= 0 {
+		opts.Themes = DefaultThemes
+	}
+
+	sorted := make([]*models.JournalEntry, len(entries))
+	copy(sorted, entries)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].CreatedAt.Before(sorted[j].CreatedAt)
+	})
+
+	d := &Digest{
+		Since:             opts.Since,
+		Until:             opts.Until,
+		Entries:           len(sorted),
+		ByType:            make(map[string]int),
+		BySection:         []Count{},
+		ByDay:             []Count{},
+		TopTerms:          []Count{},
+		TechnicalInsights: []Excerpt{},
+		FeelingsThemes:    []Theme{},
+		Sources:           []Source{},
+	}
+
+	sections := make(map[string]int)
+	days := make(map[string]int)
+	terms := make(map[string]int)
+	themes := make(map[string][]string) // feelings term -> paths of entries using it
+	thoughts := make(map[string]bool)
+	for _, entry := range sorted {
+		thoughts[entry.ThoughtKey()] = true
+		d.ByType[entry.Type]++
+		days[entry.CreatedAt.Local().Format("2006-01-02")]++
+
+		names := sortedSections(entry.Sections)
+		for _, name := range names {
+			sections[name]++
+			for _, term := range digestTerms(entry.Sections[name]) {
+				terms[term]++
+			}
+		}
+		for _, term := range uniqueStrings(digestTerms(entry.Sections["feelings"])) {
+			themes[term] = append(themes[term], entry.FilePath)
+		}
+		if insight := strings.TrimSpace(entry.Sections["technical_insights"]); insight != "" {
+			d.TechnicalInsights = append(d.TechnicalInsights, Excerpt{
+				Date: entry.CreatedAt,
+				Path: entry.FilePath,
+				Text: embeddings.Snippet(insight, nil),
+			})
+		}
+		d.Sources = append(d.Sources, Source{
+			Date:     entry.CreatedAt,
+			Type:     entry.Type,
+			Sections: names,
+			Path:     entry.FilePath,
+		})
+	}
+	d.Thoughts = len(thoughts)
+
+	for _, name := range sortedSections(toSet(sections)) {
+		d.BySection = append(d.BySection, Count{Name: name, Count: sections[name]})
+	}
+	for day, n := range days {
+		d.ByDay = append(d.ByDay, Count{Name: day, Count: n})
+	}
+	sort.Slice(d.ByDay, func(i, j int) bool { return d.ByDay[i].Name < d.ByDay[j].Name })
+	d.TopTerms = topCounts(terms, opts.TopTerms)
+
+	// A theme recurs when at least two entries' feelings mention it
+	for _, c := range topCounts(lengths(themes), len(themes)) {
+		if c.Count < 2 || len(d.FeelingsThemes) == opts.Themes {
+			break
+		}
+		d.FeelingsThemes = append(d.FeelingsThemes, Theme{Term: c.Name, Entries: c.Count, Paths: themes[c.Name]})
+	}
+	return d
+}
+
+// JSON renders the digest as indented JSON.
+func (d *Digest) JSON() ([]byte, error) {
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal digest: %w", err)
+	}
+	return data, nil
+}
+
+// Markdown renders the digest as a markdown report whose entries link to
+// their source files.
+func (d *Digest) Markdown() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("# Journal digest: %s to %s\n\n", formatDay(d.Since), formatDay(d.Until.Add(-time.Nanosecond))))
+	if d.Entries == 0 {
+		sb.WriteString("No entries in this period.\n")
+		return sb.String()
+	}
+
+	sb.WriteString(fmt.Sprintf("%d entries from %d thoughts", d.Entries, d.Thoughts))
+	var types []string
+	for _, t := range sortedKeys(d.ByType) {
+		types = append(types, fmt.Sprintf("%d %s", d.ByType[t], t))
+	}
+	sb.WriteString(fmt.Sprintf(" (%s).\n", strings.Join(types, ", ")))
+
+	sb.WriteString("\n## Sections\n\n")
+	for _, c := range d.BySection {
+		sb.WriteString(fmt.Sprintf("- %s: %d\n", models.SectionTitle(c.Name), c.Count))
+	}
+
+	sb.WriteString("\n## By day\n\n")
+	for _, c := range d.ByDay {
+		sb.WriteString(fmt.Sprintf("- %s: %d\n", c.Name, c.Count))
+	}
+
+	if len(d.TopTerms) > 0 {
+		sb.WriteString("\n## Frequent terms\n\n")
+		var parts []string
+		for _, c := range d.TopTerms {
+			parts = append(parts, fmt.Sprintf("%s (%d)", c.Name, c.Count))
+		}
+		sb.WriteString(strings.Join(parts, ", ") + "\n")
+	}
+
+	if len(d.TechnicalInsights) > 0 {
+		sb.WriteString("\n## New technical insights\n\n")
+		for _, ex := range d.TechnicalInsights {
+			sb.WriteString(fmt.Sprintf("- %s ([%s](%s))\n", ex.Text, ex.Date.Local().Format("2006-01-02 15:04"), ex.Path))
+		}
+	}
+
+	if len(d.FeelingsThemes) > 0 {
+		sb.WriteString("\n## Recurring feelings\n\n")
+		for _, theme := range d.FeelingsThemes {
+			links := make([]string, len(theme.Paths))
+			for i, path := range theme.Paths {
+				links[i] = fmt.Sprintf("[%d](%s)", i+1, path)
+			}
+			sb.WriteString(fmt.Sprintf("- %s, in %d entries: %s\n", theme.Term, theme.Entries, strings.Join(links, " ")))
+		}
+	}
+
+	sb.WriteString("\n## Sources\n\n")
+	for _, src := range d.Sources {
+		sb.WriteString(fmt.Sprintf("- [%s](%s) [%s] %s\n",
+			src.Date.Local().Format("2006-01-02 15:04"), src.Path, src.Type, strings.Join(src.Sections, ", ")))
+	}
+	return sb.String()
+}
+
+// digestTerms tokenizes text for term counts, keeping words of at least
+// three letters so numbers and fragments don't crowd out real topics.
+func digestTerms(text string) []string {
+	var terms []string
+	for _, token := range embeddings.Tokenize(text) {
+		if len([]rune(token)) < 3 || strings.IndexFunc(token, unicode.IsLetter) < 0 {
+			continue
+		}
+		terms = append(terms, token)
+	}
+	return terms
+}
+
+// topCounts returns the n largest counts, breaking ties alphabetically.
+func topCounts(counts map[string]int, n int) []Count {
+	out := make([]Count, 0, len(counts))
+	for name, count := range counts {
+		out = append(out, Count{Name: name, Count: count})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		return out[i].Name < out[j].Name
+	})
+	if len(out) > n {
+		out = out[:n]
+	}
+	return out
+}
+
+// sortedSections returns the section names in schema order, followed by
+// unknown sections alphabetically.
+func sortedSections(sections map[string]string) []string {
+	var names, extra []string
+	for _, name := range models.GetValidSections() {
+		if _, ok := sections[name]; ok {
+			names = append(names, name)
+		}
+	}
+	for name := range sections {
+		if !models.IsValidSection(name) {
+			extra = append(extra, name)
+		}
+	}
+	sort.Strings(extra)
+	return append(names, extra...)
+}
+
+// toSet turns a count map into a set keyed the same way.
+func toSet(counts map[string]int) map[string]string {
+	set := make(map[string]string, len(counts))
+	for name := range counts {
+		set[name] = ""
+	}
+	return set
+}
+
+// lengths maps each key to the length of its list.
+func lengths(lists map[string][]string) map[string]int {
+	out := make(map[string]int, len(lists))
+	for key, list := range lists {
+		out[key] = len(list)
+	}
+	return out
+}
+
+// uniqueStrings removes duplicates while preserving order.
+func uniqueStrings(list []string) []string {
+	seen := make(map[string]bool, len(list))
+	out := list[:0]
+	for _, s := range list {
+		if !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// sortedKeys returns the keys of m in alphabetical order.
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// formatDay renders a period bound as a local date, or "the beginning" for
+// an open start.
+func formatDay(t time.Time) string {
+	if t.IsZero() {
+		return "the beginning"
+	}
+	return t.Local().Format("2006-01-02")
+}
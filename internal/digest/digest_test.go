@@ -0,0 +1,102 @@
+// ABOUTME: Tests for journal digest generation.
+// ABOUTME: Covers counts, frequent terms, insights, recurring feelings, and markdown and JSON rendering.
+package digest
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/2389-research/pulse/internal/models"
+)
+
+func digestEntry(created time.Time, entryType, path string, sections map[string]string) *models.JournalEntry {
+	return &models.JournalEntry{
+		ID:        uuid.New(),
+		Sections:  sections,
+		CreatedAt: created,
+		FilePath:  path,
+		Type:      entryType,
+	}
+}
+
+func TestBuild(t *testing.T) {
+	day := time.Date(2026, 3, 2, 10, 0, 0, 0, time.Local)
+	thought := uuid.New()
+	split := []*models.JournalEntry{
+		digestEntry(day.Add(time.Hour), "user", "/j/b.md", map[string]string{"feelings": "Frustrated by flaky deploys"}),
+		digestEntry(day.Add(time.Hour), "project", "/j/c.md", map[string]string{"technical_insights": "Deploys flake when the cache warms slowly"}),
+	}
+	split[0].ThoughtID, split[1].ThoughtID = thought, thought
+	entries := []*models.JournalEntry{
+		split[0], split[1],
+		digestEntry(day, "user", "/j/a.md", map[string]string{"feelings": "frustrated, tired"}),
+		digestEntry(day.AddDate(0, 0, 1), "user", "/j/d.md", map[string]string{"feelings": "happy with the fix", "project_notes": "deploys are green"}),
+	}
+
+	d := Build(entries, Options{Since: day.AddDate(0, 0, -1), Until: day.AddDate(0, 0, 2)})
+
+	if d.Entries != 4 || d.Thoughts != 3 {
+		t.Errorf("expected 4 entries from 3 thoughts, got %d from %d", d.Entries, d.Thoughts)
+	}
+	if d.ByType["user"] != 3 || d.ByType["project"] != 1 {
+		t.Errorf("unexpected type counts: %v", d.ByType)
+	}
+	if len(d.BySection) != 3 || d.BySection[0].Name != "feelings" || d.BySection[0].Count != 3 {
+		t.Errorf("expected sections in schema order with feelings first, got %+v", d.BySection)
+	}
+	if len(d.ByDay) != 2 || d.ByDay[0].Count != 3 || d.ByDay[1].Count != 1 {
+		t.Errorf("unexpected day counts: %+v", d.ByDay)
+	}
+	if len(d.TopTerms) == 0 || d.TopTerms[0].Name != "deploys" {
+		t.Errorf("expected deploys to be the top term, got %+v", d.TopTerms)
+	}
+	if len(d.TechnicalInsights) != 1 || d.TechnicalInsights[0].Path != "/j/c.md" {
+		t.Errorf("unexpected insights: %+v", d.TechnicalInsights)
+	}
+	if len(d.FeelingsThemes) != 1 || d.FeelingsThemes[0].Term != "frustrated" {
+		t.Fatalf("expected frustrated as the only recurring theme, got %+v", d.FeelingsThemes)
+	}
+	if got := strings.Join(d.FeelingsThemes[0].Paths, ","); got != "/j/a.md,/j/b.md" {
+		t.Errorf("expected theme sources oldest first, got %s", got)
+	}
+	if d.Sources[0].Path != "/j/a.md" {
+		t.Errorf("expected sources oldest first, got %+v", d.Sources)
+	}
+
+	md := d.Markdown()
+	for _, want := range []string{"# Journal digest: 2026-03-01 to 2026-03-04", "4 entries from 3 thoughts (1 project, 3 user).", "- Feelings: 3", "frustrated, in 2 entries: [1](/j/a.md) [2](/j/b.md)", "(/j/d.md) [user] feelings, project_notes"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+
+	data, err := d.JSON()
+	if err != nil {
+		t.Fatalf("JSON error: %v", err)
+	}
+	var decoded Digest
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("JSON did not roundtrip: %v", err)
+	}
+	if decoded.Entries != 4 || len(decoded.Sources) != 4 {
+		t.Errorf("unexpected decoded digest: %+v", decoded)
+	}
+}
+
+func TestBuildEmpty(t *testing.T) {
+	d := Build(nil, Options{})
+	if !strings.Contains(d.Markdown(), "No entries in this period.") {
+		t.Errorf("expected empty digest message, got %s", d.Markdown())
+	}
+	data, err := d.JSON()
+	if err != nil {
+		t.Fatalf("JSON error: %v", err)
+	}
+	if strings.Contains(string(data), "null") {
+		t.Errorf("expected empty lists rather than null, got %s", data)
+	}
+}
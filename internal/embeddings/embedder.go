@@ -1,7 +1,13 @@
 // ABOUTME: Embedding interface and implementations for journal search.
-// ABOUTME: Provides local ONNX-based embeddings with fallback to substring matching.
+// ABOUTME: Provides a pure-Go hashed n-gram embedder and a name-based factory for new backends.
 package embeddings
 
+import (
+	"fmt"
+
+	"github.com/2389-research/pulse/internal/models"
+)
+
 // Embedder generates vector embeddings from text.
 type Embedder interface {
 	// Embed returns a vector embedding for the given text.
@@ -9,4 +15,41 @@ type Embedder interface {
 
 	// Dimension returns the dimensionality of the output vectors.
 	Dimension() int
+
+	// Name identifies the model and algorithm version. Vectors from
+	// embedders with different names are not comparable.
+	Name() string
+}
+
+// Compatible reports whether emb can be compared with vectors from embedder.
+// Sidecars without a recorded model are accepted when the dimension matches.
+func Compatible(emb *models.Embedding, embedder Embedder) bool {
+	if emb.Model != "" && emb.Model != embedder.Name() {
+		return false
+	}
+	if len(emb.Vector) != embedder.Dimension() {
+		return false
+	}
+	for _, vec := range emb.SectionVectors {
+		if len(vec) != embedder.Dimension() {
+			return false
+		}
+	}
+	return true
+}
+
+// DefaultDimension is the vector size used by the built-in hash embedder.
+const DefaultDimension = 512
+
+// New returns the embedder registered under name.
+// An empty name selects the default "hash" embedder; "none" disables embeddings and returns nil.
+func New(name string) (Embedder, error) {
+	switch name {
+	case "", "hash":
+		return NewHashEmbedder(DefaultDimension), nil
+	case "none":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown embedder %q: must be one of: hash, none", name)
+	}
 }
@@ -0,0 +1,132 @@
+// ABOUTME: Offline hashed n-gram embedder that needs no model files or network access.
+// ABOUTME: Projects word, bigram, and character trigram features into a fixed-size vector.
+package embeddings
+
+import (
+	"hash/fnv"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// Feature weights. Whole words carry the most signal; bigrams reward phrase
+// overlap and character trigrams let morphological variants ("migrate",
+// "migration") land near each other.
+const (
+	wordWeight    = 1.0
+	bigramWeight  = 0.5
+	trigramWeight = 0.25
+)
+
+// hashEmbedderName identifies the hashing scheme. Bump the version whenever
+// tokenization, features, or weights change so old sidecars are regenerated.
+const hashEmbedderName = "hash-ngram-v1"
+
+// stopwords are dropped before hashing so common filler words don't dominate short texts.
+var stopwords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "but": true, "by": true, "for": true, "from": true, "i": true,
+	"if": true, "in": true, "is": true, "it": true, "its": true, "me": true,
+	"my": true, "of": true, "on": true, "or": true, "so": true, "that": true,
+	"the": true, "this": true, "to": true, "was": true, "we": true, "were": true,
+	"with": true, "you": true,
+}
+
+// HashEmbedder produces embeddings with the hashing trick: each feature is
+// hashed to a vector index and sign, term frequencies are damped
+// logarithmically, and the result is L2-normalized.
+type HashEmbedder struct {
+	dim int
+}
+
+// NewHashEmbedder creates a hash embedder with the given output dimension.
+// Non-positive dimensions fall back to DefaultDimension.
+func NewHashEmbedder(dim int) *HashEmbedder {
+	if dim <= 0 {
+		dim = DefaultDimension
+	}
+	return &HashEmbedder{dim: dim}
+}
+
+// Embed returns a normalized vector for text. Empty text yields a zero vector.
+func (e *HashEmbedder) Embed(text string) ([]float32, error) {
+	counts := make(map[string]int)
+	tokens := Tokenize(text)
+
+	for i, tok := range tokens {
+		counts["w:"+tok]++
+		if i > 0 {
+			counts["b:"+tokens[i-1]+" "+tok]++
+		}
+		padded := []rune("^" + tok + "$")
+		for j := 0; j+3 <= len(padded); j++ {
+			counts["c:"+string(padded[j:j+3])]++
+		}
+	}
+
+	vec := make([]float64, e.dim)
+	for feature, tf := range counts {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(feature))
+		sum := h.Sum64()
+		idx := int(sum % uint64(e.dim))
+		sign := 1.0
+		if sum&(1<<63) != 0 {
+			sign = -1.0
+		}
+		vec[idx] += sign * featureWeight(feature) * (1 + math.Log(float64(tf)))
+	}
+
+	var norm float64
+	for _, v := range vec {
+		norm += v * v
+	}
+	norm = math.Sqrt(norm)
+
+	out := make([]float32, e.dim)
+	if norm == 0 {
+		return out, nil
+	}
+	for i, v := range vec {
+		out[i] = float32(v / norm)
+	}
+	return out, nil
+}
+
+// Dimension returns the dimensionality of the output vectors.
+func (e *HashEmbedder) Dimension() int {
+	return e.dim
+}
+
+// Name identifies the hashing scheme.
+func (e *HashEmbedder) Name() string {
+	return hashEmbedderName
+}
+
+// featureWeight returns the weight for a feature based on its kind prefix.
+func featureWeight(feature string) float64 {
+	switch feature[0] {
+	case 'w':
+		return wordWeight
+	case 'b':
+		return bigramWeight
+	default:
+		return trigramWeight
+	}
+}
+
+// Tokenize lowercases text, splits it on anything that isn't a letter or
+// digit, and drops stopwords.
+func Tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	tokens := fields[:0]
+	for _, f := range fields {
+		if stopwords[f] {
+			continue
+		}
+		tokens = append(tokens, f)
+	}
+	return tokens
+}
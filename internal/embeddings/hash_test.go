@@ -0,0 +1,87 @@
+// ABOUTME: Tests for the hashed n-gram embedder and tokenizer.
+// ABOUTME: Verifies determinism, normalization, and that related text scores higher than unrelated text.
+package embeddings
+
+import (
+	"math"
+	"testing"
+)
+
+func TestHashEmbedderDeterministic(t *testing.T) {
+	e := NewHashEmbedder(64)
+	a, _ := e.Embed("refactored the storage layer")
+	b, _ := e.Embed("refactored the storage layer")
+	if CosineSimilarity(a, b) < 0.9999 {
+		t.Error("expected identical text to produce identical vectors")
+	}
+}
+
+func TestHashEmbedderNormalized(t *testing.T) {
+	e := NewHashEmbedder(DefaultDimension)
+	vec, err := e.Embed("Go interfaces are powerful")
+	if err != nil {
+		t.Fatalf("Embed error: %v", err)
+	}
+	if len(vec) != DefaultDimension {
+		t.Fatalf("expected %d dims, got %d", DefaultDimension, len(vec))
+	}
+	var norm float64
+	for _, v := range vec {
+		norm += float64(v) * float64(v)
+	}
+	if math.Abs(math.Sqrt(norm)-1.0) > 0.0001 {
+		t.Errorf("expected unit vector, got norm %f", math.Sqrt(norm))
+	}
+}
+
+func TestHashEmbedderEmptyText(t *testing.T) {
+	e := NewHashEmbedder(16)
+	vec, err := e.Embed("")
+	if err != nil {
+		t.Fatalf("Embed error: %v", err)
+	}
+	for _, v := range vec {
+		if v != 0 {
+			t.Fatal("expected zero vector for empty text")
+		}
+	}
+}
+
+func TestHashEmbedderRelatedTextScoresHigher(t *testing.T) {
+	e := NewHashEmbedder(DefaultDimension)
+	query, _ := e.Embed("database migration")
+	related, _ := e.Embed("Spent the afternoon migrating the database schema to the new layout")
+	unrelated, _ := e.Embed("The weather was lovely and I went for a long walk in the park")
+
+	if CosineSimilarity(query, related) <= CosineSimilarity(query, unrelated) {
+		t.Errorf("expected related text to score higher: related=%f unrelated=%f",
+			CosineSimilarity(query, related), CosineSimilarity(query, unrelated))
+	}
+}
+
+func TestTokenizeDropsStopwordsAndPunctuation(t *testing.T) {
+	got := Tokenize("The API, is DOWN -- again!")
+	want := []string{"api", "down", "again"}
+	if len(got) != len(want) {
+		t.Fatalf("Tokenize() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Tokenize()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestNewEmbedder(t *testing.T) {
+	e, err := New("")
+	if err != nil || e == nil {
+		t.Fatalf("New(\"\") = %v, %v; want default embedder", e, err)
+	}
+	e, err = New("none")
+	if err != nil || e != nil {
+		t.Fatalf("New(\"none\") = %v, %v; want nil embedder", e, err)
+	}
+	if _, err := New("bogus"); err == nil {
+		t.Error("expected error for unknown embedder name")
+	}
+}
@@ -0,0 +1,400 @@
+// ABOUTME: Hybrid search engine blending BM25 term scores with embedding similarity.
+// ABOUTME: Shared by the MCP search_journal tool and the CLI; produces scored results with snippets.
+package embeddings
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/2389-research/pulse/internal/models"
+	"github.com/2389-research/pulse/internal/query"
+)
+
+// BM25 parameters and blending weights.
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+
+	// lexicalWeight is the share of the final score taken by BM25 when an
+	// entry has a vector; the rest comes from cosine similarity.
+	lexicalWeight = 0.5
+
+	// phraseBonus rewards entries containing the whole query verbatim.
+	phraseBonus = 0.1
+
+	// MinSimilarity is the cosine similarity an entry needs to match on
+	// embeddings alone, with no query term in common.
+	MinSimilarity = 0.12
+
+	// snippetLen is the approximate snippet length in runes.
+	snippetLen = 160
+)
+
+// EmbeddingPath returns the sidecar path for a journal markdown file.
+func EmbeddingPath(mdPath string) string {
+	return strings.TrimSuffix(mdPath, ".md") + ".embedding"
+}
+
+// ReadEmbedding loads the sidecar for a journal markdown file.
+func ReadEmbedding(mdPath string) (*models.Embedding, error) {
+	data, err := os.ReadFile(EmbeddingPath(mdPath))
+	if err != nil {
+		return nil, err
+	}
+	var emb models.Embedding
+	if err := json.Unmarshal(data, &emb); err != nil {
+		return nil, err
+	}
+	return &emb, nil
+}
+
+// document is an entry prepared for lexical scoring.
+type document struct {
+	entry  *models.JournalEntry
+	text   string
+	terms  map[string]int
+	length int
+}
+
+// Search ranks entries against query by blending BM25 over their section text
+// with cosine similarity against their .embedding sidecars. embedder may be
+// nil, in which case ranking is purely lexical. Results are populated with the
+// entry, its score, the best-matching section, and a snippet from it. With a
+// section filter, only the requested sections' vectors are scored. Entries
+// older than opts.Since or failing opts.Filter are skipped.
+//
+// queryString uses the query language of package query. Plain words are
+// ranked by keyword and meaning; operators, phrases, and fields make the query
+// boolean, and every entry it matches is returned, ranked by its free text.
+func Search(embedder Embedder, entries []*models.JournalEntry, queryString string, opts SearchOptions) ([]SearchResult, error) {
+	q, err := query.Parse(queryString)
+	if err != nil {
+		return nil, err
+	}
+	freeText := q.Text()
+	queryTerms := uniqueTerms(Tokenize(freeText))
+	queryLower := strings.ToLower(strings.TrimSpace(freeText))
+	if scoped := q.Sections(); len(scoped) > 0 {
+		if len(opts.Sections) > 0 {
+			// Both restrict: only sections named by each can match
+			var both []string
+			for _, name := range opts.Sections {
+				if contains(scoped, name) {
+					both = append(both, name)
+				}
+			}
+			if len(both) == 0 {
+				return nil, nil
+			}
+			scoped = both
+		}
+		opts.Sections = scoped
+	}
+
+	var queryVec []float32
+	if embedder != nil && queryLower != "" {
+		vec, err := embedder.Embed(freeText)
+		if err != nil {
+			return nil, err
+		}
+		queryVec = vec
+	}
+
+	docs := make([]document, 0, len(entries))
+	var totalLen int
+	for _, entry := range entries {
+		if !opts.Since.IsZero() && entry.CreatedAt.Before(opts.Since) {
+			continue
+		}
+		if !opts.Filter.Matches(entry.EntryMeta) || !q.Match(entry) {
+			continue
+		}
+		text := sectionText(entry, opts.Sections)
+		if text == "" {
+			continue
+		}
+		tokens := Tokenize(text)
+		terms := make(map[string]int, len(tokens))
+		for _, tok := range tokens {
+			terms[tok]++
+		}
+		docs = append(docs, document{entry: entry, text: text, terms: terms, length: len(tokens)})
+		totalLen += len(tokens)
+	}
+	if len(docs) == 0 {
+		return nil, nil
+	}
+	n := len(docs)
+	avgLen := float64(totalLen) / float64(len(docs))
+
+	// Document frequency for each query term
+	df := make(map[string]int, len(queryTerms))
+	if opts.Corpus != nil && opts.Corpus.DocCount >= len(docs) {
+		n = opts.Corpus.DocCount
+		if opts.Corpus.AvgLength > 0 {
+			avgLen = opts.Corpus.AvgLength
+		}
+		for _, term := range queryTerms {
+			df[term] = opts.Corpus.DocFreq[term]
+		}
+	} else {
+		for _, d := range docs {
+			for _, term := range queryTerms {
+				if d.terms[term] > 0 {
+					df[term]++
+				}
+			}
+		}
+	}
+
+	lexical := make([]float64, len(docs))
+	var maxLexical float64
+	for i, d := range docs {
+		lexical[i] = bm25(d, queryTerms, df, n, avgLen)
+		if lexical[i] > maxLexical {
+			maxLexical = lexical[i]
+		}
+	}
+
+	var results []SearchResult
+	for i, d := range docs {
+		lex := 0.0
+		if maxLexical > 0 {
+			lex = lexical[i] / maxLexical
+		}
+		phrase := queryLower != "" && strings.Contains(strings.ToLower(d.text), queryLower)
+
+		score := lex
+		sim, hasVector := 0.0, false
+		var sims map[string]float64
+		if queryVec != nil && d.entry.FilePath != "" {
+			if emb, err := ReadEmbedding(d.entry.FilePath); err == nil && Compatible(emb, embedder) {
+				sims = SectionSimilarities(queryVec, emb, opts.Sections)
+				for _, v := range sims {
+					if !hasVector || v > sim {
+						sim, hasVector = v, true
+					}
+				}
+				if hasVector {
+					score = lexicalWeight*lex + (1-lexicalWeight)*sim
+				}
+			}
+		}
+		if phrase {
+			score += phraseBonus
+		}
+
+		// A boolean query has already decided which entries match
+		if !q.Boolean() && lexical[i] == 0 && !phrase && (!hasVector || sim < MinSimilarity) {
+			continue
+		}
+
+		section := bestSection(d.entry, queryTerms, sims, opts.Sections)
+		results = append(results, SearchResult{
+			Entry:   d.entry,
+			Score:   score,
+			Path:    d.entry.FilePath,
+			Section: section,
+			Snippet: models.SectionTitle(section) + ": " + Snippet(d.entry.Sections[section], queryTerms),
+		})
+	}
+
+	// Equal scores fall back to (CreatedAt, ID), newest first, so the ranking
+	// is a total order that cursors can resume from
+	sort.Slice(results, func(i, j int) bool {
+		return rankedBefore(results[i], RankCursor(results[j]))
+	})
+	if !opts.After.IsZero() {
+		kept := results[:0]
+		for _, r := range results {
+			if rankedAfter(r, opts.After) {
+				kept = append(kept, r)
+			}
+		}
+		results = kept
+	}
+
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = 10
+	}
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results, nil
+}
+
+// RankCursor returns the cursor positioned at a search result.
+func RankCursor(r SearchResult) models.Cursor {
+	c := models.CursorAt(r.Entry)
+	c.Score = r.Score
+	return c
+}
+
+// rankedBefore reports whether r ranks ahead of the position c: a higher
+// score, or an equal score and a later (CreatedAt, ID).
+func rankedBefore(r SearchResult, c models.Cursor) bool {
+	if r.Score != c.Score {
+		return r.Score > c.Score
+	}
+	return c.Compare(r.Entry.CreatedAt, r.Entry.ID) > 0
+}
+
+// rankedAfter reports whether r ranks strictly behind the position c.
+func rankedAfter(r SearchResult, c models.Cursor) bool {
+	if r.Score != c.Score {
+		return r.Score < c.Score
+	}
+	return c.Compare(r.Entry.CreatedAt, r.Entry.ID) < 0
+}
+
+// bm25 computes the Okapi BM25 score of a document for the given query terms.
+func bm25(d document, queryTerms []string, df map[string]int, n int, avgLen float64) float64 {
+	var score float64
+	for _, term := range queryTerms {
+		tf := float64(d.terms[term])
+		if tf == 0 {
+			continue
+		}
+		idf := math.Log(1 + (float64(n)-float64(df[term])+0.5)/(float64(df[term])+0.5))
+		norm := tf + bm25K1*(1-bm25B+bm25B*float64(d.length)/avgLen)
+		score += idf * tf * (bm25K1 + 1) / norm
+	}
+	return score
+}
+
+// sectionText joins the entry's section content, restricted to the given sections if any.
+func sectionText(entry *models.JournalEntry, sections []string) string {
+	var parts []string
+	for _, name := range sortedSectionNames(entry.Sections) {
+		if len(sections) > 0 && !contains(sections, name) {
+			continue
+		}
+		if content := entry.Sections[name]; content != "" {
+			parts = append(parts, content)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// bestSection picks the section that best explains a match, blending the share
+// of query terms it contains with its vector similarity. Whole-entry similarity
+// (key "") counts for every section. Ties go to the first section by name.
+func bestSection(entry *models.JournalEntry, queryTerms []string, sims map[string]float64, sections []string) string {
+	best, bestScore := "", -1.0
+	for _, name := range sortedSectionNames(entry.Sections) {
+		if len(sections) > 0 && !contains(sections, name) {
+			continue
+		}
+		content := entry.Sections[name]
+		if content == "" {
+			continue
+		}
+
+		hitRatio := 0.0
+		if len(queryTerms) > 0 {
+			tokens := Tokenize(content)
+			hits := 0
+			for _, term := range queryTerms {
+				if contains(tokens, term) {
+					hits++
+				}
+			}
+			hitRatio = float64(hits) / float64(len(queryTerms))
+		}
+		sim, ok := sims[name]
+		if !ok {
+			sim = sims[""]
+		}
+
+		score := lexicalWeight*hitRatio + (1-lexicalWeight)*sim
+		if score > bestScore {
+			best, bestScore = name, score
+		}
+	}
+	return best
+}
+
+// Snippet returns a short excerpt of content centered on the first query term it contains.
+func Snippet(content string, queryTerms []string) string {
+	content = strings.Join(strings.Fields(content), " ")
+	lower := strings.ToLower(content)
+	start := 0
+	for _, term := range queryTerms {
+		if idx := strings.Index(lower, term); idx >= 0 {
+			start = idx
+			break
+		}
+	}
+
+	// Center the window on the match; lowercasing can change byte lengths
+	if start > len(content) {
+		start = len(content)
+	}
+	runeStart := utf8.RuneCountInString(content[:start]) - snippetLen/4
+	if runeStart < 0 {
+		runeStart = 0
+	}
+	runes := []rune(content)
+	runeEnd := runeStart + snippetLen
+	if runeEnd > len(runes) {
+		runeEnd = len(runes)
+	}
+
+	snippet := string(runes[runeStart:runeEnd])
+	if runeStart > 0 {
+		snippet = "..." + snippet
+	}
+	if runeEnd < len(runes) {
+		snippet += "..."
+	}
+	return snippet
+}
+
+// uniqueTerms removes duplicate terms while preserving order.
+func uniqueTerms(terms []string) []string {
+	seen := make(map[string]bool, len(terms))
+	out := make([]string, 0, len(terms))
+	for _, t := range terms {
+		if !seen[t] {
+			seen[t] = true
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// sortedSectionNames returns section names in a stable order.
+func sortedSectionNames(sections map[string]string) []string {
+	names := make([]string, 0, len(sections))
+	for name := range sections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// sortedKeys returns the keys of a score map in a stable order.
+func sortedKeys(m map[string]float64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// contains reports whether list contains s.
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,255 @@
+// ABOUTME: Tests for the hybrid BM25 and vector search engine.
+// ABOUTME: Covers lexical ranking, section filters, sidecar blending, and snippet extraction.
+package embeddings
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/2389-research/pulse/internal/models"
+)
+
+func makeEntry(path string, sections map[string]string) *models.JournalEntry {
+	return &models.JournalEntry{
+		ID:        uuid.New(),
+		Sections:  sections,
+		CreatedAt: time.Now(),
+		FilePath:  path,
+		Type:      "user",
+	}
+}
+
+func TestSearchLexicalRanking(t *testing.T) {
+	entries := []*models.JournalEntry{
+		makeEntry("a.md", map[string]string{"feelings": "lunch was fine"}),
+		makeEntry("b.md", map[string]string{"technical_insights": "the cache eviction bug was a cache key collision"}),
+		makeEntry("c.md", map[string]string{"project_notes": "added a cache layer"}),
+	}
+
+	results, err := Search(nil, entries, "cache collision", SearchOptions{})
+	if err != nil {
+		t.Fatalf("Search error: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if results[0].Path != "b.md" {
+		t.Errorf("expected b.md first, got %s", results[0].Path)
+	}
+	if results[0].Entry == nil {
+		t.Fatal("expected Entry to be populated")
+	}
+	if results[0].Score <= results[1].Score {
+		t.Errorf("expected descending scores, got %f then %f", results[0].Score, results[1].Score)
+	}
+	if !strings.HasPrefix(results[0].Snippet, "Technical Insights: ") {
+		t.Errorf("expected snippet to name its section, got %q", results[0].Snippet)
+	}
+}
+
+func TestSearchSectionFilter(t *testing.T) {
+	entries := []*models.JournalEntry{
+		makeEntry("a.md", map[string]string{"feelings": "frustrated with the deploy"}),
+		makeEntry("b.md", map[string]string{"project_notes": "deploy pipeline is slow"}),
+	}
+
+	results, err := Search(nil, entries, "deploy", SearchOptions{Sections: []string{"project_notes"}})
+	if err != nil {
+		t.Fatalf("Search error: %v", err)
+	}
+	if len(results) != 1 || results[0].Path != "b.md" {
+		t.Fatalf("expected only b.md, got %+v", results)
+	}
+}
+
+func TestSearchBlendsSidecarSimilarity(t *testing.T) {
+	tmpDir := t.TempDir()
+	embedder := NewHashEmbedder(DefaultDimension)
+
+	related := filepath.Join(tmpDir, "related.md")
+	unrelated := filepath.Join(tmpDir, "unrelated.md")
+	relatedSections := map[string]string{"technical_insights": "migrating schemas between database versions"}
+	unrelatedSections := map[string]string{"feelings": "a quiet walk in the park"}
+	for path, sections := range map[string]map[string]string{related: relatedSections, unrelated: unrelatedSections} {
+		_ = os.WriteFile(path, []byte("test"), 0644)
+		if err := WriteEmbedding(path, embedder, sections); err != nil {
+			t.Fatalf("WriteEmbedding error: %v", err)
+		}
+	}
+
+	entries := []*models.JournalEntry{
+		makeEntry(unrelated, unrelatedSections),
+		makeEntry(related, relatedSections),
+	}
+
+	// "migration" shares no token with the entry, only n-grams
+	results, err := Search(embedder, entries, "schema migration", SearchOptions{})
+	if err != nil {
+		t.Fatalf("Search error: %v", err)
+	}
+	if len(results) != 1 || results[0].Path != related {
+		t.Fatalf("expected only the related entry, got %+v", results)
+	}
+}
+
+func TestSearchLimit(t *testing.T) {
+	var entries []*models.JournalEntry
+	for i := 0; i < 5; i++ {
+		entries = append(entries, makeEntry("e.md", map[string]string{"feelings": "same words"}))
+	}
+	results, err := Search(nil, entries, "words", SearchOptions{Limit: 3})
+	if err != nil {
+		t.Fatalf("Search error: %v", err)
+	}
+	if len(results) != 3 {
+		t.Errorf("expected 3 results, got %d", len(results))
+	}
+}
+
+func TestSearchResumesAfterCursor(t *testing.T) {
+	// Equal scores and timestamps leave only the ID to order by
+	created := time.Now()
+	var entries []*models.JournalEntry
+	for i := 0; i < 5; i++ {
+		entry := makeEntry("e.md", map[string]string{"feelings": "same words"})
+		entry.CreatedAt = created
+		entries = append(entries, entry)
+	}
+
+	seen := make(map[uuid.UUID]bool)
+	var after models.Cursor
+	for page := 0; page < 3; page++ {
+		results, err := Search(nil, entries, "words", SearchOptions{Limit: 2, After: after})
+		if err != nil {
+			t.Fatalf("Search error: %v", err)
+		}
+		for _, r := range results {
+			if seen[r.Entry.ID] {
+				t.Errorf("page %d repeated entry %s", page, r.Entry.ID)
+			}
+			seen[r.Entry.ID] = true
+		}
+		if len(results) > 0 {
+			after = RankCursor(results[len(results)-1])
+		}
+	}
+	if len(seen) != 5 {
+		t.Errorf("expected paging to visit all 5 entries, visited %d", len(seen))
+	}
+}
+
+func TestSnippetCentersOnMatch(t *testing.T) {
+	long := strings.Repeat("filler ", 60) + "the needle is here " + strings.Repeat("padding ", 60)
+	snippet := Snippet(long, []string{"needle"})
+	if !strings.Contains(snippet, "needle") {
+		t.Errorf("expected snippet to contain the match, got %q", snippet)
+	}
+	if !strings.Contains(snippet, "...") {
+		t.Errorf("expected truncation markers, got %q", snippet)
+	}
+	if len([]rune(snippet)) > snippetLen+40 {
+		t.Errorf("snippet too long: %d runes", len([]rune(snippet)))
+	}
+}
+
+func TestSearchScoresOnlyFilteredSectionVectors(t *testing.T) {
+	tmpDir := t.TempDir()
+	embedder := NewHashEmbedder(DefaultDimension)
+
+	path := filepath.Join(tmpDir, "entry.md")
+	sections := map[string]string{
+		"feelings":           "tired but content",
+		"technical_insights": "migrating schemas between database versions",
+	}
+	_ = os.WriteFile(path, []byte("test"), 0644)
+	if err := WriteEmbedding(path, embedder, sections); err != nil {
+		t.Fatalf("WriteEmbedding error: %v", err)
+	}
+	entries := []*models.JournalEntry{makeEntry(path, sections)}
+
+	// Unfiltered: the technical_insights vector matches and is reported
+	results, err := Search(embedder, entries, "schema migration", SearchOptions{})
+	if err != nil {
+		t.Fatalf("Search error: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
+	}
+	if results[0].Section != "technical_insights" {
+		t.Errorf("expected technical_insights to match, got %q", results[0].Section)
+	}
+
+	// Filtered to feelings: the technical_insights vector must not count
+	results, err = Search(embedder, entries, "schema migration", SearchOptions{Sections: []string{"feelings"}})
+	if err != nil {
+		t.Fatalf("Search error: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("expected no feelings match, got %+v", results)
+	}
+}
+
+func TestSearchReportsLexicalSection(t *testing.T) {
+	entries := []*models.JournalEntry{
+		makeEntry("a.md", map[string]string{
+			"feelings":      "happy today",
+			"project_notes": "the webhook handler drops retries",
+		}),
+	}
+	results, err := Search(nil, entries, "webhook retries", SearchOptions{})
+	if err != nil {
+		t.Fatalf("Search error: %v", err)
+	}
+	if len(results) != 1 || results[0].Section != "project_notes" {
+		t.Fatalf("expected project_notes match, got %+v", results)
+	}
+	if !strings.HasPrefix(results[0].Snippet, "Project Notes: the webhook") {
+		t.Errorf("unexpected snippet %q", results[0].Snippet)
+	}
+}
+
+func TestSearchScopesTermsToSectionField(t *testing.T) {
+	tmpDir := t.TempDir()
+	embedder := NewHashEmbedder(DefaultDimension)
+
+	path := filepath.Join(tmpDir, "entry.md")
+	sections := map[string]string{
+		"feelings":      "tired but content",
+		"project_notes": "the webhook handler drops retries",
+	}
+	_ = os.WriteFile(path, []byte("test"), 0644)
+	if err := WriteEmbedding(path, embedder, sections); err != nil {
+		t.Fatalf("WriteEmbedding error: %v", err)
+	}
+	entries := []*models.JournalEntry{makeEntry(path, sections)}
+
+	// webhook is only in project_notes, so neither its text nor its vector counts
+	results, err := Search(embedder, entries, "section:feelings webhook", SearchOptions{})
+	if err != nil {
+		t.Fatalf("Search error: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("expected no match in feelings, got %+v", results)
+	}
+
+	results, err = Search(embedder, entries, "section:project_notes webhook", SearchOptions{})
+	if err != nil {
+		t.Fatalf("Search error: %v", err)
+	}
+	if len(results) != 1 || results[0].Section != "project_notes" {
+		t.Fatalf("expected project_notes match, got %+v", results)
+	}
+
+	results, err = Search(embedder, entries, "section:project_notes webhook", SearchOptions{Sections: []string{"feelings"}})
+	if err != nil {
+		t.Fatalf("Search error: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("expected no match when the filter and section: disagree, got %+v", results)
+	}
+}
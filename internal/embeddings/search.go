@@ -1,8 +1,9 @@
 // ABOUTME: Semantic search over journal entries using vector embeddings.
-// ABOUTME: Falls back to substring matching when no embedder is available.
+// ABOUTME: Scores .embedding sidecars by cosine similarity and writes new sidecars.
 package embeddings
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"math"
 	"os"
@@ -18,16 +19,30 @@ import (
 
 // SearchResult pairs a journal entry with its relevance score.
 type SearchResult struct {
-	Entry *models.JournalEntry
-	Score float64
-	Path  string
+	Entry   *models.JournalEntry
+	Score   float64
+	Path    string
+	Snippet string // excerpt of the best-matching section, prefixed with its title
+	Section string // name of the best-matching section; empty if unknown
 }
 
 // SearchOptions configures a search operation.
 type SearchOptions struct {
 	Limit    int
-	Type     string   // "project", "user", or "both"
-	Sections []string // section name filter
+	Type     string            // journal name, comma-separated names, or "all"
+	Sections []string          // section name filter
+	Since    time.Time         // skip entries created before this; zero for no limit
+	Filter   models.MetaFilter // tag, mood, agent, and git context filter
+	After    models.Cursor     // resume strictly after this ranking position; zero to start at the top
+	Corpus   *CorpusStats      // optional collection-wide BM25 statistics
+}
+
+// CorpusStats carries collection-wide BM25 statistics so that Search can
+// score a pre-filtered candidate set as if it saw the whole journal.
+type CorpusStats struct {
+	DocCount  int
+	AvgLength float64
+	DocFreq   map[string]int // query term -> number of entries containing it
 }
 
 // CosineSimilarity computes the cosine similarity between two vectors.
@@ -50,8 +65,39 @@ func CosineSimilarity(a, b []float32) float64 {
 	return dotProduct / (math.Sqrt(normA) * math.Sqrt(normB))
 }
 
-// SearchWithEmbeddings performs semantic search using vector embeddings.
-// Scans .embedding sidecar files alongside journal .md files.
+// Quantize packs vec into one signed byte per dimension, scaled so its
+// largest component is ±127, and encodes it as base64. Cosine similarity
+// ignores scale, so Dequantize's output compares like vec, within rounding.
+func Quantize(vec []float32) string {
+	var maxAbs float64
+	for _, v := range vec {
+		maxAbs = math.Max(maxAbs, math.Abs(float64(v)))
+	}
+	packed := make([]byte, len(vec))
+	if maxAbs > 0 {
+		for i, v := range vec {
+			packed[i] = byte(int8(math.Round(float64(v) / maxAbs * 127)))
+		}
+	}
+	return base64.StdEncoding.EncodeToString(packed)
+}
+
+// Dequantize decodes a vector packed by Quantize.
+func Dequantize(s string) ([]float32, error) {
+	packed, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	vec := make([]float32, len(packed))
+	for i, b := range packed {
+		vec[i] = float32(int8(b))
+	}
+	return vec, nil
+}
+
+// SearchWithEmbeddings performs vector-only search over the .embedding sidecar
+// files under roots. Results carry only Path and Score; use Search to rank
+// parsed entries with populated results.
 func SearchWithEmbeddings(embedder Embedder, roots []string, query string, opts SearchOptions) ([]SearchResult, error) {
 	queryVec, err := embedder.Embed(query)
 	if err != nil {
@@ -66,7 +112,14 @@ func SearchWithEmbeddings(embedder Embedder, roots []string, query string, opts
 		}
 
 		err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
-			if err != nil || info.IsDir() || !strings.HasSuffix(path, ".embedding") {
+			if err != nil {
+				return nil
+			}
+			// Hidden directories such as .trash/ hold no live entries
+			if info.IsDir() && path != root && strings.HasPrefix(info.Name(), ".") {
+				return filepath.SkipDir
+			}
+			if info.IsDir() || !strings.HasSuffix(path, ".embedding") {
 				return nil
 			}
 
@@ -80,29 +133,19 @@ func SearchWithEmbeddings(embedder Embedder, roots []string, query string, opts
 				return nil
 			}
 
-			// Filter by sections if specified
-			if len(opts.Sections) > 0 {
-				match := false
-				for _, s := range opts.Sections {
-					for _, es := range emb.Sections {
-						if s == es {
-							match = true
-							break
-						}
-					}
-					if match {
-						break
-					}
-				}
-				if !match {
-					return nil
-				}
+			// Vectors from another model live in a different space
+			if !Compatible(&emb, embedder) {
+				return nil
 			}
 
-			score := CosineSimilarity(queryVec, emb.Vector)
+			score, section, ok := BestSectionSimilarity(queryVec, &emb, opts.Sections)
+			if !ok {
+				return nil
+			}
 			results = append(results, SearchResult{
-				Score: score,
-				Path:  emb.Path,
+				Score:   score,
+				Path:    emb.Path,
+				Section: section,
 			})
 
 			return nil
@@ -128,16 +171,67 @@ func SearchWithEmbeddings(embedder Embedder, roots []string, query string, opts
 	return results[:limit], nil
 }
 
-// WriteEmbedding writes an embedding sidecar file alongside a journal entry.
+// SectionSimilarities scores queryVec against each section vector in emb,
+// restricted to sections if given. Sidecars without section vectors fall back
+// to the whole-entry vector under the key "", and with sections given only
+// when every section they list is one of them, so text from other sections
+// never scores.
+func SectionSimilarities(queryVec []float32, emb *models.Embedding, sections []string) map[string]float64 {
+	sims := make(map[string]float64)
+	if len(emb.SectionVectors) > 0 {
+		for name, vec := range emb.SectionVectors {
+			if len(sections) > 0 && !contains(sections, name) {
+				continue
+			}
+			sims[name] = CosineSimilarity(queryVec, vec)
+		}
+		return sims
+	}
+
+	if len(sections) > 0 {
+		if len(emb.Sections) == 0 {
+			return sims
+		}
+		for _, s := range emb.Sections {
+			if !contains(sections, s) {
+				return sims
+			}
+		}
+	}
+	sims[""] = CosineSimilarity(queryVec, emb.Vector)
+	return sims
+}
+
+// BestSectionSimilarity returns the highest section similarity and the section
+// it came from. ok is false when no vector applies to the requested sections.
+func BestSectionSimilarity(queryVec []float32, emb *models.Embedding, sections []string) (score float64, section string, ok bool) {
+	sims := SectionSimilarities(queryVec, emb, sections)
+	for _, name := range sortedKeys(sims) {
+		if !ok || sims[name] > score {
+			score, section, ok = sims[name], name, true
+		}
+	}
+	return score, section, ok
+}
+
+// WriteEmbedding writes an embedding sidecar file alongside a journal entry,
+// with one vector for the whole entry and one per non-empty section.
 func WriteEmbedding(mdPath string, embedder Embedder, sections map[string]string) error {
-	// Concatenate all section content for embedding
 	var parts []string
 	var sectionNames []string
-	for name, content := range sections {
-		if content != "" {
-			parts = append(parts, content)
-			sectionNames = append(sectionNames, name)
+	sectionVectors := make(map[string][]float32)
+	for _, name := range sortedSectionNames(sections) {
+		content := sections[name]
+		if content == "" {
+			continue
+		}
+		vec, err := embedder.Embed(content)
+		if err != nil {
+			return err
 		}
+		sectionVectors[name] = vec
+		parts = append(parts, content)
+		sectionNames = append(sectionNames, name)
 	}
 	text := strings.Join(parts, "\n\n")
 
@@ -147,11 +241,14 @@ func WriteEmbedding(mdPath string, embedder Embedder, sections map[string]string
 	}
 
 	emb := models.Embedding{
-		Vector:    vector,
-		Text:      text,
-		Sections:  sectionNames,
-		Timestamp: time.Now().Unix(),
-		Path:      mdPath,
+		Model:          embedder.Name(),
+		Dimension:      embedder.Dimension(),
+		Vector:         vector,
+		SectionVectors: sectionVectors,
+		Text:           text,
+		Sections:       sectionNames,
+		Timestamp:      time.Now().Unix(),
+		Path:           mdPath,
 	}
 
 	data, err := json.Marshal(emb)
@@ -159,6 +256,5 @@ func WriteEmbedding(mdPath string, embedder Embedder, sections map[string]string
 		return err
 	}
 
-	embPath := strings.TrimSuffix(mdPath, ".md") + ".embedding"
-	return mdstore.AtomicWrite(embPath, data)
+	return mdstore.AtomicWrite(EmbeddingPath(mdPath), data)
 }
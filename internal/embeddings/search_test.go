@@ -46,6 +46,10 @@ func (e *testEmbedder) Dimension() int {
 	return e.dim
 }
 
+func (e *testEmbedder) Name() string {
+	return "test"
+}
+
 func TestCosineSimilarityIdentical(t *testing.T) {
 	a := []float32{1, 2, 3}
 	score := CosineSimilarity(a, a)
@@ -129,6 +133,12 @@ func TestWriteEmbedding(t *testing.T) {
 	if len(emb.Sections) != 2 {
 		t.Errorf("expected 2 sections, got %d", len(emb.Sections))
 	}
+	if len(emb.SectionVectors) != 2 || len(emb.SectionVectors["feelings"]) != 8 {
+		t.Errorf("expected one 8-dim vector per section, got %v", emb.SectionVectors)
+	}
+	if emb.Model != "test" || emb.Dimension != 8 {
+		t.Errorf("expected model %q and dimension 8, got %q and %d", "test", emb.Model, emb.Dimension)
+	}
 }
 
 func TestSearchWithEmbeddings(t *testing.T) {
@@ -218,3 +228,73 @@ func TestSearchNonexistentRoot(t *testing.T) {
 		t.Errorf("expected 0 results, got %d", len(results))
 	}
 }
+
+func TestSearchWithEmbeddingsSkipsOtherModels(t *testing.T) {
+	tmpDir := t.TempDir()
+	embedder := &testEmbedder{dim: 8}
+
+	vec, _ := embedder.Embed("content")
+	for name, model := range map[string]string{"ours": "test", "theirs": "other-model"} {
+		emb := models.Embedding{
+			Model:    model,
+			Vector:   vec,
+			Sections: []string{"feelings"},
+			Path:     filepath.Join(tmpDir, name+".md"),
+		}
+		data, _ := json.Marshal(emb)
+		_ = os.WriteFile(filepath.Join(tmpDir, name+".embedding"), data, 0644)
+	}
+
+	// Same model name but a different dimension is also skipped
+	short := models.Embedding{Model: "test", Vector: []float32{1, 0}, Path: filepath.Join(tmpDir, "short.md")}
+	data, _ := json.Marshal(short)
+	_ = os.WriteFile(filepath.Join(tmpDir, "short.embedding"), data, 0644)
+
+	results, err := SearchWithEmbeddings(embedder, []string{tmpDir}, "content", SearchOptions{Limit: 10})
+	if err != nil {
+		t.Fatalf("SearchWithEmbeddings error: %v", err)
+	}
+	if len(results) != 1 || results[0].Path != filepath.Join(tmpDir, "ours.md") {
+		t.Fatalf("expected only the matching model's sidecar, got %+v", results)
+	}
+}
+
+func TestSectionSimilaritiesWholeVectorFallback(t *testing.T) {
+	emb := &models.Embedding{
+		Vector:   []float32{1, 0},
+		Sections: []string{"feelings", "project_notes"},
+	}
+	query := []float32{1, 0}
+
+	if sims := SectionSimilarities(query, emb, nil); len(sims) != 1 {
+		t.Errorf("expected the whole vector unfiltered, got %v", sims)
+	}
+	if sims := SectionSimilarities(query, emb, []string{"feelings", "project_notes"}); len(sims) != 1 {
+		t.Errorf("expected the whole vector when every section is requested, got %v", sims)
+	}
+	// The whole vector also covers project_notes, so it must not score for feelings alone
+	if sims := SectionSimilarities(query, emb, []string{"feelings"}); len(sims) != 0 {
+		t.Errorf("expected no similarity for a subset of sections, got %v", sims)
+	}
+}
+
+func TestQuantizeKeepsSimilarity(t *testing.T) {
+	embedder := NewHashEmbedder(DefaultDimension)
+	a, _ := embedder.Embed("flaky integration tests in the deploy pipeline")
+	b, _ := embedder.Embed("the deploy pipeline retries flaky tests")
+
+	qa, err := Dequantize(Quantize(a))
+	if err != nil {
+		t.Fatalf("Dequantize error: %v", err)
+	}
+	if len(qa) != len(a) {
+		t.Fatalf("expected %d dimensions, got %d", len(a), len(qa))
+	}
+	exact, packed := CosineSimilarity(a, b), CosineSimilarity(qa, b)
+	if math.Abs(exact-packed) > 0.01 {
+		t.Errorf("quantized similarity %f strays from %f", packed, exact)
+	}
+	if _, err := Dequantize("not base64!"); err == nil {
+		t.Error("expected an invalid encoding to be rejected")
+	}
+}
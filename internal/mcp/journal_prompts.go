@@ -0,0 +1,58 @@
+// ABOUTME: MCP prompts serving journal templates, one prompt per template.
+// ABOUTME: Each prompt tells an agent which sections to write with process_thoughts and the questions to answer.
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	gomcp "github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/2389-research/pulse/internal/templates"
+)
+
+// registerJournalPrompts adds a prompt for each of the server's templates.
+func (s *Server) registerJournalPrompts() {
+	for _, t := range s.templates {
+		s.mcp.AddPrompt(&gomcp.Prompt{
+			Name:        t.Name,
+			Description: fmt.Sprintf("Journal template: %s. Fetch it before calling process_thoughts.", t.Description),
+		}, s.handleTemplatePrompt)
+	}
+}
+
+func (s *Server) handleTemplatePrompt(ctx context.Context, req *gomcp.GetPromptRequest) (*gomcp.GetPromptResult, error) {
+	t, err := templates.Find(s.templates, req.Params.Name)
+	if err != nil {
+		return nil, err
+	}
+	return &gomcp.GetPromptResult{
+		Description: t.Description,
+		Messages: []*gomcp.PromptMessage{
+			{Role: "user", Content: &gomcp.TextContent{Text: templatePromptText(t)}},
+		},
+	}, nil
+}
+
+// templatePromptText renders a template as instructions for process_thoughts.
+func templatePromptText(t templates.Template) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Write a journal entry from the %s template", t.Name)
+	if t.Description != "" {
+		fmt.Fprintf(&sb, ": %s", t.Description)
+	}
+	sb.WriteString(".\n\nCall process_thoughts with these sections, answering the guiding questions in your own words. Leave out a section you have nothing for.\n")
+
+	questions := t.Questions()
+	for _, name := range t.Sections() {
+		fmt.Fprintf(&sb, "\n%s:\n", name)
+		for _, q := range questions[name] {
+			fmt.Fprintf(&sb, "- %s\n", q)
+		}
+	}
+	if len(t.Tags) > 0 {
+		fmt.Fprintf(&sb, "\nPass tags: %s.\n", strings.Join(t.Tags, ", "))
+	}
+	return sb.String()
+}
@@ -0,0 +1,47 @@
+// ABOUTME: Tests for the journal template prompts.
+// ABOUTME: Verifies a template prompt lists its sections, guiding questions, and tags for process_thoughts.
+package mcp
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	gomcp "github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/2389-research/pulse/internal/storage"
+	"github.com/2389-research/pulse/internal/templates"
+)
+
+func TestTemplatePrompt(t *testing.T) {
+	tmpDir := t.TempDir()
+	journal, _ := storage.NewJournalMDStore(filepath.Join(tmpDir, "project"), filepath.Join(tmpDir, "user"))
+	social, _ := storage.NewSocialMDStore(filepath.Join(tmpDir, "social"))
+	s, err := NewServer(journal, social, "test", WithTemplates(templates.Builtin()))
+	if err != nil {
+		t.Fatalf("NewServer error: %v", err)
+	}
+
+	result, err := s.handleTemplatePrompt(context.Background(), &gomcp.GetPromptRequest{
+		Params: &gomcp.GetPromptParams{Name: "standup"},
+	})
+	if err != nil {
+		t.Fatalf("prompt error: %v", err)
+	}
+	if len(result.Messages) != 1 {
+		t.Fatalf("expected one message, got %d", len(result.Messages))
+	}
+	text := result.Messages[0].Content.(*gomcp.TextContent).Text
+	for _, want := range []string{"process_thoughts", "project_notes:\n- What did you finish since the last standup?", "feelings:", "Pass tags: standup."} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected prompt to contain %q, got:\n%s", want, text)
+		}
+	}
+
+	if _, err := s.handleTemplatePrompt(context.Background(), &gomcp.GetPromptRequest{
+		Params: &gomcp.GetPromptParams{Name: "nope"},
+	}); err == nil {
+		t.Error("expected an unknown template to fail")
+	}
+}
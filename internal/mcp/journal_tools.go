@@ -1,75 +1,103 @@
 // ABOUTME: MCP tool implementations for journal operations.
-// ABOUTME: Registers process_thoughts, search_journal, read_journal_entry, list_recent_entries.
+// ABOUTME: Registers process_thoughts, search_journal, read_journal_entry, list_recent_entries, journal_digest, and the edit and trash tools.
 package mcp
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	gomcp "github.com/modelcontextprotocol/go-sdk/mcp"
 
+	"github.com/2389-research/pulse/internal/digest"
+	"github.com/2389-research/pulse/internal/embeddings"
 	"github.com/2389-research/pulse/internal/models"
+	"github.com/2389-research/pulse/internal/query"
+	"github.com/2389-research/pulse/internal/redact"
+	"github.com/2389-research/pulse/internal/storage"
 )
 
 func (s *Server) registerJournalTools() {
+	journals := s.journal.Journals()
 	s.mcp.AddTool(&gomcp.Tool{
 		Name:        "process_thoughts",
-		Description: "Write to your private journal. At least one section is required. Sections: feelings, project_notes, user_context, technical_insights, world_knowledge. Routing is automatic: project_notes goes to project journal, all others go to user journal.",
-		InputSchema: json.RawMessage(`{
-			"type": "object",
-			"properties": {
-				"feelings": {"type": "string", "description": "Your private space to be completely honest about what you're feeling and thinking."},
-				"project_notes": {"type": "string", "description": "Private technical laboratory for capturing insights about the current project."},
-				"user_context": {"type": "string", "description": "Private field notes about working with your human collaborator."},
-				"technical_insights": {"type": "string", "description": "Private software engineering notebook for broader learnings."},
-				"world_knowledge": {"type": "string", "description": "Private learning journal for everything else interesting or useful."}
-			},
-			"minProperties": 1
-		}`),
+		Description: processThoughtsDescription(journals),
+		InputSchema: processThoughtsSchema(),
 	}, s.handleProcessThoughts)
 
 	s.mcp.AddTool(&gomcp.Tool{
 		Name:        "search_journal",
-		Description: "Search through your private journal entries using text queries. Returns matching entries ranked by relevance.",
+		Description: "Search through your private journal entries. Plain words are ranked by keyword and semantic relevance. The query also accepts \"quoted phrases\", AND/OR/NOT (or -word), parentheses, and the fields section:, type:, tag:, author:, before:YYYY-MM-DD and after:YYYY-MM-DD; with any of those, only entries matching the whole expression are returned. Each result has a snippet of the matching section. Filter by tags, mood, agent, or git context. Use read_journal_entry to open a full entry. When more results remain, the result ends with a Next cursor line; pass it back as cursor with the same arguments to fetch the next page.",
+		InputSchema: searchJournalSchema(journals),
+	}, s.handleSearchJournal)
+
+	s.mcp.AddTool(&gomcp.Tool{
+		Name:        "read_journal_entry",
+		Description: "Read the full content of a specific journal entry by file path. Set thought to true to also read the entries split from the same process_thoughts call in the other journal.",
 		InputSchema: json.RawMessage(`{
 			"type": "object",
 			"properties": {
-				"query": {"type": "string", "description": "Search query text"},
-				"limit": {"type": "number", "description": "Maximum number of results (default 10)"},
-				"type": {"type": "string", "enum": ["project", "user", "both"], "description": "Search in project-specific, user-global, or both (default: both)"},
-				"sections": {"type": "array", "items": {"type": "string"}, "description": "Filter by section types"}
+				"path": {"type": "string", "description": "File path to the journal entry"},
+				"thought": {"type": "boolean", "description": "Return the whole thought: this entry and its siblings from every journal (default: false)"}
 			},
-			"required": ["query"]
+			"required": ["path"]
 		}`),
-	}, s.handleSearchJournal)
+	}, s.handleReadJournalEntry)
 
 	s.mcp.AddTool(&gomcp.Tool{
-		Name:        "read_journal_entry",
-		Description: "Read the full content of a specific journal entry by file path.",
+		Name:        "list_recent_entries",
+		Description: "List journal entries by date, newest first by default. Narrow by a date range (ISO dates or relative ages like 2w), tags, mood, agent, or git context. When more entries remain, the result ends with a Next cursor line; pass it back as cursor with the same arguments to fetch the next page.",
+		InputSchema: listRecentEntriesSchema(journals),
+	}, s.handleListRecentEntries)
+
+	s.mcp.AddTool(&gomcp.Tool{
+		Name:        "journal_digest",
+		Description: "Summarize the journal over a period, computed locally from the entries: counts per section, type, and day, the most frequent terms, new technical insights, recurring themes in feelings, and the paths of every source entry. Use since 7d for a weekly digest or today for a daily one. Returns markdown, or JSON with format json.",
+		InputSchema: journalDigestSchema(journals),
+	}, s.handleJournalDigest)
+
+	s.mcp.AddTool(&gomcp.Tool{
+		Name:        "update_journal_entry",
+		Description: "Replace sections of an existing journal entry by file path. Only the given sections change; pass an empty string to remove a section. The entry keeps its ID and records when it was updated.",
+		InputSchema: updateJournalEntrySchema(),
+	}, s.handleUpdateJournalEntry)
+
+	s.mcp.AddTool(&gomcp.Tool{
+		Name:        "append_to_journal_entry",
+		Description: "Append text to one section of an existing journal entry, creating the section if it is missing.",
+		InputSchema: appendToJournalEntrySchema(),
+	}, s.handleAppendToJournalEntry)
+
+	s.mcp.AddTool(&gomcp.Tool{
+		Name:        "delete_journal_entry",
+		Description: "Move a journal entry to the trash by file path. Trashed entries are hidden from listing and search and can be brought back with restore_journal_entry.",
 		InputSchema: json.RawMessage(`{
 			"type": "object",
 			"properties": {
-				"path": {"type": "string", "description": "File path to the journal entry"}
+				"path": {"type": "string", "description": "File path to the journal entry"},
+				"reason": {"type": "string", "description": "Why the entry is being deleted; recorded in the trashed entry"}
 			},
 			"required": ["path"]
 		}`),
-	}, s.handleReadJournalEntry)
+	}, s.handleDeleteJournalEntry)
 
 	s.mcp.AddTool(&gomcp.Tool{
-		Name:        "list_recent_entries",
-		Description: "Get recent journal entries in chronological order.",
+		Name:        "restore_journal_entry",
+		Description: "Restore a trashed journal entry to its original location. Accepts the trash path returned by delete_journal_entry or the entry's original path.",
 		InputSchema: json.RawMessage(`{
 			"type": "object",
 			"properties": {
-				"days": {"type": "number", "description": "Number of days back to search (default: 30)"},
-				"limit": {"type": "number", "description": "Maximum number of entries to return (default: 10)"},
-				"type": {"type": "string", "enum": ["project", "user", "both"], "description": "List project-specific, user-global, or both (default: both)"}
-			}
+				"path": {"type": "string", "description": "Trash path or original path of the deleted entry"}
+			},
+			"required": ["path"]
 		}`),
-	}, s.handleListRecentEntries)
+	}, s.handleRestoreJournalEntry)
 }
 
 func (s *Server) handleProcessThoughts(ctx context.Context, req *gomcp.CallToolRequest) (*gomcp.CallToolResult, error) {
@@ -78,10 +106,37 @@ func (s *Server) handleProcessThoughts(ctx context.Context, req *gomcp.CallToolR
 		return toolError("invalid arguments: %v", err), nil
 	}
 
+	meta, err := entryMetaFromArgs(args)
+	if err != nil {
+		return toolError("%v", err), nil
+	}
+	if meta.Agent == "" {
+		// Default to the identity set with the login tool
+		if identity, err := s.social.GetIdentity(); err == nil {
+			meta.Agent = identity
+		}
+	}
+
 	// Collect all sections, rejecting unknown keys
 	var unknownKeys []string
+	var linked, private, optIn bool
 	allSections := make(map[string]string)
 	for key, val := range args {
+		if key == "linked" || key == "private" || key == "sync" {
+			b, ok := val.(bool)
+			if !ok {
+				return toolError("%s must be a boolean", key), nil
+			}
+			switch key {
+			case "linked":
+				linked = b
+			case "private":
+				private = b
+			default:
+				optIn = b
+			}
+			continue
+		}
 		if !models.IsValidSection(key) {
 			unknownKeys = append(unknownKeys, key)
 			continue
@@ -92,52 +147,65 @@ func (s *Server) handleProcessThoughts(ctx context.Context, req *gomcp.CallToolR
 	}
 
 	if len(unknownKeys) > 0 {
-		return toolError("unknown section(s): %s. Valid sections: feelings, project_notes, user_context, technical_insights, world_knowledge",
-			strings.Join(unknownKeys, ", ")), nil
+		sort.Strings(unknownKeys)
+		return toolError("unknown section(s): %s. Valid sections: %s",
+			strings.Join(unknownKeys, ", "), models.ValidSectionList()), nil
 	}
 
 	if len(allSections) == 0 {
-		return toolError("at least one section is required (feelings, project_notes, user_context, technical_insights, world_knowledge)"), nil
+		return toolError("at least one section is required (%s)", models.ValidSectionList()), nil
 	}
 
-	// Split into project and user buckets based on section name
-	projectSections := make(map[string]string)
-	userSections := make(map[string]string)
-	for key, val := range allSections {
-		if key == "project_notes" {
-			projectSections[key] = val
-		} else {
-			userSections[key] = val
+	var missing []string
+	for _, name := range models.RequiredSections() {
+		if _, ok := allSections[name]; !ok {
+			missing = append(missing, name)
 		}
 	}
+	if len(missing) > 0 {
+		return toolError("missing required section(s): %s", strings.Join(missing, ", ")), nil
+	}
 
 	var resultParts []string
 	timestamp := time.Now()
 
-	// Write project entry if any project sections exist
-	if len(projectSections) > 0 {
-		entry := models.NewJournalEntry(projectSections, "project")
-		if err := s.journal.WriteEntry(entry); err != nil {
-			return toolError("failed to write project entry: %v", err), nil
-		}
-		names := sectionNames(projectSections)
-		resultParts = append(resultParts, fmt.Sprintf("[project] %s\nPath: %s", strings.Join(names, ", "), entry.FilePath))
+	// Sections are split between the project and user journals by their route
+	entries, err := s.journal.WriteSections(allSections, storage.RouteOptions{Linked: linked, Meta: meta})
+	for _, entry := range entries {
+		names := models.SectionNames(entry.Sections)
+		resultParts = append(resultParts, fmt.Sprintf("[%s] %s\nPath: %s", entry.Type, strings.Join(names, ", "), entry.FilePath))
 	}
-
-	// Write user entry if any user sections exist
-	if len(userSections) > 0 {
-		entry := models.NewJournalEntry(userSections, "user")
-		if err := s.journal.WriteEntry(entry); err != nil {
-			return toolError("failed to write user entry: %v", err), nil
+	if err != nil {
+		if len(resultParts) > 0 {
+			return toolError("%v\nAlready written:\n%s", err, strings.Join(resultParts, "\n")), nil
 		}
-		names := sectionNames(userSections)
-		resultParts = append(resultParts, fmt.Sprintf("[user] %s\nPath: %s", strings.Join(names, ", "), entry.FilePath))
+		return toolError("%v", err), nil
+	}
+	if len(entries) > 0 && len(entries[0].Tags) > 0 {
+		resultParts = append(resultParts, "Tags: "+strings.Join(entries[0].Tags, ", "))
+	}
+	if linked && len(entries) > 1 {
+		resultParts = append(resultParts, "Entries are linked to each other.")
+	}
+	for _, entry := range entries {
+		resultParts = append(resultParts, redact.Report(entry.Redactions)...)
 	}
 
-	// Sync all sections to remote API if configured
+	// Sync the sections the policy allows to the remote API if configured
 	if s.remote != nil {
-		if err := s.remote.CreateJournalEntry(ctx, allSections, timestamp); err != nil {
-			resultParts = append(resultParts, fmt.Sprintf("Warning: remote sync failed: %v", err))
+		synced, local := models.SyncableSections(allSections, optIn)
+		if private {
+			synced, local = nil, models.SectionNames(allSections)
+		}
+		if len(synced) > 0 {
+			if err := s.remote.CreateJournalEntry(ctx, synced, timestamp); err != nil {
+				resultParts = append(resultParts, fmt.Sprintf("Warning: remote sync failed: %v", err))
+			} else {
+				resultParts = append(resultParts, "Synced to remote: "+strings.Join(models.SectionNames(synced), ", "))
+			}
+		}
+		if len(local) > 0 {
+			resultParts = append(resultParts, "Kept local: "+strings.Join(local, ", "))
 		}
 	}
 
@@ -148,21 +216,15 @@ func (s *Server) handleProcessThoughts(ctx context.Context, req *gomcp.CallToolR
 	}, nil
 }
 
-// sectionNames returns sorted section names from a sections map.
-func sectionNames(sections map[string]string) []string {
-	names := make([]string, 0, len(sections))
-	for name := range sections {
-		names = append(names, name)
-	}
-	return names
-}
-
 func (s *Server) handleSearchJournal(ctx context.Context, req *gomcp.CallToolRequest) (*gomcp.CallToolResult, error) {
 	var args struct {
 		Query    string   `json:"query"`
 		Limit    int      `json:"limit"`
 		Type     string   `json:"type"`
 		Sections []string `json:"sections"`
+		Days     int      `json:"days"`
+		Cursor   string   `json:"cursor"`
+		metaFilterArgs
 	}
 	if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
 		return toolError("invalid arguments: %v", err), nil
@@ -171,50 +233,45 @@ func (s *Server) handleSearchJournal(ctx context.Context, req *gomcp.CallToolReq
 	if args.Query == "" {
 		return toolError("query is required"), nil
 	}
-	if args.Type != "" && args.Type != "project" && args.Type != "user" && args.Type != "both" {
-		return toolError("invalid type %q: must be one of: project, user, both", args.Type), nil
+	if _, err := models.SelectJournals(args.Type, s.journal.Journals()); err != nil {
+		return toolError("invalid type: %v", err), nil
 	}
 	if args.Limit <= 0 {
 		args.Limit = 10
 	}
 	if args.Type == "" {
-		args.Type = "both"
+		args.Type = "all"
 	}
 
-	// List all entries, then filter by substring match
-	entries, err := s.journal.ListEntries(args.Type, 0, 0)
+	scopeArgs := args
+	scopeArgs.Cursor, scopeArgs.Limit = "", 0
+	scope := pageScope("search_journal", scopeArgs)
+	after, err := parsePageCursor(args.Cursor, scope)
 	if err != nil {
-		return toolError("failed to list entries: %v", err), nil
+		return toolError("%v", err), nil
 	}
 
-	queryLower := strings.ToLower(args.Query)
-	var results []*models.JournalEntry
-
-	for _, entry := range entries {
-		if len(results) >= args.Limit {
-			break
-		}
-
-		for sectionName, sectionContent := range entry.Sections {
-			// Filter by requested sections if specified
-			if len(args.Sections) > 0 {
-				found := false
-				for _, s := range args.Sections {
-					if s == sectionName {
-						found = true
-						break
-					}
-				}
-				if !found {
-					continue
-				}
-			}
-
-			if strings.Contains(strings.ToLower(sectionContent), queryLower) {
-				results = append(results, entry)
-				break
-			}
-		}
+	// Ask for one extra result to learn whether another page follows
+	opts := embeddings.SearchOptions{
+		Limit:    args.Limit + 1,
+		Type:     args.Type,
+		Sections: args.Sections,
+		Filter:   args.filter(),
+		After:    after,
+	}
+	if args.Days > 0 {
+		opts.Since = query.DaysAgo(args.Days, time.Now())
+	}
+	results, err := s.journal.Search(args.Query, opts)
+	if err != nil {
+		return toolError("failed to search entries: %v", err), nil
+	}
+	var next string
+	if len(results) > args.Limit {
+		results = results[:args.Limit]
+		cursor := embeddings.RankCursor(results[len(results)-1])
+		cursor.Scope = scope
+		next = cursor.Encode()
 	}
 
 	if len(results) == 0 {
@@ -223,18 +280,43 @@ func (s *Server) handleSearchJournal(ctx context.Context, req *gomcp.CallToolReq
 		}, nil
 	}
 
+	// Siblings from one thought are reported as one hit under the best score
 	var sb strings.Builder
-	for i, entry := range results {
+	groups := models.GroupByThought(results, func(r embeddings.SearchResult) *models.JournalEntry { return r.Entry })
+	for i, group := range groups {
 		if i > 0 {
 			sb.WriteString("\n---\n")
 		}
+		result := group[0]
+		entry := result.Entry
 		sb.WriteString(fmt.Sprintf("Entry: %s\n", entry.FilePath))
+		for _, sibling := range group[1:] {
+			sb.WriteString(fmt.Sprintf("Also: %s\n", sibling.Entry.FilePath))
+		}
+		if entry.ThoughtID != uuid.Nil {
+			sb.WriteString(fmt.Sprintf("Thought: %s\n", entry.ThoughtID))
+		}
 		sb.WriteString(fmt.Sprintf("Date: %s\n", entry.CreatedAt.Format("2006-01-02 15:04:05")))
-		sb.WriteString(fmt.Sprintf("Type: %s\n", entry.Type))
-		for name, content := range entry.Sections {
-			sb.WriteString(fmt.Sprintf("\n## %s\n%s\n", models.SectionTitle(name), content))
+		types := []string{entry.Type}
+		sections := models.SectionNames(entry.Sections)
+		for _, sibling := range group[1:] {
+			types = append(types, sibling.Entry.Type)
+			sections = append(sections, models.SectionNames(sibling.Entry.Sections)...)
+		}
+		sb.WriteString(fmt.Sprintf("Type: %s\n", strings.Join(types, ", ")))
+		sb.WriteString(fmt.Sprintf("Score: %.3f\n", result.Score))
+		sb.WriteString(fmt.Sprintf("Sections: %s\n", strings.Join(sections, ", ")))
+		if len(entry.Tags) > 0 {
+			sb.WriteString(fmt.Sprintf("Tags: %s\n", strings.Join(entry.Tags, ", ")))
+		}
+		if result.Section != "" {
+			sb.WriteString(fmt.Sprintf("Matched section: %s\n", result.Section))
+		}
+		if result.Snippet != "" {
+			sb.WriteString(fmt.Sprintf("Snippet: %s\n", result.Snippet))
 		}
 	}
+	writeNextCursor(&sb, next)
 
 	return &gomcp.CallToolResult{
 		Content: []gomcp.Content{&gomcp.TextContent{Text: sb.String()}},
@@ -243,7 +325,8 @@ func (s *Server) handleSearchJournal(ctx context.Context, req *gomcp.CallToolReq
 
 func (s *Server) handleReadJournalEntry(ctx context.Context, req *gomcp.CallToolRequest) (*gomcp.CallToolResult, error) {
 	var args struct {
-		Path string `json:"path"`
+		Path    string `json:"path"`
+		Thought bool   `json:"thought"`
 	}
 	if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
 		return toolError("invalid arguments: %v", err), nil
@@ -253,16 +336,30 @@ func (s *Server) handleReadJournalEntry(ctx context.Context, req *gomcp.CallTool
 		return toolError("path is required"), nil
 	}
 
-	entry, err := s.journal.ReadEntry(args.Path)
-	if err != nil {
-		return toolError("failed to read entry: %v", err), nil
+	var entries []*models.JournalEntry
+	if args.Thought {
+		thought, err := s.journal.ReadThought(args.Path)
+		if err != nil {
+			return toolError("failed to read thought: %v", err), nil
+		}
+		entries = thought
+	} else {
+		entry, err := s.journal.ReadEntry(args.Path)
+		if err != nil {
+			return toolError("failed to read entry: %v", err), nil
+		}
+		entries = []*models.JournalEntry{entry}
 	}
 
 	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("Date: %s\n", entry.CreatedAt.Format("2006-01-02 15:04:05")))
-	sb.WriteString(fmt.Sprintf("Type: %s\n", entry.Type))
-	for name, content := range entry.Sections {
-		sb.WriteString(fmt.Sprintf("\n## %s\n%s\n", models.SectionTitle(name), content))
+	for i, entry := range entries {
+		if i > 0 {
+			sb.WriteString("\n---\n")
+		}
+		if len(entries) > 1 {
+			sb.WriteString(fmt.Sprintf("Entry: %s\n", entry.FilePath))
+		}
+		writeEntry(&sb, entry)
 	}
 
 	return &gomcp.CallToolResult{
@@ -270,33 +367,126 @@ func (s *Server) handleReadJournalEntry(ctx context.Context, req *gomcp.CallTool
 	}, nil
 }
 
+// sectionSummary lists an entry's sections for a listing line, or notes that
+// its body is encrypted and could not be read.
+func sectionSummary(entry *models.JournalEntry) string {
+	if entry.Locked {
+		return "encrypted"
+	}
+	return strings.Join(models.SectionNames(entry.Sections), ", ")
+}
+
+// writeEntry formats an entry's metadata and sections for read_journal_entry.
+func writeEntry(sb *strings.Builder, entry *models.JournalEntry) {
+	sb.WriteString(fmt.Sprintf("Date: %s\n", entry.CreatedAt.Format("2006-01-02 15:04:05")))
+	if !entry.UpdatedAt.IsZero() {
+		sb.WriteString(fmt.Sprintf("Updated: %s\n", entry.UpdatedAt.Format("2006-01-02 15:04:05")))
+	}
+	sb.WriteString(fmt.Sprintf("Type: %s\n", entry.Type))
+	if entry.ThoughtID != uuid.Nil {
+		sb.WriteString(fmt.Sprintf("Thought: %s\n", entry.ThoughtID))
+	}
+	if len(entry.LinkedIDs) > 0 {
+		ids := make([]string, len(entry.LinkedIDs))
+		for i, id := range entry.LinkedIDs {
+			ids[i] = id.String()
+		}
+		sb.WriteString(fmt.Sprintf("Linked: %s\n", strings.Join(ids, ", ")))
+	}
+	for _, field := range entry.Fields() {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", field[0], field[1]))
+	}
+	for _, name := range models.SectionNames(entry.Sections) {
+		sb.WriteString(fmt.Sprintf("\n## %s\n%s\n", models.SectionTitle(name), entry.Sections[name]))
+	}
+}
+
 func (s *Server) handleListRecentEntries(ctx context.Context, req *gomcp.CallToolRequest) (*gomcp.CallToolResult, error) {
 	var args struct {
-		Days  int    `json:"days"`
-		Limit int    `json:"limit"`
-		Type  string `json:"type"`
+		Days   int    `json:"days"`
+		Since  string `json:"since"`
+		Until  string `json:"until"`
+		Offset int    `json:"offset"`
+		Limit  int    `json:"limit"`
+		Order  string `json:"order"`
+		Type   string `json:"type"`
+		Cursor string `json:"cursor"`
+		metaFilterArgs
 	}
 	if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
 		return toolError("invalid arguments: %v", err), nil
 	}
 
-	if args.Type != "" && args.Type != "project" && args.Type != "user" && args.Type != "both" {
-		return toolError("invalid type %q: must be one of: project, user, both", args.Type), nil
+	if _, err := models.SelectJournals(args.Type, s.journal.Journals()); err != nil {
+		return toolError("invalid type: %v", err), nil
+	}
+	if args.Order != "" && args.Order != "newest" && args.Order != "oldest" {
+		return toolError("invalid order %q: must be newest or oldest", args.Order), nil
 	}
-	if args.Days <= 0 {
-		args.Days = 30
+	if args.Offset < 0 {
+		return toolError("offset must be non-negative, got %d", args.Offset), nil
+	}
+	if args.Cursor != "" && args.Offset > 0 {
+		return toolError("cursor and offset cannot be combined"), nil
 	}
 	if args.Limit <= 0 {
 		args.Limit = 10
 	}
 	if args.Type == "" {
-		args.Type = "both"
+		args.Type = "all"
+	}
+
+	now := time.Now()
+	since, until, err := query.ParseBounds(args.Since, args.Until, now)
+	if err != nil {
+		return toolError("%v", err), nil
+	}
+	// days is shorthand for since; with no bounds at all, look back 30 days.
+	// ParseBounds has already checked an explicit since against until.
+	if args.Since == "" {
+		if args.Days <= 0 && args.Until == "" {
+			args.Days = 30
+		}
+		if args.Days > 0 {
+			since = query.DaysAgo(args.Days, now)
+			if !until.IsZero() && !since.Before(until) {
+				return toolError("days (%d) must start before until (%s)", args.Days, args.Until), nil
+			}
+		}
 	}
 
-	entries, err := s.journal.ListEntries(args.Type, args.Limit, args.Days)
+	scopeArgs := args
+	scopeArgs.Cursor, scopeArgs.Limit = "", 0
+	scope := pageScope("list_recent_entries", scopeArgs)
+	after, err := parsePageCursor(args.Cursor, scope)
+	if err != nil {
+		return toolError("%v", err), nil
+	}
+
+	// Ask for one extra entry to learn whether another page follows
+	opts := storage.ListOptions{
+		Type:   args.Type,
+		Since:  since,
+		Until:  until,
+		Offset: args.Offset,
+		Limit:  args.Limit + 1,
+		Filter: args.filter(),
+		After:  after,
+	}
+	if args.Order == "oldest" {
+		opts.Order = storage.OldestFirst
+	}
+	entries, err := s.journal.ListEntries(opts)
 	if err != nil {
 		return toolError("failed to list entries: %v", err), nil
 	}
+	var next string
+	if len(entries) > args.Limit {
+		entries = entries[:args.Limit]
+		cursor := models.CursorAt(entries[len(entries)-1])
+		cursor.Scope = scope
+		next = cursor.Encode()
+	}
 
 	if len(entries) == 0 {
 		return &gomcp.CallToolResult{
@@ -304,28 +494,504 @@ func (s *Server) handleListRecentEntries(ctx context.Context, req *gomcp.CallToo
 		}, nil
 	}
 
+	// Entries split from one write are listed together as one thought
 	var sb strings.Builder
-	for i, entry := range entries {
+	for i, group := range models.GroupByThought(entries, func(e *models.JournalEntry) *models.JournalEntry { return e }) {
 		if i > 0 {
 			sb.WriteString("\n")
 		}
-		sectionNames := make([]string, 0, len(entry.Sections))
-		for name := range entry.Sections {
-			sectionNames = append(sectionNames, name)
-		}
-		sb.WriteString(fmt.Sprintf("- %s [%s] (%s) %s\n",
+		entry := group[0]
+		sb.WriteString(fmt.Sprintf("- %s [%s] (%s) %s%s\n",
 			entry.CreatedAt.Format("2006-01-02 15:04:05"),
 			entry.Type,
-			strings.Join(sectionNames, ", "),
+			sectionSummary(entry),
 			entry.FilePath,
+			hashTags(entry.Tags),
 		))
+		for _, sibling := range group[1:] {
+			sb.WriteString(fmt.Sprintf("  + [%s] (%s) %s\n",
+				sibling.Type,
+				sectionSummary(sibling),
+				sibling.FilePath,
+			))
+		}
 	}
+	writeNextCursor(&sb, next)
 
 	return &gomcp.CallToolResult{
 		Content: []gomcp.Content{&gomcp.TextContent{Text: sb.String()}},
 	}, nil
 }
 
+func (s *Server) handleJournalDigest(ctx context.Context, req *gomcp.CallToolRequest) (*gomcp.CallToolResult, error) {
+	var args struct {
+		Since  string `json:"since"`
+		Until  string `json:"until"`
+		Type   string `json:"type"`
+		Format string `json:"format"`
+		Top    int    `json:"top"`
+		metaFilterArgs
+	}
+	if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+		return toolError("invalid arguments: %v", err), nil
+	}
+
+	if _, err := models.SelectJournals(args.Type, s.journal.Journals()); err != nil {
+		return toolError("invalid type: %v", err), nil
+	}
+	if args.Format != "" && args.Format != "markdown" && args.Format != "json" {
+		return toolError("invalid format %q: must be markdown or json", args.Format), nil
+	}
+	if args.Since == "" {
+		args.Since = "7d"
+	}
+	if args.Type == "" {
+		args.Type = "all"
+	}
+
+	since, until, err := query.ParseBounds(args.Since, args.Until, time.Now())
+	if err != nil {
+		return toolError("%v", err), nil
+	}
+	entries, err := s.journal.ListEntries(storage.ListOptions{
+		Type:   args.Type,
+		Since:  since,
+		Until:  until,
+		Order:  storage.OldestFirst,
+		Filter: args.filter(),
+	})
+	if err != nil {
+		return toolError("failed to list entries: %v", err), nil
+	}
+
+	d := digest.Build(entries, digest.Options{Since: since, Until: until, TopTerms: args.Top})
+	text := d.Markdown()
+	if args.Format == "json" {
+		data, err := d.JSON()
+		if err != nil {
+			return toolError("%v", err), nil
+		}
+		text = string(data)
+	}
+	return &gomcp.CallToolResult{
+		Content: []gomcp.Content{&gomcp.TextContent{Text: text}},
+	}, nil
+}
+
+func (s *Server) handleUpdateJournalEntry(ctx context.Context, req *gomcp.CallToolRequest) (*gomcp.CallToolResult, error) {
+	var args map[string]interface{}
+	if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+		return toolError("invalid arguments: %v", err), nil
+	}
+
+	path, _ := args["path"].(string)
+	if path == "" {
+		return toolError("path is required"), nil
+	}
+
+	var unknownKeys []string
+	sections := make(map[string]string)
+	for key, val := range args {
+		if key == "path" {
+			continue
+		}
+		if !models.IsValidSection(key) {
+			unknownKeys = append(unknownKeys, key)
+			continue
+		}
+		str, ok := val.(string)
+		if !ok {
+			return toolError("section %s must be a string", key), nil
+		}
+		sections[key] = str
+	}
+
+	if len(unknownKeys) > 0 {
+		sort.Strings(unknownKeys)
+		return toolError("unknown section(s): %s. Valid sections: %s",
+			strings.Join(unknownKeys, ", "), models.ValidSectionList()), nil
+	}
+	if len(sections) == 0 {
+		return toolError("at least one section to update is required"), nil
+	}
+
+	entry, err := s.journal.UpdateEntry(path, sections)
+	if err != nil {
+		return toolError("failed to update entry: %v", err), nil
+	}
+
+	resultParts := []string{fmt.Sprintf("[%s] %s\nPath: %s", entry.Type, strings.Join(models.SectionNames(entry.Sections), ", "), entry.FilePath)}
+	resultParts = append(resultParts, redact.Report(entry.Redactions)...)
+	return &gomcp.CallToolResult{
+		Content: []gomcp.Content{&gomcp.TextContent{
+			Text: "Journal entry updated:\n" + strings.Join(resultParts, "\n"),
+		}},
+	}, nil
+}
+
+func (s *Server) handleAppendToJournalEntry(ctx context.Context, req *gomcp.CallToolRequest) (*gomcp.CallToolResult, error) {
+	var args struct {
+		Path    string `json:"path"`
+		Section string `json:"section"`
+		Content string `json:"content"`
+	}
+	if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+		return toolError("invalid arguments: %v", err), nil
+	}
+
+	if args.Path == "" {
+		return toolError("path is required"), nil
+	}
+	if !models.IsValidSection(args.Section) {
+		return toolError("invalid section %q. Valid sections: %s", args.Section, models.ValidSectionList()), nil
+	}
+	if strings.TrimSpace(args.Content) == "" {
+		return toolError("content is required"), nil
+	}
+
+	entry, err := s.journal.AppendToSection(args.Path, args.Section, args.Content)
+	if err != nil {
+		return toolError("failed to append to entry: %v", err), nil
+	}
+
+	resultParts := []string{"Path: " + entry.FilePath}
+	resultParts = append(resultParts, redact.Report(entry.Redactions)...)
+	return &gomcp.CallToolResult{
+		Content: []gomcp.Content{&gomcp.TextContent{
+			Text: fmt.Sprintf("Appended to %s:\n%s", args.Section, strings.Join(resultParts, "\n")),
+		}},
+	}, nil
+}
+
+func (s *Server) handleDeleteJournalEntry(ctx context.Context, req *gomcp.CallToolRequest) (*gomcp.CallToolResult, error) {
+	var args struct {
+		Path   string `json:"path"`
+		Reason string `json:"reason"`
+	}
+	if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+		return toolError("invalid arguments: %v", err), nil
+	}
+
+	if args.Path == "" {
+		return toolError("path is required"), nil
+	}
+
+	entry, err := s.journal.DeleteEntry(args.Path, args.Reason)
+	if err != nil {
+		return toolError("failed to delete entry: %v", err), nil
+	}
+
+	return &gomcp.CallToolResult{
+		Content: []gomcp.Content{&gomcp.TextContent{
+			Text: fmt.Sprintf("Journal entry moved to trash: %s\nUse restore_journal_entry to undo.", entry.FilePath),
+		}},
+	}, nil
+}
+
+func (s *Server) handleRestoreJournalEntry(ctx context.Context, req *gomcp.CallToolRequest) (*gomcp.CallToolResult, error) {
+	var args struct {
+		Path string `json:"path"`
+	}
+	if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+		return toolError("invalid arguments: %v", err), nil
+	}
+
+	if args.Path == "" {
+		return toolError("path is required"), nil
+	}
+
+	entry, err := s.journal.RestoreEntry(args.Path)
+	if err != nil {
+		return toolError("failed to restore entry: %v", err), nil
+	}
+
+	return &gomcp.CallToolResult{
+		Content: []gomcp.Content{&gomcp.TextContent{Text: fmt.Sprintf("Journal entry restored:\nPath: %s", entry.FilePath)}},
+	}, nil
+}
+
+// processThoughtsDescription describes process_thoughts, listing the
+// configured sections and which of journals each routes to.
+func processThoughtsDescription(journals []string) string {
+	var names []string
+	routes := make(map[string][]string)
+	for _, def := range models.Sections() {
+		names = append(names, def.Name)
+		routes[def.Route] = append(routes[def.Route], def.Name)
+	}
+
+	desc := fmt.Sprintf("Write to your private journal. At least one section is required. Sections: %s.", strings.Join(names, ", "))
+	if required := models.RequiredSections(); len(required) > 0 {
+		desc += fmt.Sprintf(" Required: %s.", strings.Join(required, ", "))
+	}
+	var routing []string
+	for _, journal := range journals {
+		routed := routes[journal]
+		switch {
+		case journal == "user" || len(routed) == 0:
+		case len(routed) == 1:
+			routing = append(routing, fmt.Sprintf("%s goes to %s journal", routed[0], journal))
+		default:
+			routing = append(routing, fmt.Sprintf("%s go to %s journal", strings.Join(routed, ", "), journal))
+		}
+	}
+	if len(routing) == 0 {
+		desc += " All sections go to the user journal."
+	} else {
+		desc += fmt.Sprintf(" Routing is automatic: %s, all others go to user journal.", strings.Join(routing, "; "))
+	}
+	desc += " Optional tags, mood, valence, and git context are stored with the entry for later filtering."
+	if optIn := syncOptInSections(); len(optIn) > 0 {
+		desc += fmt.Sprintf(" When remote sync is configured, %s stay local unless sync is true.", strings.Join(optIn, ", "))
+	}
+	desc += " Set private to keep the whole write local."
+	return desc
+}
+
+// syncOptInSections returns the names of sections sent to the remote API only
+// when a write opts in.
+func syncOptInSections() []string {
+	var names []string
+	for _, def := range models.Sections() {
+		if def.Sync == models.SyncOptIn {
+			names = append(names, def.Name)
+		}
+	}
+	return names
+}
+
+// sectionProperties builds one string property per configured section.
+// describe returns the property description for a section.
+func sectionProperties(describe func(models.SectionDef) string) map[string]interface{} {
+	props := make(map[string]interface{})
+	for _, def := range models.Sections() {
+		props[def.Name] = map[string]interface{}{
+			"type":        "string",
+			"description": describe(def),
+		}
+	}
+	return props
+}
+
+// mustSchema marshals a JSON schema built from plain maps.
+func mustSchema(schema map[string]interface{}) json.RawMessage {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		panic(fmt.Sprintf("failed to marshal tool schema: %v", err))
+	}
+	return data
+}
+
+// processThoughtsSchema builds the process_thoughts input schema from the section schema.
+func processThoughtsSchema() json.RawMessage {
+	props := sectionProperties(func(def models.SectionDef) string {
+		if def.Description != "" {
+			return def.Description
+		}
+		return models.SectionTitle(def.Name) + " section content"
+	})
+	for name, prop := range entryMetaProperties() {
+		props[name] = prop
+	}
+	props["linked"] = map[string]interface{}{
+		"type":        "boolean",
+		"description": "When sections are split between journals, record each entry's ID in the others (default: false)",
+	}
+	props["private"] = map[string]interface{}{
+		"type":        "boolean",
+		"description": "Keep every section of this write local, even when remote sync is configured (default: false)",
+	}
+	props["sync"] = map[string]interface{}{
+		"type":        "boolean",
+		"description": "Also send sections whose sync policy is opt-in, such as feelings and user_context, to the remote API (default: false)",
+	}
+	schema := map[string]interface{}{
+		"type":          "object",
+		"properties":    props,
+		"minProperties": 1,
+	}
+	if required := models.RequiredSections(); len(required) > 0 {
+		schema["required"] = required
+	}
+	return mustSchema(schema)
+}
+
+// updateJournalEntrySchema builds the update_journal_entry input schema from the section schema.
+func updateJournalEntrySchema() json.RawMessage {
+	props := sectionProperties(func(def models.SectionDef) string {
+		return "New content for the " + strings.ToLower(models.SectionTitle(def.Name)) + " section"
+	})
+	props["path"] = map[string]interface{}{"type": "string", "description": "File path to the journal entry"}
+	return mustSchema(map[string]interface{}{
+		"type":          "object",
+		"properties":    props,
+		"required":      []string{"path"},
+		"minProperties": 2,
+	})
+}
+
+// appendToJournalEntrySchema builds the append_to_journal_entry input schema from the section schema.
+func appendToJournalEntrySchema() json.RawMessage {
+	return mustSchema(map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"path":    map[string]interface{}{"type": "string", "description": "File path to the journal entry"},
+			"section": map[string]interface{}{"type": "string", "enum": models.GetValidSections(), "description": "Section to append to"},
+			"content": map[string]interface{}{"type": "string", "description": "Text to append"},
+		},
+		"required": []string{"path", "section", "content"},
+	})
+}
+
+// searchJournalSchema builds the search_journal input schema.
+func searchJournalSchema(journals []string) json.RawMessage {
+	props := metaFilterProperties()
+	props["query"] = map[string]interface{}{"type": "string", "description": "Search query, e.g. deploy or \"flaky test\" AND tag:ci NOT section:feelings"}
+	props["limit"] = map[string]interface{}{"type": "number", "description": "Maximum number of results (default 10)"}
+	props["type"] = journalTypeProperty("Journals to search", journals)
+	props["sections"] = map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}, "description": "Filter by section types"}
+	props["days"] = map[string]interface{}{"type": "number", "description": "Only search entries from this many days back (default: all)"}
+	props["cursor"] = map[string]interface{}{"type": "string", "description": "Next cursor from a previous search_journal result with the same arguments, to fetch the following page"}
+	return mustSchema(map[string]interface{}{
+		"type":       "object",
+		"properties": props,
+		"required":   []string{"query"},
+	})
+}
+
+// listRecentEntriesSchema builds the list_recent_entries input schema.
+func listRecentEntriesSchema(journals []string) json.RawMessage {
+	props := metaFilterProperties()
+	props["days"] = map[string]interface{}{"type": "number", "description": "Number of days back to search (default: 30 when neither since nor until is set; ignored when since is set)"}
+	props["since"] = map[string]interface{}{"type": "string", "description": "Earliest entries to include: YYYY-MM-DD, a timestamp, today, yesterday, or an age like 3d or 2w (local time)"}
+	props["until"] = map[string]interface{}{"type": "string", "description": "Latest entries to include, in the same forms as since; a date includes that whole day"}
+	props["offset"] = map[string]interface{}{"type": "number", "description": "Number of entries to skip, for paging (default: 0)"}
+	props["order"] = map[string]interface{}{"type": "string", "enum": []string{"newest", "oldest"}, "description": "Sort order (default: newest)"}
+	props["limit"] = map[string]interface{}{"type": "number", "description": "Maximum number of entries to return (default: 10)"}
+	props["cursor"] = map[string]interface{}{"type": "string", "description": "Next cursor from a previous list_recent_entries result with the same arguments, to fetch the following page"}
+	props["type"] = journalTypeProperty("Journals to list", journals)
+	return mustSchema(map[string]interface{}{
+		"type":       "object",
+		"properties": props,
+	})
+}
+
+func journalDigestSchema(journals []string) json.RawMessage {
+	props := metaFilterProperties()
+	props["since"] = map[string]interface{}{"type": "string", "description": "Start of the period: YYYY-MM-DD, a timestamp, today, yesterday, or an age like 7d (default: 7d)"}
+	props["until"] = map[string]interface{}{"type": "string", "description": "End of the period, in the same forms as since (default: now)"}
+	props["type"] = journalTypeProperty("Journals to summarize", journals)
+	props["format"] = map[string]interface{}{"type": "string", "enum": []string{"markdown", "json"}, "description": "Output format (default: markdown)"}
+	props["top"] = map[string]interface{}{"type": "number", "description": "Number of frequent terms to report (default: 10)"}
+	return mustSchema(map[string]interface{}{
+		"type":       "object",
+		"properties": props,
+	})
+}
+
+// journalTypeProperty describes a type argument selecting among journals;
+// what starts the description, e.g. "Journals to search".
+func journalTypeProperty(what string, journals []string) map[string]interface{} {
+	return map[string]interface{}{
+		"type":        "string",
+		"description": fmt.Sprintf("%s: one of %s, a comma-separated list of them, or all (default: all)", what, strings.Join(journals, ", ")),
+	}
+}
+
+// entryMetaKeys are the process_thoughts arguments recorded as entry metadata.
+var entryMetaKeys = []string{"tags", "mood", "valence", "agent", "repo", "branch", "commit", "attributes"}
+
+// entryMetaProperties describes the metadata arguments of process_thoughts.
+func entryMetaProperties() map[string]interface{} {
+	return map[string]interface{}{
+		"tags":       map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}, "description": "Tags for later filtering, e.g. incident"},
+		"mood":       map[string]interface{}{"type": "string", "description": "One word for your mood, e.g. frustrated"},
+		"valence":    map[string]interface{}{"type": "number", "minimum": -1, "maximum": 1, "description": "How positive the mood is, from -1 (negative) to 1 (positive)"},
+		"agent":      map[string]interface{}{"type": "string", "description": "Agent identity (default: the name set with login)"},
+		"repo":       map[string]interface{}{"type": "string", "description": "Repository the thought is about (default for project entries: the enclosing git repository)"},
+		"branch":     map[string]interface{}{"type": "string", "description": "Git branch the thought is about (default for project entries: the current branch)"},
+		"commit":     map[string]interface{}{"type": "string", "description": "Git commit the thought is about (default for project entries: HEAD)"},
+		"attributes": map[string]interface{}{"type": "object", "additionalProperties": map[string]interface{}{"type": "string"}, "description": "Free-form string key/value pairs"},
+	}
+}
+
+// entryMetaFromArgs removes the metadata arguments from args and decodes them.
+func entryMetaFromArgs(args map[string]interface{}) (models.EntryMeta, error) {
+	raw := make(map[string]interface{})
+	for _, key := range entryMetaKeys {
+		if val, ok := args[key]; ok {
+			raw[key] = val
+			delete(args, key)
+		}
+	}
+	var meta models.EntryMeta
+	if len(raw) == 0 {
+		return meta, nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return meta, fmt.Errorf("invalid metadata: %w", err)
+	}
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return meta, fmt.Errorf("invalid metadata: %w", err)
+	}
+	if err := meta.Validate(); err != nil {
+		return meta, err
+	}
+	return meta, nil
+}
+
+// metaFilterArgs are the metadata filters shared by search_journal and list_recent_entries.
+type metaFilterArgs struct {
+	Tags       []string          `json:"tags"`
+	Mood       string            `json:"mood"`
+	MinValence *float64          `json:"min_valence"`
+	MaxValence *float64          `json:"max_valence"`
+	Agent      string            `json:"agent"`
+	Repo       string            `json:"repo"`
+	Branch     string            `json:"branch"`
+	Commit     string            `json:"commit"`
+	Attributes map[string]string `json:"attributes"`
+}
+
+// filter converts the arguments to a storage filter.
+func (a metaFilterArgs) filter() models.MetaFilter {
+	return models.MetaFilter{
+		Tags:       a.Tags,
+		Mood:       a.Mood,
+		MinValence: a.MinValence,
+		MaxValence: a.MaxValence,
+		Agent:      a.Agent,
+		Repo:       a.Repo,
+		Branch:     a.Branch,
+		Commit:     a.Commit,
+		Attributes: a.Attributes,
+	}
+}
+
+// metaFilterProperties describes metaFilterArgs for tool schemas.
+func metaFilterProperties() map[string]interface{} {
+	return map[string]interface{}{
+		"tags":        map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}, "description": "Only entries carrying all of these tags"},
+		"mood":        map[string]interface{}{"type": "string", "description": "Only entries with this mood"},
+		"min_valence": map[string]interface{}{"type": "number", "description": "Only entries with valence at least this"},
+		"max_valence": map[string]interface{}{"type": "number", "description": "Only entries with valence at most this"},
+		"agent":       map[string]interface{}{"type": "string", "description": "Only entries written by this agent"},
+		"repo":        map[string]interface{}{"type": "string", "description": "Only entries about this repository (its root path for captured context)"},
+		"branch":      map[string]interface{}{"type": "string", "description": "Only entries from this git branch"},
+		"commit":      map[string]interface{}{"type": "string", "description": "Only entries from this commit (prefix match)"},
+		"attributes":  map[string]interface{}{"type": "object", "additionalProperties": map[string]interface{}{"type": "string"}, "description": "Only entries with all of these attribute values"},
+	}
+}
+
+// hashTags formats tags as a " #a #b" suffix, or "" without tags.
+func hashTags(tags []string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	return " #" + strings.Join(tags, " #")
+}
+
 // toolError creates an error result for MCP tool responses.
 func toolError(format string, args ...interface{}) *gomcp.CallToolResult {
 	return &gomcp.CallToolResult{
@@ -333,3 +999,30 @@ func toolError(format string, args ...interface{}) *gomcp.CallToolResult {
 		IsError: true,
 	}
 }
+
+// pageScope fingerprints the arguments that define a listing or search, so
+// a cursor is only honored by the request that issued it.
+func pageScope(tool string, args interface{}) string {
+	data, _ := json.Marshal(args)
+	sum := sha256.Sum256(append([]byte(tool+"\x00"), data...))
+	return hex.EncodeToString(sum[:8])
+}
+
+// parsePageCursor decodes a cursor argument and checks it belongs to scope.
+func parsePageCursor(token, scope string) (models.Cursor, error) {
+	cursor, err := models.ParseCursor(token)
+	if err != nil {
+		return cursor, err
+	}
+	if token != "" && cursor.Scope != scope {
+		return models.Cursor{}, fmt.Errorf("cursor was issued for different arguments; repeat the original arguments to continue")
+	}
+	return cursor, nil
+}
+
+// writeNextCursor ends a page with the cursor for the next one, if any.
+func writeNextCursor(sb *strings.Builder, next string) {
+	if next != "" {
+		sb.WriteString(fmt.Sprintf("\nNext cursor: %s\n", next))
+	}
+}
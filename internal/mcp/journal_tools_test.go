@@ -1,19 +1,25 @@
 // ABOUTME: Tests for journal MCP tool handlers.
-// ABOUTME: Covers process_thoughts, search_journal, read_journal_entry, list_recent_entries.
+// ABOUTME: Covers process_thoughts, search_journal, read_journal_entry, list_recent_entries, journal_digest, and the edit and trash tools.
 package mcp
 
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	gomcp "github.com/modelcontextprotocol/go-sdk/mcp"
 
+	"github.com/2389-research/pulse/internal/models"
+	"github.com/2389-research/pulse/internal/redact"
 	"github.com/2389-research/pulse/internal/storage"
 )
 
@@ -77,6 +83,36 @@ func callTool(t *testing.T, s *Server, name string, args interface{}) *gomcp.Cal
 			t.Fatalf("handler error: %v", err)
 		}
 		return result
+	case "journal_digest":
+		result, err := s.handleJournalDigest(ctx, req)
+		if err != nil {
+			t.Fatalf("handler error: %v", err)
+		}
+		return result
+	case "update_journal_entry":
+		result, err := s.handleUpdateJournalEntry(ctx, req)
+		if err != nil {
+			t.Fatalf("handler error: %v", err)
+		}
+		return result
+	case "append_to_journal_entry":
+		result, err := s.handleAppendToJournalEntry(ctx, req)
+		if err != nil {
+			t.Fatalf("handler error: %v", err)
+		}
+		return result
+	case "delete_journal_entry":
+		result, err := s.handleDeleteJournalEntry(ctx, req)
+		if err != nil {
+			t.Fatalf("handler error: %v", err)
+		}
+		return result
+	case "restore_journal_entry":
+		result, err := s.handleRestoreJournalEntry(ctx, req)
+		if err != nil {
+			t.Fatalf("handler error: %v", err)
+		}
+		return result
 	case "login":
 		result, err := s.handleLogin(ctx, req)
 		if err != nil {
@@ -357,6 +393,35 @@ func makeJournalServerWithRemote(t *testing.T, handler http.Handler) *Server {
 	return server
 }
 
+func TestProcessThoughtsPartialWriteListsWrittenPaths(t *testing.T) {
+	tmpDir := t.TempDir()
+	journal, _ := storage.NewJournalMDStore(
+		filepath.Join(tmpDir, "project"),
+		filepath.Join(tmpDir, "user"),
+	)
+	social, _ := storage.NewSocialMDStore(filepath.Join(tmpDir, "social"))
+	s, err := NewServer(journal, social, "test")
+	if err != nil {
+		t.Fatalf("NewServer error: %v", err)
+	}
+	// A file where the user root should be makes the second write fail
+	if err := os.WriteFile(filepath.Join(tmpDir, "user"), []byte("not a directory"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result := callTool(t, s, "process_thoughts", map[string]interface{}{
+		"project_notes": "Written before the failure",
+		"feelings":      "Never written",
+	})
+	if !result.IsError {
+		t.Fatalf("expected an error, got: %s", getTextContent(result))
+	}
+	text := getTextContent(result)
+	if !strings.Contains(text, "Already written:") || !strings.Contains(text, filepath.Join(tmpDir, "project")) {
+		t.Errorf("expected the project entry's path in the error, got: %s", text)
+	}
+}
+
 func TestProcessThoughtsRemoteSync(t *testing.T) {
 	var receivedBody []byte
 	var receivedPath string
@@ -386,7 +451,7 @@ func TestProcessThoughtsRemoteSync(t *testing.T) {
 		t.Errorf("expected x-api-key 'test-key', got %q", receivedAuth)
 	}
 
-	// Verify payload contains ALL sections (no project/user split for remote)
+	// Verify payload holds the sections the sync policy allows, unsplit
 	var payload struct {
 		TeamID    string            `json:"team_id"`
 		Timestamp int64             `json:"timestamp"`
@@ -401,8 +466,8 @@ func TestProcessThoughtsRemoteSync(t *testing.T) {
 	if payload.Timestamp <= 0 {
 		t.Errorf("expected positive timestamp, got %d", payload.Timestamp)
 	}
-	if payload.Sections["feelings"] != "Feeling great" {
-		t.Errorf("expected feelings in remote payload, got %q", payload.Sections["feelings"])
+	if _, ok := payload.Sections["feelings"]; ok {
+		t.Errorf("expected opt-in feelings to stay local, got %q", payload.Sections["feelings"])
 	}
 	if payload.Sections["project_notes"] != "Working on pulse" {
 		t.Errorf("expected project_notes in remote payload, got %q", payload.Sections["project_notes"])
@@ -413,6 +478,9 @@ func TestProcessThoughtsRemoteSync(t *testing.T) {
 	if strings.Contains(text, "remote sync failed") {
 		t.Errorf("did not expect remote failure warning, got: %s", text)
 	}
+	if !strings.Contains(text, "Synced to remote: project_notes") || !strings.Contains(text, "Kept local: feelings") {
+		t.Errorf("expected the response to say what was synced, got: %s", text)
+	}
 }
 
 func TestProcessThoughtsRemoteSyncFailure(t *testing.T) {
@@ -421,8 +489,9 @@ func TestProcessThoughtsRemoteSyncFailure(t *testing.T) {
 		_, _ = w.Write([]byte("server error"))
 	}))
 
-	result := callTool(t, s, "process_thoughts", map[string]string{
+	result := callTool(t, s, "process_thoughts", map[string]interface{}{
 		"feelings": "Feeling great despite errors",
+		"sync":     true,
 	})
 
 	// Local write should still succeed
@@ -441,6 +510,47 @@ func TestProcessThoughtsRemoteSyncFailure(t *testing.T) {
 	}
 }
 
+func TestProcessThoughtsSyncPolicy(t *testing.T) {
+	var bodies []string
+	s := makeJournalServerWithRemote(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	// Opt-in sections go out only when the call asks for them
+	callTool(t, s, "process_thoughts", map[string]interface{}{
+		"feelings":     "shared on purpose",
+		"user_context": "prefers small PRs",
+		"sync":         true,
+	})
+	if len(bodies) != 1 || !strings.Contains(bodies[0], "shared on purpose") || !strings.Contains(bodies[0], "prefers small PRs") {
+		t.Fatalf("expected opted-in sections to sync, got %v", bodies)
+	}
+
+	// Nothing allowed by the policy means no request at all
+	callTool(t, s, "process_thoughts", map[string]string{"feelings": "just for me"})
+	if len(bodies) != 1 {
+		t.Errorf("expected no request for local-only sections, got %d requests", len(bodies))
+	}
+
+	// private keeps even always-synced sections local
+	result := callTool(t, s, "process_thoughts", map[string]interface{}{
+		"project_notes": "internal codename rollout",
+		"private":       true,
+	})
+	if len(bodies) != 1 {
+		t.Errorf("expected no request for a private write, got %d requests", len(bodies))
+	}
+	if text := getTextContent(result); !strings.Contains(text, "Kept local: project_notes") {
+		t.Errorf("expected the private write to be reported local, got: %s", text)
+	}
+
+	if result := callTool(t, s, "process_thoughts", map[string]interface{}{"feelings": "x", "private": "yes"}); !result.IsError {
+		t.Error("expected error for non-boolean private")
+	}
+}
+
 func TestProcessThoughtsUnknownSection(t *testing.T) {
 	s := makeJournalServer(t)
 
@@ -493,6 +603,179 @@ func TestListRecentEntriesRejectsInvalidType(t *testing.T) {
 	}
 }
 
+func TestListRecentEntriesDateRange(t *testing.T) {
+	s := makeJournalServer(t)
+	writeTestEntry(t, s, map[string]string{"feelings": "written today"})
+
+	result := callTool(t, s, "list_recent_entries", map[string]any{"since": "today", "order": "oldest"})
+	if result.IsError {
+		t.Fatalf("expected success, got error: %s", getTextContent(result))
+	}
+	if strings.Contains(getTextContent(result), "No recent entries") {
+		t.Errorf("expected today's entry, got: %s", getTextContent(result))
+	}
+
+	result = callTool(t, s, "list_recent_entries", map[string]any{"until": "yesterday"})
+	if result.IsError {
+		t.Fatalf("expected success, got error: %s", getTextContent(result))
+	}
+	if !strings.Contains(getTextContent(result), "No recent entries") {
+		t.Errorf("expected no entries before today, got: %s", getTextContent(result))
+	}
+
+	result = callTool(t, s, "list_recent_entries", map[string]any{"since": "last tuesday"})
+	if !result.IsError || !strings.Contains(getTextContent(result), "invalid since") {
+		t.Errorf("expected invalid date error, got: %s", getTextContent(result))
+	}
+}
+
+func TestListRecentEntriesUntilSkipsDaysDefault(t *testing.T) {
+	s := makeJournalServer(t)
+	old := models.NewJournalEntry(map[string]string{"feelings": "written long ago"}, "user")
+	old.CreatedAt = time.Now().AddDate(0, 0, -90)
+	if err := s.journal.WriteEntry(old); err != nil {
+		t.Fatalf("WriteEntry error: %v", err)
+	}
+
+	// until alone does not fall back to the last 30 days
+	result := callTool(t, s, "list_recent_entries", map[string]any{"until": "today"})
+	if result.IsError {
+		t.Fatalf("expected success, got error: %s", getTextContent(result))
+	}
+	if !strings.Contains(getTextContent(result), old.CreatedAt.Format("2006-01-02")) {
+		t.Errorf("expected the 90-day-old entry, got: %s", getTextContent(result))
+	}
+
+	result = callTool(t, s, "list_recent_entries", map[string]any{"days": 5, "until": "60d"})
+	if !result.IsError || !strings.Contains(getTextContent(result), "days (5) must start before until") {
+		t.Errorf("expected an inverted range error, got: %s", getTextContent(result))
+	}
+
+	// An explicit since is named in the error, not the days it replaces
+	result = callTool(t, s, "list_recent_entries", map[string]any{"since": "7d", "until": "60d"})
+	if !result.IsError || !strings.Contains(getTextContent(result), "since (7d) must be before until (60d)") || strings.Contains(getTextContent(result), "days") {
+		t.Errorf("expected an inverted range error naming since, got: %s", getTextContent(result))
+	}
+}
+
+func TestListRecentEntriesCursor(t *testing.T) {
+	s := makeJournalServer(t)
+	for i := 0; i < 5; i++ {
+		writeTestEntry(t, s, map[string]string{"feelings": fmt.Sprintf("entry %d", i)})
+	}
+
+	nextCursor := regexp.MustCompile(`Next cursor: (\S+)`)
+	seen := make(map[string]bool)
+	args := map[string]any{"limit": 2, "type": "user"}
+	for page := 0; ; page++ {
+		if page > 5 {
+			t.Fatal("cursor never ran out")
+		}
+		result := callTool(t, s, "list_recent_entries", args)
+		if result.IsError {
+			t.Fatalf("expected success, got error: %s", getTextContent(result))
+		}
+		text := getTextContent(result)
+		for _, line := range strings.Split(text, "\n") {
+			if strings.HasPrefix(line, "- ") {
+				if seen[line] {
+					t.Errorf("page %d repeated %q", page, line)
+				}
+				seen[line] = true
+			}
+		}
+		m := nextCursor.FindStringSubmatch(text)
+		if m == nil {
+			break
+		}
+		args["cursor"] = m[1]
+	}
+	if len(seen) != 5 {
+		t.Errorf("expected to page through 5 entries, saw %d", len(seen))
+	}
+
+	// A cursor only continues the listing it came from
+	result := callTool(t, s, "list_recent_entries", map[string]any{"limit": 2, "type": "user"})
+	m := nextCursor.FindStringSubmatch(getTextContent(result))
+	if m == nil {
+		t.Fatalf("expected a next cursor, got: %s", getTextContent(result))
+	}
+	result = callTool(t, s, "list_recent_entries", map[string]any{"limit": 2, "type": "project", "cursor": m[1]})
+	if !result.IsError || !strings.Contains(getTextContent(result), "different arguments") {
+		t.Errorf("expected mismatched cursor error, got: %s", getTextContent(result))
+	}
+	result = callTool(t, s, "list_recent_entries", map[string]any{"cursor": "garbage"})
+	if !result.IsError || !strings.Contains(getTextContent(result), "invalid cursor") {
+		t.Errorf("expected invalid cursor error, got: %s", getTextContent(result))
+	}
+}
+
+func TestSearchJournalCursor(t *testing.T) {
+	s := makeJournalServer(t)
+	for i := 0; i < 3; i++ {
+		writeTestEntry(t, s, map[string]string{"feelings": fmt.Sprintf("deploy went fine %d", i)})
+	}
+
+	result := callTool(t, s, "search_journal", map[string]any{"query": "deploy", "limit": 2})
+	text := getTextContent(result)
+	if strings.Count(text, "Entry: ") != 2 {
+		t.Fatalf("expected 2 results on the first page, got: %s", text)
+	}
+	m := regexp.MustCompile(`Next cursor: (\S+)`).FindStringSubmatch(text)
+	if m == nil {
+		t.Fatalf("expected a next cursor, got: %s", text)
+	}
+
+	result = callTool(t, s, "search_journal", map[string]any{"query": "deploy", "limit": 2, "cursor": m[1]})
+	text = getTextContent(result)
+	if strings.Count(text, "Entry: ") != 1 || strings.Contains(text, "Next cursor") {
+		t.Errorf("expected the last result and no cursor, got: %s", text)
+	}
+
+	result = callTool(t, s, "search_journal", map[string]any{"query": "fine", "limit": 2, "cursor": m[1]})
+	if !result.IsError {
+		t.Errorf("expected a cursor from another query to be rejected, got: %s", getTextContent(result))
+	}
+}
+
+func TestJournalDigest(t *testing.T) {
+	s := makeJournalServer(t)
+	writeTestEntry(t, s, map[string]string{"feelings": "anxious about the release"})
+	writeTestEntry(t, s, map[string]string{"feelings": "still anxious, but calmer"})
+	writeTestEntry(t, s, map[string]string{"technical_insights": "retries need jitter"})
+
+	result := callTool(t, s, "journal_digest", map[string]any{"since": "today"})
+	if result.IsError {
+		t.Fatalf("expected success, got error: %s", getTextContent(result))
+	}
+	text := getTextContent(result)
+	for _, want := range []string{"3 entries", "- Feelings: 2", "- Technical Insights: 1", "retries need jitter", "anxious, in 2 entries", "## Sources"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected digest to contain %q, got:\n%s", want, text)
+		}
+	}
+
+	result = callTool(t, s, "journal_digest", map[string]any{"format": "json"})
+	var d struct {
+		Entries   int `json:"entries"`
+		BySection []struct {
+			Name  string `json:"name"`
+			Count int    `json:"count"`
+		} `json:"by_section"`
+	}
+	if err := json.Unmarshal([]byte(getTextContent(result)), &d); err != nil {
+		t.Fatalf("expected JSON digest, got %v: %s", err, getTextContent(result))
+	}
+	if d.Entries != 3 || len(d.BySection) != 2 {
+		t.Errorf("unexpected JSON digest: %+v", d)
+	}
+
+	result = callTool(t, s, "journal_digest", map[string]any{"format": "pdf"})
+	if !result.IsError {
+		t.Error("expected error for unknown format")
+	}
+}
+
 func TestListRecentEntriesEmpty(t *testing.T) {
 	s := makeJournalServer(t)
 
@@ -507,3 +790,383 @@ func TestListRecentEntriesEmpty(t *testing.T) {
 		t.Errorf("expected 'No recent entries', got: %s", text)
 	}
 }
+
+func TestSearchJournalReturnsScoreAndSnippet(t *testing.T) {
+	s := makeJournalServer(t)
+
+	callTool(t, s, "process_thoughts", map[string]string{
+		"technical_insights": "The retry loop hid a deadlock in the connection pool",
+		"feelings":           "Relieved to have found it",
+	})
+
+	result := callTool(t, s, "search_journal", map[string]interface{}{
+		"query": "deadlock",
+	})
+	if result.IsError {
+		t.Fatalf("expected success, got error: %s", getTextContent(result))
+	}
+
+	text := getTextContent(result)
+	if !strings.Contains(text, "Score: ") {
+		t.Errorf("expected a score line, got: %s", text)
+	}
+	if !strings.Contains(text, "Matched section: technical_insights") {
+		t.Errorf("expected the matched section to be reported, got: %s", text)
+	}
+	if !strings.Contains(text, "Snippet: Technical Insights: The retry loop hid a deadlock") {
+		t.Errorf("expected a snippet of the matching section, got: %s", text)
+	}
+	if strings.Contains(text, "Relieved") {
+		t.Errorf("expected non-matching sections to be left out, got: %s", text)
+	}
+}
+
+// writeTestEntry writes a user entry through process_thoughts and returns its path.
+func writeTestEntry(t *testing.T, s *Server, sections map[string]string) string {
+	t.Helper()
+	text := getTextContent(callTool(t, s, "process_thoughts", sections))
+	pathIdx := strings.Index(text, "Path: ")
+	if pathIdx < 0 {
+		t.Fatalf("couldn't find path in response: %s", text)
+	}
+	return strings.TrimSpace(text[pathIdx+6:])
+}
+
+func TestUpdateJournalEntry(t *testing.T) {
+	s := makeJournalServer(t)
+	path := writeTestEntry(t, s, map[string]string{
+		"feelings":     "Anxious about the release",
+		"user_context": "Prefers tabs",
+	})
+
+	result := callTool(t, s, "update_journal_entry", map[string]string{
+		"path":         path,
+		"user_context": "Prefers spaces, actually",
+	})
+	if result.IsError {
+		t.Fatalf("expected success, got error: %s", getTextContent(result))
+	}
+
+	readText := getTextContent(callTool(t, s, "read_journal_entry", map[string]string{"path": path}))
+	if !strings.Contains(readText, "Prefers spaces, actually") || strings.Contains(readText, "Prefers tabs") {
+		t.Errorf("expected replaced user context, got: %s", readText)
+	}
+	if !strings.Contains(readText, "Anxious about the release") {
+		t.Errorf("expected untouched feelings, got: %s", readText)
+	}
+	if !strings.Contains(readText, "Updated: ") {
+		t.Errorf("expected updated timestamp, got: %s", readText)
+	}
+}
+
+func TestUpdateJournalEntryRejectsUnknownSection(t *testing.T) {
+	s := makeJournalServer(t)
+	path := writeTestEntry(t, s, map[string]string{"feelings": "fine"})
+
+	result := callTool(t, s, "update_journal_entry", map[string]string{
+		"path":  path,
+		"bogus": "nope",
+	})
+	if !result.IsError {
+		t.Error("expected error for unknown section")
+	}
+}
+
+func TestAppendToJournalEntry(t *testing.T) {
+	s := makeJournalServer(t)
+	path := writeTestEntry(t, s, map[string]string{"feelings": "Started the day tired"})
+
+	result := callTool(t, s, "append_to_journal_entry", map[string]string{
+		"path":    path,
+		"section": "feelings",
+		"content": "Feeling better after lunch",
+	})
+	if result.IsError {
+		t.Fatalf("expected success, got error: %s", getTextContent(result))
+	}
+
+	readText := getTextContent(callTool(t, s, "read_journal_entry", map[string]string{"path": path}))
+	if !strings.Contains(readText, "Started the day tired\n\nFeeling better after lunch") {
+		t.Errorf("expected appended text, got: %s", readText)
+	}
+}
+
+func TestDeleteJournalEntry(t *testing.T) {
+	s := makeJournalServer(t)
+	path := writeTestEntry(t, s, map[string]string{"feelings": "Retract this"})
+
+	result := callTool(t, s, "delete_journal_entry", map[string]string{"path": path})
+	if result.IsError {
+		t.Fatalf("expected success, got error: %s", getTextContent(result))
+	}
+
+	if read := callTool(t, s, "read_journal_entry", map[string]string{"path": path}); !read.IsError {
+		t.Error("expected reading a deleted entry to fail")
+	}
+	if !strings.Contains(getTextContent(result), ".trash") {
+		t.Errorf("expected trash path in response, got: %s", getTextContent(result))
+	}
+}
+
+func TestRestoreJournalEntry(t *testing.T) {
+	s := makeJournalServer(t)
+	path := writeTestEntry(t, s, map[string]string{"feelings": "Deleted by mistake"})
+
+	result := callTool(t, s, "delete_journal_entry", map[string]string{"path": path, "reason": "oops"})
+	if result.IsError {
+		t.Fatalf("expected success, got error: %s", getTextContent(result))
+	}
+
+	listText := getTextContent(callTool(t, s, "list_recent_entries", map[string]interface{}{}))
+	if strings.Contains(listText, path) {
+		t.Errorf("expected trashed entry to be hidden, got: %s", listText)
+	}
+
+	// Restoring by the original path works even though the file moved
+	result = callTool(t, s, "restore_journal_entry", map[string]string{"path": path})
+	if result.IsError {
+		t.Fatalf("expected success, got error: %s", getTextContent(result))
+	}
+
+	readText := getTextContent(callTool(t, s, "read_journal_entry", map[string]string{"path": path}))
+	if !strings.Contains(readText, "Deleted by mistake") {
+		t.Errorf("expected restored content, got: %s", readText)
+	}
+}
+
+func TestDeleteJournalEntryOutsideRoots(t *testing.T) {
+	s := makeJournalServer(t)
+	outside := filepath.Join(t.TempDir(), "outside.md")
+	if err := os.WriteFile(outside, []byte("keep me"), 0o644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	result := callTool(t, s, "delete_journal_entry", map[string]string{"path": outside})
+	if !result.IsError {
+		t.Error("expected error for path outside journal roots")
+	}
+	if _, err := os.Stat(outside); err != nil {
+		t.Errorf("file outside roots should not be touched: %v", err)
+	}
+}
+
+func TestProcessThoughtsCustomSections(t *testing.T) {
+	defaults := models.DefaultSections()
+	t.Cleanup(func() { _ = models.SetSections(defaults) })
+	custom := append(models.DefaultSections(),
+		models.SectionDef{Name: "decisions", Description: "Decisions made and why", Route: "project", Required: true},
+		models.SectionDef{Name: "blockers", Route: "user"},
+	)
+	if err := models.SetSections(custom); err != nil {
+		t.Fatalf("SetSections error: %v", err)
+	}
+
+	schema := string(processThoughtsSchema())
+	if !strings.Contains(schema, `"decisions"`) || !strings.Contains(schema, "Decisions made and why") {
+		t.Errorf("expected custom section in schema, got %s", schema)
+	}
+	if !strings.Contains(schema, `"required":["decisions"]`) {
+		t.Errorf("expected required list in schema, got %s", schema)
+	}
+
+	s := makeJournalServer(t)
+
+	result := callTool(t, s, "process_thoughts", map[string]string{"blockers": "CI is down"})
+	if !result.IsError || !strings.Contains(getTextContent(result), "decisions") {
+		t.Errorf("expected missing required section error, got: %s", getTextContent(result))
+	}
+
+	result = callTool(t, s, "process_thoughts", map[string]string{
+		"decisions": "Ship behind a flag",
+		"blockers":  "CI is down",
+	})
+	if result.IsError {
+		t.Fatalf("expected success, got error: %s", getTextContent(result))
+	}
+	text := getTextContent(result)
+	if !strings.Contains(text, "[project] decisions") || !strings.Contains(text, "[user] blockers") {
+		t.Errorf("expected routing by configured route, got: %s", text)
+	}
+}
+
+func TestProcessThoughtsLinked(t *testing.T) {
+	s := makeJournalServer(t)
+
+	result := callTool(t, s, "process_thoughts", map[string]interface{}{
+		"feelings":      "Proud of this one",
+		"project_notes": "Finished the migration",
+		"linked":        true,
+	})
+	if result.IsError {
+		t.Fatalf("expected success, got error: %s", getTextContent(result))
+	}
+	text := getTextContent(result)
+	if !strings.Contains(text, "linked") {
+		t.Errorf("expected linked note in response, got: %s", text)
+	}
+
+	var paths []string
+	for _, line := range strings.Split(text, "\n") {
+		if strings.HasPrefix(line, "Path: ") {
+			paths = append(paths, strings.TrimPrefix(line, "Path: "))
+		}
+	}
+	if len(paths) != 2 {
+		t.Fatalf("expected two paths, got: %s", text)
+	}
+	readText := getTextContent(callTool(t, s, "read_journal_entry", map[string]string{"path": paths[0]}))
+	if !strings.Contains(readText, "Linked: ") {
+		t.Errorf("expected linked ID in read output, got: %s", readText)
+	}
+
+	result = callTool(t, s, "process_thoughts", map[string]interface{}{"feelings": "x", "linked": "yes"})
+	if !result.IsError {
+		t.Error("expected error for non-boolean linked")
+	}
+}
+
+func TestThoughtSiblingsGrouped(t *testing.T) {
+	s := makeJournalServer(t)
+
+	result := callTool(t, s, "process_thoughts", map[string]interface{}{
+		"feelings":      "Uneasy about the cutover",
+		"project_notes": "Cutover scheduled for Friday",
+	})
+	if result.IsError {
+		t.Fatalf("expected success, got error: %s", getTextContent(result))
+	}
+	var paths []string
+	for _, line := range strings.Split(getTextContent(result), "\n") {
+		if strings.HasPrefix(line, "Path: ") {
+			paths = append(paths, strings.TrimPrefix(line, "Path: "))
+		}
+	}
+	if len(paths) != 2 {
+		t.Fatalf("expected two paths, got: %s", getTextContent(result))
+	}
+
+	readText := getTextContent(callTool(t, s, "read_journal_entry", map[string]interface{}{
+		"path":    paths[1],
+		"thought": true,
+	}))
+	for _, p := range paths {
+		if !strings.Contains(readText, "Entry: "+p) {
+			t.Errorf("expected %s in thought output, got: %s", p, readText)
+		}
+	}
+	if !strings.Contains(readText, "Cutover scheduled") || !strings.Contains(readText, "Uneasy") {
+		t.Errorf("expected both halves of the thought, got: %s", readText)
+	}
+
+	listText := getTextContent(callTool(t, s, "list_recent_entries", map[string]interface{}{}))
+	if !strings.Contains(listText, "  + [") {
+		t.Errorf("expected one half listed under its sibling, got: %s", listText)
+	}
+
+	searchText := getTextContent(callTool(t, s, "search_journal", map[string]interface{}{"query": "cutover"}))
+	if strings.Count(searchText, "Entry: ") != 1 || !strings.Contains(searchText, "Also: ") {
+		t.Errorf("expected siblings grouped into one result, got: %s", searchText)
+	}
+}
+
+func TestProcessThoughtsMetadataAndFilters(t *testing.T) {
+	s := makeJournalServer(t)
+	if err := s.social.SetIdentity("night-owl"); err != nil {
+		t.Fatalf("SetIdentity error: %v", err)
+	}
+
+	result := callTool(t, s, "process_thoughts", map[string]interface{}{
+		"feelings":   "Paged at 3am",
+		"tags":       []string{"incident", "oncall"},
+		"mood":       "groggy",
+		"valence":    -0.6,
+		"branch":     "main",
+		"attributes": map[string]string{"ticket": "OPS-7"},
+	})
+	if result.IsError {
+		t.Fatalf("expected success, got error: %s", getTextContent(result))
+	}
+	if !strings.Contains(getTextContent(result), "Tags: incident, oncall") {
+		t.Errorf("expected tags in response, got: %s", getTextContent(result))
+	}
+	callTool(t, s, "process_thoughts", map[string]interface{}{"feelings": "Calm day", "branch": "dev"})
+
+	listText := getTextContent(callTool(t, s, "list_recent_entries", map[string]interface{}{
+		"tags":   []string{"incident"},
+		"branch": "main",
+	}))
+	if strings.Count(listText, "\n- ") != 0 || !strings.Contains(listText, "#incident #oncall") {
+		t.Errorf("expected exactly the tagged entry, got: %s", listText)
+	}
+	path := listText[strings.Index(listText, "/"):strings.Index(listText, " #")]
+
+	readText := getTextContent(callTool(t, s, "read_journal_entry", map[string]string{"path": path}))
+	for _, want := range []string{"Agent: night-owl", "Mood: groggy", "Valence: -0.6", "Branch: main", "ticket: OPS-7"} {
+		if !strings.Contains(readText, want) {
+			t.Errorf("expected %q in read output, got: %s", want, readText)
+		}
+	}
+
+	searchText := getTextContent(callTool(t, s, "search_journal", map[string]interface{}{
+		"query":       "day",
+		"min_valence": 0,
+	}))
+	if !strings.Contains(searchText, "No matching entries") {
+		t.Errorf("expected valence filter to exclude entries without valence, got: %s", searchText)
+	}
+
+	result = callTool(t, s, "process_thoughts", map[string]interface{}{"feelings": "x", "valence": 3})
+	if !result.IsError {
+		t.Error("expected error for out-of-range valence")
+	}
+	result = callTool(t, s, "process_thoughts", map[string]interface{}{"feelings": "x", "tags": "incident"})
+	if !result.IsError {
+		t.Error("expected error for non-array tags")
+	}
+}
+
+func TestSearchJournalQuerySyntax(t *testing.T) {
+	s := makeJournalServer(t)
+	callTool(t, s, "process_thoughts", map[string]interface{}{"feelings": "Nervous about the release", "tags": []string{"release"}})
+	callTool(t, s, "process_thoughts", map[string]interface{}{"feelings": "Release went fine"})
+
+	text := getTextContent(callTool(t, s, "search_journal", map[string]interface{}{"query": "release AND tag:release"}))
+	if strings.Count(text, "Entry: ") != 1 || !strings.Contains(text, "Nervous") {
+		t.Errorf("expected only the tagged entry, got: %s", text)
+	}
+
+	result := callTool(t, s, "search_journal", map[string]interface{}{"query": `release AND "nervous`})
+	if !result.IsError {
+		t.Fatal("expected syntax error")
+	}
+	if !strings.Contains(getTextContent(result), "column 13") {
+		t.Errorf("expected error to point at column 13, got: %s", getTextContent(result))
+	}
+}
+
+func TestProcessThoughtsReportsRedactions(t *testing.T) {
+	tmpDir := t.TempDir()
+	redactor, _ := redact.New(redact.ModeMask, nil)
+	journal, _ := storage.NewJournalMDStore(filepath.Join(tmpDir, "project"), filepath.Join(tmpDir, "user"),
+		storage.WithRedactor(redactor), storage.WithGitContext(false))
+	social, _ := storage.NewSocialMDStore(filepath.Join(tmpDir, "social"), storage.WithPostRedactor(redactor))
+	s, err := NewServer(journal, social, "test")
+	if err != nil {
+		t.Fatalf("NewServer error: %v", err)
+	}
+
+	result := callTool(t, s, "process_thoughts", map[string]string{"feelings": "dave@example.com was kind"})
+	text := getTextContent(result)
+	if result.IsError || !strings.Contains(text, "Redacted: 1 email in feelings") {
+		t.Fatalf("expected the redaction to be reported, got: %s", text)
+	}
+	if strings.Contains(text, "dave@") {
+		t.Errorf("expected the report not to repeat the secret, got: %s", text)
+	}
+
+	callTool(t, s, "login", map[string]string{"agent_name": "turbo_gecko"})
+	text = getTextContent(callTool(t, s, "create_post", map[string]string{"content": "ping dave@example.com"}))
+	if !strings.Contains(text, "Redacted: 1 email in content") {
+		t.Errorf("expected create_post to report the redaction, got: %s", text)
+	}
+}
@@ -1,5 +1,5 @@
 // ABOUTME: MCP server initialization and configuration for pulse.
-// ABOUTME: Sets up server with journal and social tools for AI agent access.
+// ABOUTME: Sets up server with journal and social tools and journal template prompts for AI agent access.
 package mcp
 
 import (
@@ -9,14 +9,16 @@ import (
 	gomcp "github.com/modelcontextprotocol/go-sdk/mcp"
 
 	"github.com/2389-research/pulse/internal/storage"
+	"github.com/2389-research/pulse/internal/templates"
 )
 
 // Server wraps the MCP server with journal and social storage.
 type Server struct {
-	mcp     *gomcp.Server
-	journal storage.JournalStore
-	social  storage.SocialStore
-	remote  *storage.RemoteClient
+	mcp       *gomcp.Server
+	journal   storage.JournalStore
+	social    storage.SocialStore
+	remote    *storage.RemoteClient
+	templates []templates.Template
 }
 
 // ServerOption configures optional Server dependencies.
@@ -29,6 +31,13 @@ func WithRemoteClient(rc *storage.RemoteClient) ServerOption {
 	}
 }
 
+// WithTemplates serves each journal template as a prompt.
+func WithTemplates(t []templates.Template) ServerOption {
+	return func(s *Server) {
+		s.templates = t
+	}
+}
+
 // NewServer creates an MCP server with journal and social capabilities.
 func NewServer(journal storage.JournalStore, social storage.SocialStore, version string, opts ...ServerOption) (*Server, error) {
 	if journal == nil {
@@ -62,6 +71,7 @@ func NewServer(journal storage.JournalStore, social storage.SocialStore, version
 
 	s.registerJournalTools()
 	s.registerSocialTools()
+	s.registerJournalPrompts()
 
 	return s, nil
 }
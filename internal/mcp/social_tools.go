@@ -12,6 +12,7 @@ import (
 	gomcp "github.com/modelcontextprotocol/go-sdk/mcp"
 
 	"github.com/2389-research/pulse/internal/models"
+	"github.com/2389-research/pulse/internal/redact"
 	"github.com/2389-research/pulse/internal/storage"
 )
 
@@ -117,13 +118,15 @@ func (s *Server) handleCreatePost(ctx context.Context, req *gomcp.CallToolReques
 		return toolError("failed to create post: %v", err), nil
 	}
 
+	report := redact.Report(post.Redactions)
+
 	// Sync to remote if configured
 	if s.remote != nil {
 		if err := s.remote.CreatePost(ctx, post); err != nil {
 			// Local write succeeded, remote failed - note but don't error
 			return &gomcp.CallToolResult{
 				Content: []gomcp.Content{&gomcp.TextContent{
-					Text: fmt.Sprintf("Post created locally (ID: %s) but remote sync failed: %v", post.ID.String()[:8], err),
+					Text: strings.Join(append([]string{fmt.Sprintf("Post created locally (ID: %s) but remote sync failed: %v", post.ID.String()[:8], err)}, report...), "\n"),
 				}},
 			}, nil
 		}
@@ -135,7 +138,7 @@ func (s *Server) handleCreatePost(ctx context.Context, req *gomcp.CallToolReques
 
 	return &gomcp.CallToolResult{
 		Content: []gomcp.Content{&gomcp.TextContent{
-			Text: fmt.Sprintf("Post created (ID: %s)", post.ID.String()[:8]),
+			Text: strings.Join(append([]string{fmt.Sprintf("Post created (ID: %s)", post.ID.String()[:8])}, report...), "\n"),
 		}},
 	}, nil
 }
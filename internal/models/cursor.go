@@ -0,0 +1,69 @@
+// ABOUTME: Opaque pagination cursors for journal listings and search rankings.
+// ABOUTME: A cursor records the last entry returned so the next page resumes strictly after it.
+package models
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Cursor marks a position in a stable ordering of journal entries. Listings
+// order entries by (CreatedAt, ID); search rankings order by score first and
+// break ties the same way. Scope fingerprints the request the cursor came
+// from, so a cursor cannot silently continue a different listing or query.
+type Cursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        uuid.UUID `json:"id"`
+	Score     float64   `json:"s,omitempty"` // set for search rankings
+	Scope     string    `json:"q,omitempty"`
+}
+
+// CursorAt returns the cursor positioned at entry.
+func CursorAt(entry *JournalEntry) Cursor {
+	return Cursor{CreatedAt: entry.CreatedAt, ID: entry.ID}
+}
+
+// IsZero reports whether the cursor is unset, meaning "start from the top".
+func (c Cursor) IsZero() bool {
+	return c.CreatedAt.IsZero() && c.ID == uuid.Nil
+}
+
+// Compare orders the position (createdAt, id) against the cursor's position:
+// -1 if it sorts before the cursor in ascending order, 0 if equal, 1 if after.
+func (c Cursor) Compare(createdAt time.Time, id uuid.UUID) int {
+	switch {
+	case createdAt.Before(c.CreatedAt):
+		return -1
+	case createdAt.After(c.CreatedAt):
+		return 1
+	}
+	return bytes.Compare(id[:], c.ID[:])
+}
+
+// Encode renders the cursor as an opaque URL-safe token.
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// ParseCursor decodes a token produced by Encode. An empty token yields the
+// zero cursor.
+func ParseCursor(token string) (Cursor, error) {
+	var c Cursor
+	if token == "" {
+		return c, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return c, fmt.Errorf("invalid cursor")
+	}
+	if err := json.Unmarshal(data, &c); err != nil || c.IsZero() {
+		return Cursor{}, fmt.Errorf("invalid cursor")
+	}
+	return c, nil
+}
@@ -0,0 +1,54 @@
+// ABOUTME: Tests for pagination cursors.
+// ABOUTME: Covers encoding roundtrips, rejection of malformed tokens, and position ordering.
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestCursorRoundtrip(t *testing.T) {
+	entry := NewJournalEntry(map[string]string{"feelings": "x"}, "user")
+	cursor := CursorAt(entry)
+	cursor.Score = 0.1 + 0.2
+	cursor.Scope = "abc"
+
+	parsed, err := ParseCursor(cursor.Encode())
+	if err != nil {
+		t.Fatalf("ParseCursor error: %v", err)
+	}
+	if !parsed.CreatedAt.Equal(entry.CreatedAt) || parsed.ID != entry.ID {
+		t.Errorf("position changed in roundtrip: %+v", parsed)
+	}
+	if parsed.Score != cursor.Score || parsed.Scope != "abc" {
+		t.Errorf("score or scope changed in roundtrip: %+v", parsed)
+	}
+
+	if zero, err := ParseCursor(""); err != nil || !zero.IsZero() {
+		t.Errorf("empty token should parse to the zero cursor, got %+v, %v", zero, err)
+	}
+	for _, bad := range []string{"not base64!", "bm90IGpzb24", "e30"} {
+		if _, err := ParseCursor(bad); err == nil {
+			t.Errorf("ParseCursor(%q) expected error", bad)
+		}
+	}
+}
+
+func TestCursorCompare(t *testing.T) {
+	now := time.Now()
+	low := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	high := uuid.MustParse("ffffffff-0000-0000-0000-000000000000")
+	cursor := Cursor{CreatedAt: now, ID: low}
+
+	if got := cursor.Compare(now.Add(-time.Second), high); got != -1 {
+		t.Errorf("earlier time should sort before, got %d", got)
+	}
+	if got := cursor.Compare(now, high); got != 1 {
+		t.Errorf("same time and higher ID should sort after, got %d", got)
+	}
+	if got := cursor.Compare(now, low); got != 0 {
+		t.Errorf("same position should compare equal, got %d", got)
+	}
+}
@@ -0,0 +1,84 @@
+// ABOUTME: Journal names: the built-in project and user journals plus rules for naming journals declared in config.
+// ABOUTME: Resolves type selectors such as "work", "work,oncall", or "all" against a store's journals.
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// builtinJournals always exist: the project-local and user-global journals.
+var builtinJournals = []string{"project", "user"}
+
+// reservedJournalNames select journals rather than naming one. "both" is
+// what "all" was called when there were only two journals.
+var reservedJournalNames = map[string]bool{
+	"all":  true,
+	"both": true,
+}
+
+var journalNamePattern = regexp.MustCompile(`^[a-z][a-z0-9]*([_-][a-z0-9]+)*$`)
+
+// CheckJournalName reports whether name can name a journal declared in
+// config: lowercase letters and digits, optionally joined by - or _, and not
+// a built-in or reserved name.
+func CheckJournalName(name string) error {
+	if !journalNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid journal name %q: use lowercase letters, digits, - and _", name)
+	}
+	if reservedJournalNames[name] {
+		return fmt.Errorf("journal name %q is reserved", name)
+	}
+	if IsBuiltinJournal(name) {
+		return fmt.Errorf("journal %q is built in", name)
+	}
+	return nil
+}
+
+// IsBuiltinJournal reports whether name is the project or user journal.
+func IsBuiltinJournal(name string) bool {
+	for _, builtin := range builtinJournals {
+		if name == builtin {
+			return true
+		}
+	}
+	return false
+}
+
+// BuiltinJournals returns the journals every store has: project, then user.
+func BuiltinJournals() []string {
+	return append([]string{}, builtinJournals...)
+}
+
+// SelectJournals resolves a type selector to journal names from known: one
+// name, a comma-separated list of names, or "all" (also "both", or empty) for
+// every journal. Names are returned in known's order without duplicates.
+func SelectJournals(selector string, known []string) ([]string, error) {
+	selector = strings.TrimSpace(selector)
+	if selector == "" || reservedJournalNames[selector] {
+		return known, nil
+	}
+	wanted := make(map[string]bool)
+	for _, name := range strings.Split(selector, ",") {
+		name = strings.TrimSpace(name)
+		found := false
+		for _, journal := range known {
+			if journal == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown journal %q: must be one of: %s, or all", name, strings.Join(known, ", "))
+		}
+		wanted[name] = true
+	}
+	var out []string
+	for _, journal := range known {
+		if wanted[journal] {
+			out = append(out, journal)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,61 @@
+// ABOUTME: Tests for journal names and type selectors.
+// ABOUTME: Covers checking journal names, routing sections to named journals, and resolving "all" and name lists.
+package models
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCheckJournalName(t *testing.T) {
+	for _, name := range []string{"work", "on-call", "team_2"} {
+		if err := CheckJournalName(name); err != nil {
+			t.Errorf("CheckJournalName(%q) error: %v", name, err)
+		}
+	}
+	for _, name := range []string{"Work", "all", "both", "user", "project", "-x", ""} {
+		if err := CheckJournalName(name); err == nil {
+			t.Errorf("expected %q to be rejected", name)
+		}
+	}
+}
+
+func TestSelectJournals(t *testing.T) {
+	known := []string{"project", "user", "oncall", "work"}
+	tests := []struct {
+		selector string
+		want     []string
+	}{
+		{"", known},
+		{"all", known},
+		{"both", known},
+		{"work", []string{"work"}},
+		{"work, project,work", []string{"project", "work"}},
+	}
+	for _, tt := range tests {
+		got, err := SelectJournals(tt.selector, known)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SelectJournals(%q) = %v, %v; want %v", tt.selector, got, err, tt.want)
+		}
+	}
+
+	if _, err := SelectJournals("work,team", known); err == nil || !strings.Contains(err.Error(), `unknown journal "team"`) {
+		t.Errorf("expected an unknown journal error, got %v", err)
+	}
+	if _, err := SelectJournals("all,work", known); err == nil {
+		t.Error("expected all to be rejected inside a list")
+	}
+}
+
+func TestSetSectionsRoutesToNamedJournal(t *testing.T) {
+	t.Cleanup(func() { _ = SetSections(DefaultSections()) })
+
+	// Whether the journal exists is up to the store that writes the section
+	if err := SetSections(append(DefaultSections(), SectionDef{Name: "pages", Route: "oncall"})); err != nil {
+		t.Errorf("SetSections error: %v", err)
+	}
+	if err := SetSections(append(DefaultSections(), SectionDef{Name: "pages", Route: "both"})); err == nil {
+		t.Error("expected a route to a selector to be rejected")
+	}
+}
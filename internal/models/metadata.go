@@ -0,0 +1,147 @@
+// ABOUTME: Optional structured metadata carried in journal entry frontmatter.
+// ABOUTME: Covers tags, mood and valence, agent identity, git context, free-form attributes, and filtering by them.
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// EntryMeta is optional context recorded alongside a journal entry's sections.
+type EntryMeta struct {
+	Tags       []string          `json:"tags,omitempty"`
+	Mood       string            `json:"mood,omitempty"`    // free-form mood word, e.g. "anxious"
+	Valence    *float64          `json:"valence,omitempty"` // -1 (negative) to 1 (positive); nil if unset
+	Agent      string            `json:"agent,omitempty"`   // identity of the agent that wrote the entry
+	Repo       string            `json:"repo,omitempty"`
+	Branch     string            `json:"branch,omitempty"`
+	Commit     string            `json:"commit,omitempty"`
+	Dirty      bool              `json:"dirty,omitempty"`      // the work tree had uncommitted changes at Commit
+	Attributes map[string]string `json:"attributes,omitempty"` // free-form key/value pairs
+}
+
+// Validate checks that valence is in range and attribute keys are non-empty.
+func (m EntryMeta) Validate() error {
+	if m.Valence != nil && (*m.Valence < -1 || *m.Valence > 1) {
+		return fmt.Errorf("valence must be between -1 and 1, got %g", *m.Valence)
+	}
+	for key := range m.Attributes {
+		if strings.TrimSpace(key) == "" {
+			return fmt.Errorf("attribute keys must not be empty")
+		}
+	}
+	return nil
+}
+
+// Fields returns the set metadata as label/value pairs in display order.
+func (m EntryMeta) Fields() [][2]string {
+	var fields [][2]string
+	add := func(label, value string) {
+		if value != "" {
+			fields = append(fields, [2]string{label, value})
+		}
+	}
+	add("Tags", strings.Join(m.Tags, ", "))
+	add("Mood", m.Mood)
+	if m.Valence != nil {
+		add("Valence", strconv.FormatFloat(*m.Valence, 'g', -1, 64))
+	}
+	add("Agent", m.Agent)
+	add("Repo", m.Repo)
+	add("Branch", m.Branch)
+	add("Commit", m.Commit)
+	if m.Dirty {
+		add("Dirty", "yes")
+	}
+	keys := make([]string, 0, len(m.Attributes))
+	for key := range m.Attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		add(key, m.Attributes[key])
+	}
+	return fields
+}
+
+// NormalizeTags lowercases and trims tags, dropping empty and duplicate ones
+// while keeping their first-seen order.
+func NormalizeTags(tags []string) []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(tag), "#")))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		out = append(out, tag)
+	}
+	return out
+}
+
+// MetaFilter selects entries by their metadata. Zero-valued fields match
+// everything; set fields must all match.
+type MetaFilter struct {
+	Tags       []string // entry must carry every tag
+	Mood       string
+	MinValence *float64
+	MaxValence *float64
+	Agent      string
+	Repo       string
+	Branch     string
+	Commit     string            // matches full hashes by prefix
+	Attributes map[string]string // entry must carry every key with the same value
+}
+
+// IsZero reports whether the filter matches every entry.
+func (f MetaFilter) IsZero() bool {
+	return len(f.Tags) == 0 && f.Mood == "" && f.MinValence == nil && f.MaxValence == nil &&
+		f.Agent == "" && f.Repo == "" && f.Branch == "" && f.Commit == "" && len(f.Attributes) == 0
+}
+
+// Matches reports whether m satisfies every set field of the filter. Tags,
+// mood, and agent compare case-insensitively; git fields compare exactly.
+func (f MetaFilter) Matches(m EntryMeta) bool {
+	for _, want := range NormalizeTags(f.Tags) {
+		found := false
+		for _, tag := range m.Tags {
+			if strings.EqualFold(tag, want) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if f.Mood != "" && !strings.EqualFold(f.Mood, m.Mood) {
+		return false
+	}
+	if f.MinValence != nil && (m.Valence == nil || *m.Valence < *f.MinValence) {
+		return false
+	}
+	if f.MaxValence != nil && (m.Valence == nil || *m.Valence > *f.MaxValence) {
+		return false
+	}
+	if f.Agent != "" && !strings.EqualFold(f.Agent, m.Agent) {
+		return false
+	}
+	if f.Repo != "" && f.Repo != m.Repo {
+		return false
+	}
+	if f.Branch != "" && f.Branch != m.Branch {
+		return false
+	}
+	if f.Commit != "" && (m.Commit == "" || !strings.HasPrefix(m.Commit, f.Commit)) {
+		return false
+	}
+	for key, want := range f.Attributes {
+		if got, ok := m.Attributes[key]; !ok || got != want {
+			return false
+		}
+	}
+	return true
+}
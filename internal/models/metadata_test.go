@@ -0,0 +1,85 @@
+// ABOUTME: Tests for journal entry metadata.
+// ABOUTME: Covers validation, tag normalization, display fields, and filter matching.
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEntryMetaValidate(t *testing.T) {
+	ok, low := 0.5, -1.5
+	if err := (EntryMeta{Valence: &ok}).Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := (EntryMeta{Valence: &low}).Validate(); err == nil {
+		t.Error("expected error for valence below -1")
+	}
+	if err := (EntryMeta{Attributes: map[string]string{" ": "x"}}).Validate(); err == nil {
+		t.Error("expected error for empty attribute key")
+	}
+}
+
+func TestNormalizeTags(t *testing.T) {
+	got := NormalizeTags([]string{" Incident", "#db", "incident", "", "DB"})
+	want := []string{"incident", "db"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NormalizeTags = %v, want %v", got, want)
+	}
+}
+
+func TestEntryMetaFields(t *testing.T) {
+	v := -0.25
+	meta := EntryMeta{
+		Tags:       []string{"incident"},
+		Valence:    &v,
+		Branch:     "main",
+		Attributes: map[string]string{"ticket": "OPS-1"},
+	}
+	want := [][2]string{{"Tags", "incident"}, {"Valence", "-0.25"}, {"Branch", "main"}, {"ticket", "OPS-1"}}
+	if got := meta.Fields(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Fields = %v, want %v", got, want)
+	}
+}
+
+func TestMetaFilterMatches(t *testing.T) {
+	v := 0.5
+	meta := EntryMeta{
+		Tags:       []string{"incident", "db"},
+		Mood:       "Tired",
+		Valence:    &v,
+		Agent:      "claude",
+		Branch:     "main",
+		Commit:     "abc1234def",
+		Attributes: map[string]string{"ticket": "OPS-1"},
+	}
+	low, high := 0.0, 0.4
+
+	tests := []struct {
+		name   string
+		filter MetaFilter
+		want   bool
+	}{
+		{"zero", MetaFilter{}, true},
+		{"all tags", MetaFilter{Tags: []string{"Incident", "db"}}, true},
+		{"missing tag", MetaFilter{Tags: []string{"incident", "auth"}}, false},
+		{"mood case-insensitive", MetaFilter{Mood: "tired"}, true},
+		{"min valence", MetaFilter{MinValence: &low}, true},
+		{"max valence", MetaFilter{MaxValence: &high}, false},
+		{"branch", MetaFilter{Branch: "main", Agent: "Claude"}, true},
+		{"other branch", MetaFilter{Branch: "dev"}, false},
+		{"commit prefix", MetaFilter{Commit: "abc123"}, true},
+		{"attribute", MetaFilter{Attributes: map[string]string{"ticket": "OPS-2"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Matches(meta); got != tt.want {
+				t.Errorf("Matches = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if (MetaFilter{MinValence: &low}).Matches(EntryMeta{}) {
+		t.Error("expected valence filter to reject entries without a valence")
+	}
+}
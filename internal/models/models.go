@@ -11,37 +11,30 @@ import (
 
 // JournalEntry represents a private journal entry with named sections.
 type JournalEntry struct {
-	ID        uuid.UUID
-	Sections  map[string]string // feelings, project_notes, user_context, technical_insights, world_knowledge
-	CreatedAt time.Time
-	FilePath  string
-	Type      string // "project" or "user"
-}
-
-// validSections lists the allowed journal section names.
-var validSections = []string{
-	"feelings",
-	"project_notes",
-	"user_context",
-	"technical_insights",
-	"world_knowledge",
-}
+	ID           uuid.UUID         `json:"id"`
+	Sections     map[string]string `json:"sections"` // section name -> content; see SectionDef
+	CreatedAt    time.Time         `json:"created_at"`
+	UpdatedAt    time.Time         `json:"updated_at,omitzero"`     // zero until the entry is edited
+	DeletedAt    time.Time         `json:"deleted_at,omitzero"`     // set while the entry sits in the trash
+	DeleteReason string            `json:"delete_reason,omitempty"` // why a trashed entry was deleted
+	LinkedIDs    []uuid.UUID       `json:"linked_ids,omitempty"`    // entries written alongside this one from the same split call
+	ThoughtID    uuid.UUID         `json:"thought_id,omitzero"`     // shared by every entry split from one write; zero if never split
+	FilePath     string            `json:"path,omitempty"`
+	Type         string            `json:"type"`                // journal name: "project", "user", or a configured one
+	Encrypted    bool              `json:"encrypted,omitempty"` // body is stored encrypted; see storage.WithPassphrase
+	Locked       bool              `json:"locked,omitempty"`    // encrypted body left unread for lack of a passphrase; Sections is empty
+	EntryMeta                      // optional tags, mood, agent, git context, and attributes
 
-// GetValidSections returns a copy of the valid section names.
-func GetValidSections() []string {
-	out := make([]string, len(validSections))
-	copy(out, validSections)
-	return out
+	Redactions []Redaction `json:"-"` // what redaction found in this write; not stored
 }
 
-// IsValidSection returns true if the given section name is valid.
-func IsValidSection(name string) bool {
-	for _, s := range validSections {
-		if s == name {
-			return true
-		}
-	}
-	return false
+// Redaction reports how often a detector matched in one field of a write.
+// The matched text itself is never recorded.
+type Redaction struct {
+	Field    string `json:"field"`    // section name, or "content" for posts
+	Detector string `json:"detector"` // e.g. "aws_access_key", "email"
+	Count    int    `json:"count"`
+	Masked   bool   `json:"masked"` // replaced in the written text; false when only reported
 }
 
 // NewJournalEntry creates a journal entry with generated UUID and timestamp.
@@ -54,6 +47,32 @@ func NewJournalEntry(sections map[string]string, entryType string) *JournalEntry
 	}
 }
 
+// ThoughtKey identifies the thought an entry belongs to: its thought ID when
+// it was split from a larger write, otherwise its own ID.
+func (e *JournalEntry) ThoughtKey() string {
+	if e.ThoughtID != uuid.Nil {
+		return e.ThoughtID.String()
+	}
+	return e.ID.String()
+}
+
+// GroupByThought groups items whose entries share a thought, in the order each
+// thought first appears. entry extracts the journal entry from an item.
+func GroupByThought[T any](items []T, entry func(T) *JournalEntry) [][]T {
+	var groups [][]T
+	index := make(map[string]int)
+	for _, item := range items {
+		key := entry(item).ThoughtKey()
+		if i, ok := index[key]; ok {
+			groups[i] = append(groups[i], item)
+			continue
+		}
+		index[key] = len(groups)
+		groups = append(groups, []T{item})
+	}
+	return groups
+}
+
 // SocialPost represents a social media post.
 type SocialPost struct {
 	ID           uuid.UUID
@@ -63,6 +82,7 @@ type SocialPost struct {
 	CreatedAt    time.Time
 	ParentPostID *uuid.UUID
 	Synced       bool
+	Redactions   []Redaction // what redaction found in this write; not stored
 }
 
 // NewSocialPost creates a social post with generated UUID and timestamp.
@@ -95,11 +115,51 @@ func SectionKey(heading string) string {
 	return strings.Join(parts, "_")
 }
 
-// Embedding represents a vector embedding for a journal entry.
+// ParseSections extracts sections from markdown body text: each "## Title"
+// heading starts the section keyed by SectionKey(Title).
+func ParseSections(body string) map[string]string {
+	sections := make(map[string]string)
+	lines := strings.Split(body, "\n")
+
+	var currentSection string
+	var currentContent strings.Builder
+
+	for _, line := range lines {
+		if strings.HasPrefix(line, "## ") {
+			// Save previous section
+			if currentSection != "" {
+				sections[currentSection] = strings.TrimSpace(currentContent.String())
+			}
+			// Start new section
+			heading := strings.TrimPrefix(line, "## ")
+			currentSection = SectionKey(heading)
+			currentContent.Reset()
+		} else if currentSection != "" {
+			currentContent.WriteString(line)
+			currentContent.WriteString("\n")
+		}
+	}
+
+	// Save last section
+	if currentSection != "" {
+		sections[currentSection] = strings.TrimSpace(currentContent.String())
+	}
+
+	return sections
+}
+
+// Embedding represents vector embeddings for a journal entry.
+// Vector covers the whole entry; SectionVectors holds one vector per section
+// so section-filtered searches can score only the sections they ask for.
+// Model and Dimension identify the embedder that produced the vectors;
+// sidecars written before they were recorded leave them empty.
 type Embedding struct {
-	Vector    []float32 `json:"vector"`
-	Text      string    `json:"text"`
-	Sections  []string  `json:"sections"`
-	Timestamp int64     `json:"timestamp"`
-	Path      string    `json:"path"`
+	Model          string               `json:"model,omitempty"`
+	Dimension      int                  `json:"dimension,omitempty"`
+	Vector         []float32            `json:"vector"`
+	SectionVectors map[string][]float32 `json:"section_vectors,omitempty"`
+	Text           string               `json:"text"`
+	Sections       []string             `json:"sections"`
+	Timestamp      int64                `json:"timestamp"`
+	Path           string               `json:"path"`
 }
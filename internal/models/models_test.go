@@ -4,6 +4,8 @@ package models
 
 import (
 	"testing"
+
+	"github.com/google/uuid"
 )
 
 func TestIsValidSection(t *testing.T) {
@@ -45,3 +47,25 @@ func TestGetValidSectionsReturnsCopy(t *testing.T) {
 		t.Error("GetValidSections returned shared slice, not a copy")
 	}
 }
+
+func TestGroupByThought(t *testing.T) {
+	thought := uuid.New()
+	a := &JournalEntry{ID: uuid.New(), ThoughtID: thought}
+	b := &JournalEntry{ID: uuid.New()}
+	c := &JournalEntry{ID: uuid.New(), ThoughtID: thought}
+
+	if a.ThoughtKey() != thought.String() || b.ThoughtKey() != b.ID.String() {
+		t.Errorf("unexpected thought keys %q, %q", a.ThoughtKey(), b.ThoughtKey())
+	}
+
+	groups := GroupByThought([]*JournalEntry{a, b, c}, func(e *JournalEntry) *JournalEntry { return e })
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 groups, got %d", len(groups))
+	}
+	if len(groups[0]) != 2 || groups[0][0] != a || groups[0][1] != c {
+		t.Errorf("expected siblings grouped under the first, got %v", groups[0])
+	}
+	if len(groups[1]) != 1 || groups[1][0] != b {
+		t.Errorf("expected unsplit entry alone, got %v", groups[1])
+	}
+}
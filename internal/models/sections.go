@@ -0,0 +1,219 @@
+// ABOUTME: Journal section schema: the configurable set of sections entries may contain.
+// ABOUTME: Holds each section's description, routing target, sync policy, and required flag, defaulting to the built-in five.
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// SectionDef describes a journal section: its snake_case key, what it is for,
+// which journal it routes to, whether it may be sent to the remote API, and
+// whether every write must include it.
+type SectionDef struct {
+	Name        string
+	Description string
+	Route       string // a journal name, e.g. "project" or "user"
+	Sync        string // SyncAlways (if empty), SyncOptIn, or SyncNever
+	Required    bool
+}
+
+// Sync policies decide which sections a write sends to the remote API.
+const (
+	SyncAlways = "always" // sent with every synced write
+	SyncOptIn  = "opt-in" // sent only when the write asks for it
+	SyncNever  = "never"  // kept local
+)
+
+// defaultSections are the built-in sections used when config declares none.
+// The personal ones stay local unless a write opts in.
+var defaultSections = []SectionDef{
+	{Name: "feelings", Description: "Your private space to be completely honest about what you're feeling and thinking.", Route: "user", Sync: SyncOptIn},
+	{Name: "project_notes", Description: "Private technical laboratory for capturing insights about the current project.", Route: "project", Sync: SyncAlways},
+	{Name: "user_context", Description: "Private field notes about working with your human collaborator.", Route: "user", Sync: SyncOptIn},
+	{Name: "technical_insights", Description: "Private software engineering notebook for broader learnings.", Route: "user", Sync: SyncAlways},
+	{Name: "world_knowledge", Description: "Private learning journal for everything else interesting or useful.", Route: "user", Sync: SyncAlways},
+}
+
+// reservedSectionNames collide with tool arguments and CLI flags.
+var reservedSectionNames = map[string]bool{
+	"path":        true,
+	"section":     true,
+	"content":     true,
+	"reason":      true,
+	"type":        true,
+	"limit":       true,
+	"days":        true,
+	"linked":      true,
+	"private":     true,
+	"sync":        true,
+	"thought":     true,
+	"query":       true,
+	"sections":    true,
+	"tags":        true,
+	"mood":        true,
+	"valence":     true,
+	"agent":       true,
+	"repo":        true,
+	"branch":      true,
+	"commit":      true,
+	"attributes":  true,
+	"min_valence": true,
+	"max_valence": true,
+}
+
+var sectionNamePattern = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`)
+
+var (
+	sectionsMu sync.RWMutex
+	sections   = defaultSections
+)
+
+// DefaultSections returns a copy of the built-in section definitions.
+func DefaultSections() []SectionDef {
+	out := make([]SectionDef, len(defaultSections))
+	copy(out, defaultSections)
+	return out
+}
+
+// SetSections replaces the active section schema. Names must be unique
+// snake_case keys, routes must be well-formed journal names (the store
+// checks that they exist), and sync policies one of the Sync constants, with
+// empty meaning SyncAlways. Rendering follows the order given.
+func SetSections(defs []SectionDef) error {
+	if len(defs) == 0 {
+		return fmt.Errorf("at least one section must be defined")
+	}
+	seen := make(map[string]bool, len(defs))
+	out := make([]SectionDef, len(defs))
+	for i, def := range defs {
+		if !sectionNamePattern.MatchString(def.Name) {
+			return fmt.Errorf("invalid section name %q: use lowercase snake_case", def.Name)
+		}
+		if reservedSectionNames[def.Name] {
+			return fmt.Errorf("section name %q is reserved", def.Name)
+		}
+		if seen[def.Name] {
+			return fmt.Errorf("duplicate section %q", def.Name)
+		}
+		seen[def.Name] = true
+		if !IsBuiltinJournal(def.Route) && CheckJournalName(def.Route) != nil {
+			return fmt.Errorf("invalid route %q for section %q: must name a journal", def.Route, def.Name)
+		}
+		switch def.Sync {
+		case "":
+			def.Sync = SyncAlways
+		case SyncAlways, SyncOptIn, SyncNever:
+		default:
+			return fmt.Errorf("invalid sync %q for section %q: must be always, opt-in, or never", def.Sync, def.Name)
+		}
+		out[i] = def
+	}
+
+	sectionsMu.Lock()
+	defer sectionsMu.Unlock()
+	sections = out
+	return nil
+}
+
+// Sections returns a copy of the active section definitions in order.
+func Sections() []SectionDef {
+	sectionsMu.RLock()
+	defer sectionsMu.RUnlock()
+	out := make([]SectionDef, len(sections))
+	copy(out, sections)
+	return out
+}
+
+// LookupSection returns the definition of the named section.
+func LookupSection(name string) (SectionDef, bool) {
+	sectionsMu.RLock()
+	defer sectionsMu.RUnlock()
+	for _, def := range sections {
+		if def.Name == name {
+			return def, true
+		}
+	}
+	return SectionDef{}, false
+}
+
+// GetValidSections returns the active section names in order.
+func GetValidSections() []string {
+	defs := Sections()
+	out := make([]string, len(defs))
+	for i, def := range defs {
+		out[i] = def.Name
+	}
+	return out
+}
+
+// SectionNames returns the names in sections in schema order, followed by
+// names the schema no longer declares, sorted, so stored content that predates
+// a schema change is never dropped.
+func SectionNames(sections map[string]string) []string {
+	names := make([]string, 0, len(sections))
+	for _, name := range GetValidSections() {
+		if _, ok := sections[name]; ok {
+			names = append(names, name)
+		}
+	}
+	var extra []string
+	for name := range sections {
+		if !IsValidSection(name) {
+			extra = append(extra, name)
+		}
+	}
+	sort.Strings(extra)
+	return append(names, extra...)
+}
+
+// IsValidSection returns true if the given section name is valid.
+func IsValidSection(name string) bool {
+	_, ok := LookupSection(name)
+	return ok
+}
+
+// RequiredSections returns the names of sections every write must include.
+func RequiredSections() []string {
+	var out []string
+	for _, def := range Sections() {
+		if def.Required {
+			out = append(out, def.Name)
+		}
+	}
+	return out
+}
+
+// SyncableSections splits sections into those the sync policy lets a write
+// send to the remote API and the names of those it keeps local, in schema
+// order. optIn includes SyncOptIn sections. Undeclared sections never sync.
+func SyncableSections(sections map[string]string, optIn bool) (map[string]string, []string) {
+	synced := make(map[string]string, len(sections))
+	var local []string
+	for _, def := range Sections() {
+		content, ok := sections[def.Name]
+		if !ok {
+			continue
+		}
+		if def.Sync == SyncAlways || (def.Sync == SyncOptIn && optIn) {
+			synced[def.Name] = content
+		} else {
+			local = append(local, def.Name)
+		}
+	}
+	for name := range sections {
+		if !IsValidSection(name) {
+			local = append(local, name)
+		}
+	}
+	return synced, local
+}
+
+// ValidSectionList returns the active section names as a comma-separated
+// list, for error messages.
+func ValidSectionList() string {
+	return strings.Join(GetValidSections(), ", ")
+}
@@ -0,0 +1,101 @@
+// ABOUTME: Tests for the configurable journal section schema.
+// ABOUTME: Covers validation, replacement of the active sections, required lookups, name ordering, and sync policy.
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSetSectionsReplacesSchema(t *testing.T) {
+	t.Cleanup(func() { _ = SetSections(DefaultSections()) })
+
+	defs := append(DefaultSections(), SectionDef{Name: "decisions", Route: "project", Required: true})
+	if err := SetSections(defs); err != nil {
+		t.Fatalf("SetSections error: %v", err)
+	}
+
+	if !IsValidSection("decisions") {
+		t.Error("expected custom section to be valid")
+	}
+	if def, ok := LookupSection("decisions"); !ok || def.Route != "project" {
+		t.Errorf("unexpected lookup result: %+v, %v", def, ok)
+	}
+	if req := RequiredSections(); len(req) != 1 || req[0] != "decisions" {
+		t.Errorf("RequiredSections() = %v", req)
+	}
+	names := GetValidSections()
+	if names[len(names)-1] != "decisions" {
+		t.Errorf("expected declared order to be kept, got %v", names)
+	}
+}
+
+func TestSectionNamesKeepsUndeclaredSections(t *testing.T) {
+	t.Cleanup(func() { _ = SetSections(DefaultSections()) })
+	if err := SetSections([]SectionDef{{Name: "feelings", Route: "user"}, {Name: "decisions", Route: "project"}}); err != nil {
+		t.Fatalf("SetSections error: %v", err)
+	}
+
+	// project_notes and blockers were written under an older schema
+	sections := map[string]string{"project_notes": "a", "decisions": "b", "blockers": "c", "feelings": "d"}
+	if got := strings.Join(SectionNames(sections), ","); got != "feelings,decisions,blockers,project_notes" {
+		t.Errorf("SectionNames() = %s", got)
+	}
+}
+
+func TestSetSectionsValidates(t *testing.T) {
+	t.Cleanup(func() { _ = SetSections(DefaultSections()) })
+
+	tests := []struct {
+		name string
+		defs []SectionDef
+	}{
+		{"empty", nil},
+		{"bad name", []SectionDef{{Name: "Bad Name", Route: "user"}}},
+		{"double underscore", []SectionDef{{Name: "a__b", Route: "user"}}},
+		{"reserved", []SectionDef{{Name: "path", Route: "user"}}},
+		{"duplicate", []SectionDef{{Name: "retro", Route: "user"}, {Name: "retro", Route: "user"}}},
+		{"bad route", []SectionDef{{Name: "retro", Route: "Team"}}},
+		{"route to all", []SectionDef{{Name: "retro", Route: "all"}}},
+		{"bad sync", []SectionDef{{Name: "retro", Route: "user", Sync: "sometimes"}}},
+	}
+	for _, tt := range tests {
+		if err := SetSections(tt.defs); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+	if !IsValidSection("feelings") {
+		t.Error("a rejected schema must leave the active sections unchanged")
+	}
+}
+
+func TestSyncableSections(t *testing.T) {
+	t.Cleanup(func() { _ = SetSections(DefaultSections()) })
+	defs := append(DefaultSections(), SectionDef{Name: "secrets", Route: "user", Sync: SyncNever}, SectionDef{Name: "retro", Route: "user"})
+	if err := SetSections(defs); err != nil {
+		t.Fatalf("SetSections error: %v", err)
+	}
+
+	sections := map[string]string{
+		"feelings":      "tired",
+		"project_notes": "shipped",
+		"secrets":       "hunter2",
+		"retro":         "went well",
+		"undeclared":    "?",
+	}
+	synced, local := SyncableSections(sections, false)
+	if len(synced) != 2 || synced["project_notes"] != "shipped" || synced["retro"] != "went well" {
+		t.Errorf("expected always sections only, got %v", synced)
+	}
+	if strings.Join(local, ",") != "feelings,secrets,undeclared" {
+		t.Errorf("expected the rest kept local in schema order, got %v", local)
+	}
+
+	synced, local = SyncableSections(sections, true)
+	if synced["feelings"] != "tired" || synced["secrets"] != "" {
+		t.Errorf("expected opting in to add only opt-in sections, got %v", synced)
+	}
+	if strings.Join(local, ",") != "secrets,undeclared" {
+		t.Errorf("got local %v", local)
+	}
+}
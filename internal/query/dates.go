@@ -0,0 +1,140 @@
+// ABOUTME: Date expressions shared by query fields and listing flags.
+// ABOUTME: Accepts ISO dates and timestamps, today/yesterday, and relative ages like 3d or 2w, in local time.
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DateRange is the span a date expression names: a whole local day for
+// "2026-03-01" or "yesterday", a single instant for "2h" or a timestamp.
+// Start is inclusive and End exclusive; they are equal for instants.
+type DateRange struct {
+	Start time.Time
+	End   time.Time
+}
+
+// timestampLayouts are the accepted absolute forms finer than a day.
+var timestampLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+}
+
+// ParseDate parses a date expression relative to now, in now's time zone:
+//
+//	2026-03-01            that whole day
+//	2026-03-01T15:04      that instant (seconds and a zone offset optional)
+//	today, yesterday      that whole day
+//	now                   now
+//	3h                    three hours before now
+//	3d, 2w, 6m, 1y        the start of the day that many days, weeks,
+//	                      months, or years before today
+func ParseDate(value string, now time.Time) (DateRange, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	loc := now.Location()
+	today := StartOfDay(now)
+
+	switch value {
+	case "":
+		return DateRange{}, fmt.Errorf("empty date")
+	case "now":
+		return DateRange{Start: now, End: now}, nil
+	case "today":
+		return dayRange(today), nil
+	case "yesterday":
+		return dayRange(today.AddDate(0, 0, -1)), nil
+	}
+
+	if day, err := time.ParseInLocation("2006-01-02", value, loc); err == nil {
+		return dayRange(day), nil
+	}
+	for _, layout := range timestampLayouts {
+		if t, err := time.ParseInLocation(layout, strings.ToUpper(value), loc); err == nil {
+			return DateRange{Start: t, End: t}, nil
+		}
+	}
+
+	if n, unit, ok := splitAge(value); ok {
+		var start time.Time
+		switch unit {
+		case "h":
+			start = now.Add(-time.Duration(n) * time.Hour)
+			return DateRange{Start: start, End: start}, nil
+		case "d":
+			start = today.AddDate(0, 0, -n)
+		case "w":
+			start = today.AddDate(0, 0, -7*n)
+		case "m":
+			start = today.AddDate(0, -n, 0)
+		case "y":
+			start = today.AddDate(-n, 0, 0)
+		}
+		return DateRange{Start: start, End: start}, nil
+	}
+
+	return DateRange{}, fmt.Errorf("invalid date %q: use YYYY-MM-DD, a timestamp, today, yesterday, or an age like 3d or 2w", value)
+}
+
+// ParseBounds resolves since and until expressions to a half-open interval
+// [start, end). since begins at the start of its span and until ends at the
+// end of its span, so "--since 2026-03-01 --until 2026-03-01" covers that
+// whole day. An empty expression leaves its bound zero.
+func ParseBounds(since, until string, now time.Time) (start, end time.Time, err error) {
+	if since != "" {
+		r, err := ParseDate(since, now)
+		if err != nil {
+			return start, end, fmt.Errorf("invalid since: %w", err)
+		}
+		start = r.Start
+	}
+	if until != "" {
+		r, err := ParseDate(until, now)
+		if err != nil {
+			return start, end, fmt.Errorf("invalid until: %w", err)
+		}
+		end = r.End
+	}
+	if !start.IsZero() && !end.IsZero() && !start.Before(end) {
+		return start, end, fmt.Errorf("since (%s) must be before until (%s)", since, until)
+	}
+	return start, end, nil
+}
+
+// StartOfDay returns midnight at the start of t's day in t's time zone.
+func StartOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+// DaysAgo returns the start of the local day days before now, so "the last
+// 1 day" covers yesterday and today.
+func DaysAgo(days int, now time.Time) time.Time {
+	return StartOfDay(now).AddDate(0, 0, -days)
+}
+
+// dayRange spans the local day starting at day.
+func dayRange(day time.Time) DateRange {
+	return DateRange{Start: day, End: day.AddDate(0, 0, 1)}
+}
+
+// splitAge splits a relative age such as "14d" into its count and unit.
+func splitAge(value string) (int, string, bool) {
+	if len(value) < 2 {
+		return 0, "", false
+	}
+	unit := value[len(value)-1:]
+	if !strings.Contains("hdwmy", unit) {
+		return 0, "", false
+	}
+	n, err := strconv.Atoi(value[:len(value)-1])
+	if err != nil || n < 0 {
+		return 0, "", false
+	}
+	return n, unit, true
+}
@@ -0,0 +1,83 @@
+// ABOUTME: Tests for date expression parsing.
+// ABOUTME: Covers ISO dates, timestamps, named days, relative ages, and since/until bounds in a non-UTC zone.
+package query
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	// 00:30 local is still the previous day in UTC, which catches any
+	// accidental UTC day arithmetic
+	loc := time.FixedZone("UTC+5", 5*60*60)
+	now := time.Date(2026, 3, 10, 0, 30, 0, 0, loc)
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, loc) }
+
+	tests := []struct {
+		value      string
+		start, end time.Time
+	}{
+		{"2026-03-01", day(2026, 3, 1), day(2026, 3, 2)},
+		{"today", day(2026, 3, 10), day(2026, 3, 11)},
+		{"Yesterday", day(2026, 3, 9), day(2026, 3, 10)},
+		{"now", now, now},
+		{"2026-03-01T15:04", time.Date(2026, 3, 1, 15, 4, 0, 0, loc), time.Date(2026, 3, 1, 15, 4, 0, 0, loc)},
+		{"2026-03-01 15:04:05", time.Date(2026, 3, 1, 15, 4, 5, 0, loc), time.Date(2026, 3, 1, 15, 4, 5, 0, loc)},
+		{"3h", now.Add(-3 * time.Hour), now.Add(-3 * time.Hour)},
+		{"3d", day(2026, 3, 7), day(2026, 3, 7)},
+		{"2w", day(2026, 2, 24), day(2026, 2, 24)},
+		{"1m", day(2026, 2, 10), day(2026, 2, 10)},
+		{"1y", day(2025, 3, 10), day(2025, 3, 10)},
+	}
+	for _, tt := range tests {
+		r, err := ParseDate(tt.value, now)
+		if err != nil {
+			t.Errorf("ParseDate(%q) error: %v", tt.value, err)
+			continue
+		}
+		if !r.Start.Equal(tt.start) || !r.End.Equal(tt.end) {
+			t.Errorf("ParseDate(%q) = [%v, %v), want [%v, %v)", tt.value, r.Start, r.End, tt.start, tt.end)
+		}
+	}
+
+	rfc, err := ParseDate("2026-03-01T10:00:00Z", now)
+	if err != nil {
+		t.Fatalf("ParseDate RFC3339 error: %v", err)
+	}
+	if !rfc.Start.Equal(time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("RFC3339 offset should be honored, got %v", rfc.Start)
+	}
+
+	for _, bad := range []string{"", "someday", "3x", "-2d", "2026-13-01"} {
+		if _, err := ParseDate(bad, now); err == nil {
+			t.Errorf("ParseDate(%q) expected error", bad)
+		}
+	}
+}
+
+func TestParseBounds(t *testing.T) {
+	loc := time.FixedZone("UTC-7", -7*60*60)
+	now := time.Date(2026, 3, 10, 23, 0, 0, 0, loc)
+
+	start, end, err := ParseBounds("2026-03-01", "2026-03-01", now)
+	if err != nil {
+		t.Fatalf("ParseBounds error: %v", err)
+	}
+	if !start.Equal(time.Date(2026, 3, 1, 0, 0, 0, 0, loc)) || !end.Equal(time.Date(2026, 3, 2, 0, 0, 0, 0, loc)) {
+		t.Errorf("same-day bounds should cover the whole day, got [%v, %v)", start, end)
+	}
+
+	start, end, err = ParseBounds("", "", now)
+	if err != nil || !start.IsZero() || !end.IsZero() {
+		t.Errorf("empty bounds should be zero, got [%v, %v) err %v", start, end, err)
+	}
+
+	if _, _, err := ParseBounds("today", "1w", now); err == nil || !strings.Contains(err.Error(), "must be before") {
+		t.Errorf("expected inverted range error, got %v", err)
+	}
+	if _, _, err := ParseBounds("whenever", "", now); err == nil || !strings.Contains(err.Error(), "invalid since") {
+		t.Errorf("expected invalid since error, got %v", err)
+	}
+}
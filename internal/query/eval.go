@@ -0,0 +1,266 @@
+// ABOUTME: Evaluation of parsed search queries against journal entries.
+// ABOUTME: Provides boolean matching plus the free text and section scope used for ranking.
+package query
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/2389-research/pulse/internal/models"
+)
+
+// Boolean reports whether the query filters entries: it uses an operator, a
+// phrase, a field, or grouping. A query of plain words is not boolean; it is
+// ranked by relevance and may match by meaning rather than exact words.
+func (q *Query) Boolean() bool {
+	return q.boolean
+}
+
+// Match reports whether entry satisfies the query. Queries that are not
+// boolean match every entry; ranking decides relevance.
+func (q *Query) Match(entry *models.JournalEntry) bool {
+	if !q.Boolean() {
+		return true
+	}
+	return q.Root.match(entry, entryText(entry))
+}
+
+// Text returns the words and phrases that are not negated, for relevance
+// ranking and snippets.
+func (q *Query) Text() string {
+	var parts []string
+	var walk func(Node, bool)
+	walk = func(n Node, negated bool) {
+		switch n := n.(type) {
+		case *Term:
+			if !negated {
+				parts = append(parts, n.Text)
+			}
+		case *And:
+			for _, c := range n.Nodes {
+				walk(c, negated)
+			}
+		case *Or:
+			for _, c := range n.Nodes {
+				walk(c, negated)
+			}
+		case *Not:
+			walk(n.Node, !negated)
+		}
+	}
+	if q.Root != nil {
+		walk(q.Root, false)
+	}
+	return strings.Join(parts, " ")
+}
+
+// Sections returns the sections every match must have, from section: fields
+// joined to the rest of the query by AND. Ranking is scoped to them.
+func (q *Query) Sections() []string {
+	var sections []string
+	for _, n := range q.conjuncts() {
+		if f, ok := n.(*Field); ok && f.Name == FieldSection && !contains(sections, f.Value) {
+			sections = append(sections, f.Value)
+		}
+	}
+	sort.Strings(sections)
+	return sections
+}
+
+// CheckTypes returns a *SyntaxError for the first type: field naming none of
+// journals, the journals of the store being searched.
+func (q *Query) CheckTypes(journals []string) error {
+	var err error
+	var walk func(Node)
+	walk = func(n Node) {
+		switch n := n.(type) {
+		case *Field:
+			if err == nil && n.Name == FieldType && !contains(journals, n.Value) {
+				err = &SyntaxError{Input: q.Input, Column: n.valueColumn,
+					Msg: fmt.Sprintf("invalid type %q: must be one of: %s", n.Value, strings.Join(journals, ", "))}
+			}
+		case *And:
+			for _, c := range n.Nodes {
+				walk(c)
+			}
+		case *Or:
+			for _, c := range n.Nodes {
+				walk(c)
+			}
+		case *Not:
+			walk(n.Node)
+		}
+	}
+	if q.Root != nil {
+		walk(q.Root)
+	}
+	return err
+}
+
+// RequiresTerm reports whether every entry the query matches must contain
+// at least one of its non-negated words or phrases. When true, candidates
+// can be narrowed to entries sharing a term with Text.
+func (q *Query) RequiresTerm() bool {
+	if q.Root == nil {
+		return false
+	}
+	return requiresTerm(q.Root)
+}
+
+func requiresTerm(n Node) bool {
+	switch n := n.(type) {
+	case *Term:
+		return true
+	case *And:
+		for _, c := range n.Nodes {
+			if requiresTerm(c) {
+				return true
+			}
+		}
+		return false
+	case *Or:
+		for _, c := range n.Nodes {
+			if !requiresTerm(c) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+// conjuncts returns the nodes joined by the query's top-level AND.
+func (q *Query) conjuncts() []Node {
+	switch root := q.Root.(type) {
+	case nil:
+		return nil
+	case *And:
+		return root.Nodes
+	default:
+		return []Node{root}
+	}
+}
+
+func (t *Term) match(_ *models.JournalEntry, text string) bool {
+	if t.Phrase {
+		return strings.Contains(text, normalizeSpace(strings.ToLower(t.Text)))
+	}
+	// Every word of the term must start some word of the entry, so "deploy"
+	// finds "deployment" and "ci/cd" needs both "ci" and "cd"
+	entryWords := words(text)
+	termWords := words(t.Text)
+	if len(termWords) == 0 {
+		return strings.Contains(text, strings.ToLower(t.Text))
+	}
+	for _, want := range termWords {
+		found := false
+		for _, w := range entryWords {
+			if strings.HasPrefix(w, want) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *Field) match(e *models.JournalEntry, _ string) bool {
+	switch f.Name {
+	case FieldSection:
+		return strings.TrimSpace(e.Sections[f.Value]) != ""
+	case FieldType:
+		return e.Type == f.Value
+	case FieldTag:
+		for _, tag := range e.Tags {
+			if strings.EqualFold(tag, f.Value) {
+				return true
+			}
+		}
+		return false
+	case FieldAuthor:
+		return strings.EqualFold(e.Agent, f.Value)
+	case FieldBefore:
+		return e.CreatedAt.Before(f.Range.Start)
+	case FieldAfter:
+		return !e.CreatedAt.Before(f.Range.End)
+	}
+	return false
+}
+
+// match scopes the terms joined by a to the sections named by section:
+// fields among them, so section:feelings deploy needs deploy in feelings.
+func (a *And) match(e *models.JournalEntry, text string) bool {
+	var sections []string
+	for _, n := range a.Nodes {
+		if f, ok := n.(*Field); ok && f.Name == FieldSection && !contains(sections, f.Value) {
+			sections = append(sections, f.Value)
+		}
+	}
+	if len(sections) > 0 {
+		text = sectionsText(e, sections)
+	}
+	for _, n := range a.Nodes {
+		if !n.match(e, text) {
+			return false
+		}
+	}
+	return true
+}
+
+func (o *Or) match(e *models.JournalEntry, text string) bool {
+	for _, n := range o.Nodes {
+		if n.match(e, text) {
+			return true
+		}
+	}
+	return false
+}
+
+func (n *Not) match(e *models.JournalEntry, text string) bool {
+	return !n.Node.match(e, text)
+}
+
+// entryText is the lowercased, space-normalized text of every section.
+func entryText(e *models.JournalEntry) string {
+	parts := make([]string, 0, len(e.Sections))
+	for _, content := range e.Sections {
+		parts = append(parts, content)
+	}
+	return normalizeSpace(strings.ToLower(strings.Join(parts, "\n")))
+}
+
+// sectionsText is entryText restricted to the named sections.
+func sectionsText(e *models.JournalEntry, sections []string) string {
+	parts := make([]string, 0, len(sections))
+	for _, name := range sections {
+		parts = append(parts, e.Sections[name])
+	}
+	return normalizeSpace(strings.ToLower(strings.Join(parts, "\n")))
+}
+
+// words lowercases text and splits it on anything that isn't a letter or digit.
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// normalizeSpace collapses runs of whitespace to single spaces.
+func normalizeSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
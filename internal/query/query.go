@@ -0,0 +1,454 @@
+// ABOUTME: Parser for the journal search query language shared by the MCP tools and the CLI.
+// ABOUTME: Turns phrases, AND/OR/NOT, grouping, and field filters into an AST with column-aware syntax errors.
+package query
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/2389-research/pulse/internal/models"
+)
+
+// Grammar, lowest precedence first. Juxtaposed terms are ANDed; operators
+// are uppercase so lowercase "and", "or", and "not" stay ordinary words.
+//
+//	query   := or
+//	or      := and ("OR" and)*
+//	and     := unary (["AND"] unary)*
+//	unary   := ("NOT" | "-") unary | primary
+//	primary := "(" or ")" | phrase | field | word
+//	field   := name ":" (word | phrase)
+
+// Field names recognised before a colon. Any other word containing a colon
+// is an ordinary word, so text like "error: timeout" still searches.
+const (
+	FieldSection = "section"
+	FieldType    = "type"
+	FieldBefore  = "before"
+	FieldAfter   = "after"
+	FieldTag     = "tag"
+	FieldAuthor  = "author"
+)
+
+var knownFields = map[string]bool{
+	FieldSection: true,
+	FieldType:    true,
+	FieldBefore:  true,
+	FieldAfter:   true,
+	FieldTag:     true,
+	FieldAuthor:  true,
+}
+
+// Node is an element of a parsed query.
+type Node interface {
+	String() string
+	match(e *models.JournalEntry, text string) bool
+}
+
+// Term matches entries whose text contains it. A bare word matches words
+// starting with it; a phrase must appear verbatim, ignoring case and spacing.
+type Term struct {
+	Text   string
+	Phrase bool
+	Column int
+}
+
+// Field filters entries by metadata, e.g. tag:incident or before:2026-03-01.
+type Field struct {
+	Name   string
+	Value  string
+	Column int
+	Range  DateRange // the span named by a before: or after: value
+
+	valueColumn int // column of Value, for errors found after parsing
+}
+
+// And matches entries matched by every child.
+type And struct{ Nodes []Node }
+
+// Or matches entries matched by any child.
+type Or struct{ Nodes []Node }
+
+// Not matches entries its child does not match.
+type Not struct{ Node Node }
+
+// Query is a parsed search query.
+type Query struct {
+	Input string
+	Root  Node // nil for an empty query
+
+	boolean bool // uses an operator, phrase, field, or parentheses; see Boolean
+}
+
+// SyntaxError reports a problem at a 1-based column of the query.
+type SyntaxError struct {
+	Input  string
+	Column int
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("query syntax error at column %d: %s\n  %s\n  %s^",
+		e.Column, e.Msg, e.Input, strings.Repeat(" ", e.Column-1))
+}
+
+// tokenKind classifies lexer tokens.
+type tokenKind int
+
+const (
+	tokWord tokenKind = iota
+	tokPhrase
+	tokField
+	tokAnd
+	tokOr
+	tokNot
+	tokLParen
+	tokRParen
+	tokEOF
+)
+
+// token is one lexeme with the 1-based column it starts at.
+type token struct {
+	kind     tokenKind
+	text     string // word or phrase text; field value for tokField
+	field    string // field name for tokField
+	col      int
+	valueCol int // column of a field's value
+}
+
+// Parse parses input into a Query. An empty or blank input yields a query
+// that matches everything.
+func Parse(input string) (*Query, error) {
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{input: input, tokens: tokens}
+	q := &Query{Input: input}
+	if p.peek().kind == tokEOF {
+		return q, nil
+	}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		if tok.kind == tokRParen {
+			return nil, p.errorf(tok.col, "unexpected ) with no matching (")
+		}
+		return nil, p.errorf(tok.col, "unexpected %s", describe(tok))
+	}
+	q.Root = root
+	q.boolean = p.boolean
+	return q, nil
+}
+
+// lex splits input into tokens, tracking rune columns.
+func lex(input string) ([]token, error) {
+	var tokens []token
+	runes := []rune(input)
+	col := func(i int) int { return i + 1 }
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokLParen, col: col(i)})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokRParen, col: col(i)})
+			i++
+		case r == '"':
+			text, next, err := lexPhrase(input, runes, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokPhrase, text: text, col: col(i)})
+			i = next
+		case r == '-' && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) && (i == 0 || isBoundary(runes[i-1])):
+			tokens = append(tokens, token{kind: tokNot, text: "-", col: col(i)})
+			i++
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && runes[i] != '(' && runes[i] != ')' && runes[i] != '"' {
+				i++
+			}
+			word := string(runes[start:i])
+			switch word {
+			case "AND":
+				tokens = append(tokens, token{kind: tokAnd, text: word, col: col(start)})
+				continue
+			case "OR":
+				tokens = append(tokens, token{kind: tokOr, text: word, col: col(start)})
+				continue
+			case "NOT":
+				tokens = append(tokens, token{kind: tokNot, text: word, col: col(start)})
+				continue
+			}
+
+			name, value, found := strings.Cut(word, ":")
+			if !found || !knownFields[strings.ToLower(name)] {
+				tokens = append(tokens, token{kind: tokWord, text: word, col: col(start)})
+				continue
+			}
+			tok := token{kind: tokField, field: strings.ToLower(name), text: value, col: col(start)}
+			tok.valueCol = tok.col + utf8.RuneCountInString(name) + 1
+			// A quoted value directly after the colon: author:"Jane Doe"
+			if value == "" && i < len(runes) && runes[i] == '"' {
+				text, next, err := lexPhrase(input, runes, i)
+				if err != nil {
+					return nil, err
+				}
+				tok.text = text
+				i = next
+			}
+			tokens = append(tokens, tok)
+		}
+	}
+	return append(tokens, token{kind: tokEOF, col: len(runes) + 1}), nil
+}
+
+// lexPhrase reads a quoted phrase starting at the quote at runes[start],
+// returning its text and the index after the closing quote.
+func lexPhrase(input string, runes []rune, start int) (string, int, error) {
+	end := start + 1
+	for end < len(runes) && runes[end] != '"' {
+		end++
+	}
+	if end >= len(runes) {
+		return "", 0, &SyntaxError{Input: input, Column: start + 1, Msg: "unterminated quoted phrase"}
+	}
+	return string(runes[start+1 : end]), end + 1, nil
+}
+
+// isBoundary reports whether r may precede a "-" negation prefix.
+func isBoundary(r rune) bool {
+	return unicode.IsSpace(r) || r == '('
+}
+
+// parser is a recursive-descent parser over lexer tokens.
+type parser struct {
+	input   string
+	tokens  []token
+	pos     int
+	boolean bool // consumed something other than a plain word
+}
+
+func (p *parser) peek() token { return p.tokens[p.pos] }
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	if tok.kind != tokWord && tok.kind != tokEOF {
+		p.boolean = true
+	}
+	return tok
+}
+
+func (p *parser) errorf(col int, format string, args ...interface{}) error {
+	return &SyntaxError{Input: p.input, Column: col, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) parseOr() (Node, error) {
+	first, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	nodes := []Node{first}
+	for p.peek().kind == tokOr {
+		op := p.next()
+		if err := p.expectOperand(op); err != nil {
+			return nil, err
+		}
+		node, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	if len(nodes) == 1 {
+		return first, nil
+	}
+	return &Or{Nodes: nodes}, nil
+}
+
+func (p *parser) parseAnd() (Node, error) {
+	first, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	nodes := []Node{first}
+	for {
+		tok := p.peek()
+		if tok.kind == tokAnd {
+			p.next()
+			if err := p.expectOperand(tok); err != nil {
+				return nil, err
+			}
+		} else if !startsOperand(tok.kind) {
+			break
+		}
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	if len(nodes) == 1 {
+		return first, nil
+	}
+	return &And{Nodes: nodes}, nil
+}
+
+func (p *parser) parseUnary() (Node, error) {
+	if tok := p.peek(); tok.kind == tokNot {
+		p.next()
+		if err := p.expectOperand(tok); err != nil {
+			return nil, err
+		}
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &Not{Node: node}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Node, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokWord:
+		return &Term{Text: tok.text, Column: tok.col}, nil
+	case tokPhrase:
+		if strings.TrimSpace(tok.text) == "" {
+			return nil, p.errorf(tok.col, "empty quoted phrase")
+		}
+		return &Term{Text: tok.text, Phrase: true, Column: tok.col}, nil
+	case tokField:
+		return p.parseField(tok)
+	case tokLParen:
+		if p.peek().kind == tokRParen {
+			return nil, p.errorf(p.peek().col, "empty parentheses")
+		}
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.peek(); closing.kind != tokRParen {
+			return nil, p.errorf(closing.col, "expected ) to close ( at column %d", tok.col)
+		}
+		p.next()
+		return node, nil
+	case tokRParen:
+		return nil, p.errorf(tok.col, "unexpected ) with no matching (")
+	case tokEOF:
+		return nil, p.errorf(tok.col, "unexpected end of query")
+	default:
+		return nil, p.errorf(tok.col, "unexpected %s", describe(tok))
+	}
+}
+
+// parseField validates a field token's value.
+func (p *parser) parseField(tok token) (Node, error) {
+	value := strings.TrimSpace(tok.text)
+	if value == "" {
+		return nil, p.errorf(tok.valueCol, "expected a value after %s:", tok.field)
+	}
+	field := &Field{Name: tok.field, Value: value, Column: tok.col, valueColumn: tok.valueCol}
+
+	// type: values depend on the store searched; see CheckTypes
+	switch tok.field {
+	case FieldSection:
+		if !models.IsValidSection(value) {
+			return nil, p.errorf(tok.valueCol, "unknown section %q: valid sections are %s", value, models.ValidSectionList())
+		}
+	case FieldBefore, FieldAfter:
+		dates, err := ParseDate(value, time.Now())
+		if err != nil {
+			return nil, p.errorf(tok.valueCol, "%v", err)
+		}
+		field.Range = dates
+	}
+	return field, nil
+}
+
+// expectOperand fails when an operator is followed by nothing it can apply to.
+func (p *parser) expectOperand(op token) error {
+	next := p.peek()
+	if startsOperand(next.kind) {
+		return nil
+	}
+	if next.kind == tokEOF {
+		return p.errorf(next.col, "expected a term after %s at column %d", op.text, op.col)
+	}
+	return p.errorf(next.col, "expected a term after %s, found %s", op.text, describe(next))
+}
+
+// startsOperand reports whether a token can begin an operand.
+func startsOperand(kind tokenKind) bool {
+	switch kind {
+	case tokWord, tokPhrase, tokField, tokLParen, tokNot:
+		return true
+	}
+	return false
+}
+
+// describe names a token for error messages.
+func describe(tok token) string {
+	switch tok.kind {
+	case tokAnd, tokOr, tokNot:
+		return tok.text
+	case tokLParen:
+		return "("
+	case tokRParen:
+		return ")"
+	case tokEOF:
+		return "end of query"
+	default:
+		return fmt.Sprintf("%q", tok.text)
+	}
+}
+
+func (t *Term) String() string {
+	if t.Phrase {
+		return fmt.Sprintf("%q", t.Text)
+	}
+	return t.Text
+}
+
+func (f *Field) String() string {
+	if strings.ContainsAny(f.Value, " \t") {
+		return fmt.Sprintf("%s:%q", f.Name, f.Value)
+	}
+	return f.Name + ":" + f.Value
+}
+
+func (a *And) String() string { return "(" + joinNodes(a.Nodes, " AND ") + ")" }
+
+func (o *Or) String() string { return "(" + joinNodes(o.Nodes, " OR ") + ")" }
+
+func (n *Not) String() string { return "NOT " + n.Node.String() }
+
+// String renders the query in canonical, fully parenthesized form.
+func (q *Query) String() string {
+	if q.Root == nil {
+		return ""
+	}
+	return q.Root.String()
+}
+
+func joinNodes(nodes []Node, sep string) string {
+	parts := make([]string, len(nodes))
+	for i, n := range nodes {
+		parts[i] = n.String()
+	}
+	return strings.Join(parts, sep)
+}
@@ -0,0 +1,191 @@
+// ABOUTME: Tests for the journal search query language.
+// ABOUTME: Covers parsing to the AST, column-aware syntax errors, and matching entries.
+package query
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/2389-research/pulse/internal/models"
+)
+
+func TestParseAST(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"", ""},
+		{"deploy", "deploy"},
+		{"flaky tests", "(flaky AND tests)"},
+		{`"flaky test" OR deploy`, `("flaky test" OR deploy)`},
+		{"a OR b c", "(a OR (b AND c))"},
+		{"a AND (b OR c)", "(a AND (b OR c))"},
+		{"NOT a -b", "(NOT a AND NOT b)"},
+		{"section:feelings type:project", "(section:feelings AND type:project)"},
+		{`author:"night owl" tag:Incident`, `(author:"night owl" AND tag:Incident)`},
+		{"error: timeout", "(error: AND timeout)"},
+		{"well-known or not", "(well-known AND or AND not)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			q, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse error: %v", err)
+			}
+			if got := q.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrorsPointAtColumn(t *testing.T) {
+	tests := []struct {
+		input  string
+		column int
+		msg    string
+	}{
+		{`deploy "flaky`, 8, "unterminated quoted phrase"},
+		{"a OR", 5, "expected a term after OR"},
+		{"(a OR b", 8, "expected ) to close ("},
+		{"a )", 3, "unexpected )"},
+		{"section:moods", 9, `unknown section "moods"`},
+		{"after:someday", 7, `invalid date "someday"`},
+		{"tag:", 5, "expected a value after tag:"},
+		{"a AND AND b", 7, "expected a term after AND"},
+		{"()", 2, "empty parentheses"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := Parse(tt.input)
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("expected *SyntaxError, got %v", err)
+			}
+			if syntaxErr.Column != tt.column {
+				t.Errorf("Column = %d, want %d (%v)", syntaxErr.Column, tt.column, err)
+			}
+			if !strings.Contains(err.Error(), tt.msg) {
+				t.Errorf("error %q does not mention %q", err, tt.msg)
+			}
+			caret := strings.Repeat(" ", tt.column-1) + "^"
+			if !strings.HasSuffix(err.Error(), caret) {
+				t.Errorf("error does not end with a caret under column %d:\n%s", tt.column, err)
+			}
+		})
+	}
+}
+
+func TestCheckTypes(t *testing.T) {
+	journals := []string{"project", "user", "work"}
+	for _, input := range []string{"deploy", "type:work", "type:user OR NOT type:project"} {
+		q, err := Parse(input)
+		if err != nil {
+			t.Fatalf("Parse(%q) error: %v", input, err)
+		}
+		if err := q.CheckTypes(journals); err != nil {
+			t.Errorf("CheckTypes(%q) error: %v", input, err)
+		}
+	}
+
+	q, err := Parse("deploy OR (tag:ci -type:team)")
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	err = q.CheckTypes(journals)
+	var syntaxErr *SyntaxError
+	if !errors.As(err, &syntaxErr) || syntaxErr.Column != 25 || !strings.Contains(err.Error(), `invalid type "team": must be one of: project, user, work`) {
+		t.Errorf("expected an invalid type error at column 25, got %v", err)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	entry := &models.JournalEntry{
+		Sections: map[string]string{
+			"feelings":      "Frustrated by the flaky   test suite",
+			"project_notes": "Deployment pipeline retries twice",
+		},
+		CreatedAt: time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local),
+		Type:      "project",
+		EntryMeta: models.EntryMeta{Tags: []string{"ci"}, Agent: "night-owl"},
+	}
+
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"anything at all", true}, // plain words rank rather than filter
+		{`"flaky test"`, true},
+		{`"test flaky"`, false},
+		{"deploy AND flaky", true},
+		{"deploy AND rollback", false},
+		{"rollback OR retries", true},
+		{"flaky -deploy", false},
+		{"NOT (rollback OR canary) AND flaky", true},
+		{"section:feelings", true},
+		{"section:world_knowledge", false},
+		{"section:feelings flaky", true},
+		{"section:feelings deploy", false}, // deploy is only in project_notes
+		{"section:feelings -deploy", true},
+		{"section:feelings OR deploy", true},
+		{"(section:feelings OR section:project_notes) deploy", true},
+		{"type:project tag:CI author:Night-Owl", true},
+		{"type:user OR tag:incident", false},
+		{"after:2026-03-09 before:2026-03-11", true},
+		{"after:2026-03-10", false},
+		{"before:2026-03-10", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			q, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse error: %v", err)
+			}
+			if got := q.Match(entry); got != tt.want {
+				t.Errorf("Match = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRankingHelpers(t *testing.T) {
+	q, err := Parse(`"flaky test" deploy -rollback section:feelings`)
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	if !q.Boolean() {
+		t.Error("expected phrase and fields to make the query boolean")
+	}
+	if got := q.Text(); got != "flaky test deploy" {
+		t.Errorf("Text() = %q, want negated terms dropped", got)
+	}
+	if got := q.Sections(); len(got) != 1 || got[0] != "feelings" {
+		t.Errorf("Sections() = %v", got)
+	}
+	if !q.RequiresTerm() {
+		t.Error("expected RequiresTerm for a conjunction with a term")
+	}
+
+	for input, want := range map[string]bool{
+		"tag:ci":           false,
+		"a OR tag:ci":      false,
+		"a OR b":           true,
+		"NOT a":            false,
+		"tag:ci AND flaky": true,
+	} {
+		q, err := Parse(input)
+		if err != nil {
+			t.Fatalf("Parse(%q) error: %v", input, err)
+		}
+		if got := q.RequiresTerm(); got != want {
+			t.Errorf("RequiresTerm(%q) = %v, want %v", input, got, want)
+		}
+	}
+
+	plain, _ := Parse("flaky tests")
+	if plain.Boolean() {
+		t.Error("expected plain words not to be boolean")
+	}
+}
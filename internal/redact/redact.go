@@ -0,0 +1,286 @@
+// ABOUTME: Detection and redaction of secrets and personal data in text written by agents.
+// ABOUTME: Built-in detectors cover AWS keys, JWTs, private keys, emails, and high-entropy tokens; config adds regexes.
+package redact
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/2389-research/pulse/internal/models"
+)
+
+// Mode decides what happens to text a detector matches.
+type Mode string
+
+const (
+	ModeOff   Mode = "off"   // no scanning (default)
+	ModeWarn  Mode = "warn"  // keep the text, report the findings
+	ModeMask  Mode = "mask"  // replace matches with [REDACTED:<detector>]
+	ModeBlock Mode = "block" // refuse the write
+)
+
+// Detector finds one kind of sensitive text.
+type Detector struct {
+	Name    string
+	Pattern *regexp.Regexp
+	Accept  func(match string) bool // optional check that each match is really sensitive
+}
+
+// Pattern is a custom detector declared in config.
+type Pattern struct {
+	Name  string
+	Regex string
+}
+
+// Builtin returns the detectors every redactor starts with. Earlier detectors
+// win where matches overlap.
+func Builtin() []Detector {
+	return []Detector{
+		{Name: "private_key", Pattern: regexp.MustCompile(`(?s)-----BEGIN [A-Z0-9 ]*PRIVATE KEY( BLOCK)?-----.*?(-----END [A-Z0-9 ]*PRIVATE KEY( BLOCK)?-----|\z)`)},
+		{Name: "jwt", Pattern: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{8,}\.eyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}`)},
+		{Name: "aws_access_key", Pattern: regexp.MustCompile(`\b(AKIA|ASIA|ABIA|ACCA)[0-9A-Z]{16}\b`)},
+		{Name: "aws_secret_key", Pattern: regexp.MustCompile(`(?i)\baws_?secret_?(access_?)?key\b["']?\s*[:=]\s*["']?[A-Za-z0-9/+=]{40}`)},
+		{Name: "email", Pattern: regexp.MustCompile(`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}\b`)},
+		// Slashes end a token, so paths and URLs are judged segment by segment
+		{Name: "high_entropy", Pattern: regexp.MustCompile(`[A-Za-z0-9+_=-]{24,}`), Accept: looksRandom},
+	}
+}
+
+// Redactor scans text with a set of detectors and applies its mode. A nil
+// Redactor does nothing, so callers need not check for one.
+type Redactor struct {
+	mode      Mode
+	detectors []Detector
+}
+
+// New returns a redactor in mode (ModeOff if empty) with the built-in
+// detectors followed by the custom patterns. Rewriting or refusing text is
+// opt-in, so an unset mode never changes what the user wrote.
+func New(mode Mode, custom []Pattern) (*Redactor, error) {
+	if mode == "" {
+		mode = ModeOff
+	}
+	switch mode {
+	case ModeOff, ModeWarn, ModeMask, ModeBlock:
+	default:
+		return nil, fmt.Errorf("invalid redaction mode %q: must be one of: off, warn, mask, block", mode)
+	}
+
+	detectors := Builtin()
+	// Custom patterns go before the catch-all entropy check so they name their matches
+	entropy := detectors[len(detectors)-1]
+	detectors = detectors[:len(detectors)-1]
+	for _, p := range custom {
+		if p.Name == "" {
+			return nil, fmt.Errorf("redaction pattern %q needs a name", p.Regex)
+		}
+		re, err := regexp.Compile(p.Regex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %s: %w", p.Name, err)
+		}
+		detectors = append(detectors, Detector{Name: p.Name, Pattern: re})
+	}
+	detectors = append(detectors, entropy)
+	return &Redactor{mode: mode, detectors: detectors}, nil
+}
+
+// Mode returns the redactor's mode; ModeOff for a nil Redactor.
+func (r *Redactor) Mode() Mode {
+	if r == nil {
+		return ModeOff
+	}
+	return r.mode
+}
+
+// BlockedError is returned in block mode when sensitive text was found.
+type BlockedError struct {
+	Findings []models.Redaction
+}
+
+func (e *BlockedError) Error() string {
+	return "refusing to write possible secrets: " + Describe(e.Findings)
+}
+
+// match is one detector hit, as byte offsets into the scanned text.
+type match struct {
+	start, end int
+	detector   string
+}
+
+// scan returns non-overlapping matches in text, ordered by position.
+func (r *Redactor) scan(text string) []match {
+	var all []match
+	for _, d := range r.detectors {
+		for _, loc := range d.Pattern.FindAllStringIndex(text, -1) {
+			if loc[0] == loc[1] || (d.Accept != nil && !d.Accept(text[loc[0]:loc[1]])) {
+				continue
+			}
+			all = append(all, match{start: loc[0], end: loc[1], detector: d.Name})
+		}
+	}
+	// all is in detector priority order; keep each match unless an earlier
+	// detector already claimed part of its text
+	var kept []match
+	for _, m := range all {
+		overlaps := false
+		for _, k := range kept {
+			if m.start < k.end && k.start < m.end {
+				overlaps = true
+				break
+			}
+		}
+		if !overlaps {
+			kept = append(kept, m)
+		}
+	}
+	sort.Slice(kept, func(i, j int) bool { return kept[i].start < kept[j].start })
+	return kept
+}
+
+// Text applies the redactor to one field's text. It returns the text to
+// write, which is masked in mask mode and unchanged otherwise, and what was
+// found. In block mode any finding yields a *BlockedError.
+func (r *Redactor) Text(field, text string) (string, []models.Redaction, error) {
+	out, findings := r.apply(field, text)
+	if r.Mode() == ModeBlock && len(findings) > 0 {
+		return text, findings, &BlockedError{Findings: findings}
+	}
+	return out, findings, nil
+}
+
+// apply scans text and masks it in mask mode.
+func (r *Redactor) apply(field, text string) (string, []models.Redaction) {
+	if r.Mode() == ModeOff {
+		return text, nil
+	}
+	matches := r.scan(text)
+	if len(matches) == 0 {
+		return text, nil
+	}
+
+	var findings []models.Redaction
+	counts := make(map[string]int)
+	for _, m := range matches {
+		if counts[m.detector] == 0 {
+			findings = append(findings, models.Redaction{Field: field, Detector: m.detector, Masked: r.mode == ModeMask})
+		}
+		counts[m.detector]++
+	}
+	for i := range findings {
+		findings[i].Count = counts[findings[i].Detector]
+	}
+
+	if r.mode != ModeMask {
+		return text, findings
+	}
+
+	var sb strings.Builder
+	last := 0
+	for _, m := range matches {
+		sb.WriteString(text[last:m.start])
+		sb.WriteString("[REDACTED:" + m.detector + "]")
+		last = m.end
+	}
+	sb.WriteString(text[last:])
+	return sb.String(), findings
+}
+
+// Sections applies the redactor to every section, returning a new map and
+// the findings across all sections in section name order.
+func (r *Redactor) Sections(sections map[string]string) (map[string]string, []models.Redaction, error) {
+	if r.Mode() == ModeOff {
+		return sections, nil, nil
+	}
+	names := make([]string, 0, len(sections))
+	for name := range sections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	out := make(map[string]string, len(sections))
+	var findings []models.Redaction
+	for _, name := range names {
+		text, found := r.apply(name, sections[name])
+		out[name] = text
+		findings = append(findings, found...)
+	}
+	// Block only after scanning everything, so the error names every finding
+	if r.mode == ModeBlock && len(findings) > 0 {
+		return sections, findings, &BlockedError{Findings: findings}
+	}
+	return out, findings, nil
+}
+
+// Describe summarizes findings as "2 email in feelings, 1 jwt in
+// technical_insights" without revealing the matched text.
+func Describe(findings []models.Redaction) string {
+	parts := make([]string, len(findings))
+	for i, f := range findings {
+		parts[i] = fmt.Sprintf("%d %s in %s", f.Count, f.Detector, f.Field)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Report renders findings as lines for a tool result or command output:
+// one for masked text and a warning for text written unchanged.
+func Report(findings []models.Redaction) []string {
+	var masked, kept []models.Redaction
+	for _, f := range findings {
+		if f.Masked {
+			masked = append(masked, f)
+		} else {
+			kept = append(kept, f)
+		}
+	}
+	var lines []string
+	if len(masked) > 0 {
+		lines = append(lines, "Redacted: "+Describe(masked))
+	}
+	if len(kept) > 0 {
+		lines = append(lines, "Warning: possible secrets written unchanged: "+Describe(kept))
+	}
+	return lines
+}
+
+// looksRandom reports whether a long token looks like a generated secret
+// rather than an identifier, path, or hash: it mixes letters and digits, is
+// not plain hex (commit hashes, UUIDs), and has high per-character entropy.
+func looksRandom(token string) bool {
+	var letters, digits, hex int
+	for _, r := range token {
+		switch {
+		case unicode.IsDigit(r):
+			digits++
+			hex++
+		case unicode.IsLetter(r):
+			letters++
+			if strings.ContainsRune("abcdefABCDEF", r) {
+				hex++
+			}
+		}
+	}
+	if letters == 0 || digits == 0 || hex == letters+digits {
+		return false
+	}
+	return entropy(token) >= 4.0
+}
+
+// entropy returns the Shannon entropy of s in bits per character.
+func entropy(s string) float64 {
+	counts := make(map[rune]int)
+	n := 0
+	for _, r := range s {
+		counts[r]++
+		n++
+	}
+	var h float64
+	for _, c := range counts {
+		p := float64(c) / float64(n)
+		h -= p * math.Log2(p)
+	}
+	return h
+}
@@ -0,0 +1,390 @@
+// ABOUTME: Compressed archives of old date directories, one tar.zst bundle per day in each root's .archive/.
+// ABOUTME: Archived entries keep their original paths and stay readable and searchable, but are read-only.
+package storage
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/harperreed/mdstore"
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/2389-research/pulse/internal/embeddings"
+	"github.com/2389-research/pulse/internal/models"
+)
+
+// archiveDirName is the directory inside each journal root holding archived
+// date directories, one bundle per day: .archive/YYYY-MM-DD.tar.zst. Bundle
+// members are named like the files they replace, e.g. 2025-01-15/<file>.md,
+// so extracting a bundle into the root restores the directory.
+const archiveDirName = ".archive"
+
+// bundleExt is the file extension of archive bundles.
+const bundleExt = ".tar.zst"
+
+// maxCachedBundles bounds how many decompressed bundles a store keeps in
+// memory for reading archived entries.
+const maxCachedBundles = 16
+
+// bundleFile is one member of an archive bundle.
+type bundleFile struct {
+	name    string // slash path relative to the root, e.g. 2025-01-15/<file>.md
+	modTime time.Time
+	data    []byte
+}
+
+// cachedBundle is a decompressed bundle, valid while the bundle file keeps
+// the same size and mtime.
+type cachedBundle struct {
+	size    int64
+	modTime int64
+	files   map[string][]byte // member name -> content
+}
+
+// bundlePath returns the path of root's bundle for the date directory day.
+func bundlePath(root, day string) string {
+	return filepath.Join(root, archiveDirName, day+bundleExt)
+}
+
+// archivedBundle maps the original path of an archived entry to its bundle
+// and member name. It does not check that either exists.
+func archivedBundle(entryPath string) (bundle, member string) {
+	dayDir := filepath.Dir(entryPath)
+	day := filepath.Base(dayDir)
+	return bundlePath(filepath.Dir(dayDir), day), day + "/" + filepath.Base(entryPath)
+}
+
+// listBundles returns the days root has bundles for, oldest first.
+func listBundles(root string) ([]string, error) {
+	files, err := os.ReadDir(filepath.Join(root, archiveDirName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var days []string
+	for _, file := range files {
+		day, ok := strings.CutSuffix(file.Name(), bundleExt)
+		if file.IsDir() || !ok {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", day); err != nil {
+			continue
+		}
+		days = append(days, day)
+	}
+	sort.Strings(days)
+	return days, nil
+}
+
+// readBundle decompresses the bundle file, returning its members in order.
+func readBundle(bundle string) ([]bundleFile, error) {
+	f, err := os.Open(bundle)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	dec, err := zstd.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bundle %s: %w", bundle, err)
+	}
+	defer dec.Close()
+
+	var files []bundleFile
+	tr := tar.NewReader(dec)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read bundle %s: %w", bundle, err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read bundle %s: %w", bundle, err)
+		}
+		files = append(files, bundleFile{name: path.Clean(hdr.Name), modTime: hdr.ModTime, data: data})
+	}
+	return files, nil
+}
+
+// writeBundle writes files, sorted by name, to the bundle file, replacing
+// any existing one atomically.
+func writeBundle(bundle string, files []bundleFile) error {
+	sort.Slice(files, func(i, j int) bool { return files[i].name < files[j].name })
+
+	var buf bytes.Buffer
+	enc, err := zstd.NewWriter(&buf, zstd.WithEncoderLevel(zstd.SpeedBetterCompression))
+	if err != nil {
+		return fmt.Errorf("failed to create bundle: %w", err)
+	}
+	tw := tar.NewWriter(enc)
+	for _, file := range files {
+		hdr := &tar.Header{
+			Name:    file.name,
+			Mode:    0o600,
+			Size:    int64(len(file.data)),
+			ModTime: file.modTime,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return fmt.Errorf("failed to write bundle: %w", err)
+		}
+		if _, err := tw.Write(file.data); err != nil {
+			return fmt.Errorf("failed to write bundle: %w", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	if err := mdstore.AtomicWrite(bundle, buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	return nil
+}
+
+// readEntryData reads the entry file at path or, once it has been archived,
+// its copy in the bundle for its day.
+func (s *JournalMDStore) readEntryData(entryPath string) ([]byte, error) {
+	data, err := os.ReadFile(entryPath)
+	if err == nil || !os.IsNotExist(err) {
+		return data, err
+	}
+	if archived, archErr := s.readArchived(entryPath); archErr == nil {
+		return archived, nil
+	}
+	return nil, err
+}
+
+// readArchived returns the archived copy of the entry at entryPath, reading
+// its bundle through the store's cache.
+func (s *JournalMDStore) readArchived(entryPath string) ([]byte, error) {
+	bundle, member := archivedBundle(entryPath)
+	info, err := os.Stat(bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	s.archiveMu.Lock()
+	defer s.archiveMu.Unlock()
+	cached, ok := s.archives[bundle]
+	if !ok || cached.size != info.Size() || cached.modTime != info.ModTime().UnixNano() {
+		files, err := readBundle(bundle)
+		if err != nil {
+			return nil, err
+		}
+		cached = &cachedBundle{size: info.Size(), modTime: info.ModTime().UnixNano(), files: make(map[string][]byte, len(files))}
+		for _, file := range files {
+			cached.files[file.name] = file.data
+		}
+		if len(s.archives) >= maxCachedBundles {
+			s.archives = make(map[string]*cachedBundle)
+		}
+		s.archives[bundle] = cached
+	}
+	data, ok := cached.files[member]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	return data, nil
+}
+
+// resolveArchivedPath locates an archived entry from the path it had before
+// archiving. The returned location's path is the original path.
+func (s *JournalMDStore) resolveArchivedPath(entryPath string) (entryLocation, error) {
+	absPath, err := filepath.Abs(entryPath)
+	if err != nil {
+		return entryLocation{}, fmt.Errorf("invalid path: %w", err)
+	}
+	for _, root := range s.allRoots() {
+		for _, base := range []string{filepath.Clean(root), resolveRoot(root)} {
+			if absBase, err := filepath.Abs(base); err == nil {
+				base = absBase
+			}
+			if !strings.HasPrefix(absPath, base+string(filepath.Separator)) {
+				continue
+			}
+			rel, _ := filepath.Rel(base, absPath)
+			parts := strings.Split(filepath.ToSlash(rel), "/")
+			if len(parts) != 2 {
+				continue
+			}
+			if _, err := time.Parse("2006-01-02", parts[0]); err != nil {
+				continue
+			}
+			if _, err := s.readArchived(absPath); err != nil {
+				continue
+			}
+			return entryLocation{path: absPath, root: root, absRoot: base, rel: filepath.ToSlash(rel)}, nil
+		}
+	}
+	return entryLocation{}, fmt.Errorf("no archived entry found for %q", entryPath)
+}
+
+// errArchived reports an attempt to change an archived entry.
+func errArchived(path string) error {
+	return fmt.Errorf("entry %q is archived and read-only", path)
+}
+
+// archiveDay moves every entry in root's date directory day into the day's
+// bundle, merging with any bundle already there, and removes the directory.
+// Sidecars are dropped; archived entries are found by keywords only.
+// Returns the number of entries archived.
+func (s *JournalMDStore) archiveDay(root, day string, dryRun bool) (int, error) {
+	dir := filepath.Join(root, day)
+	archived := 0
+	err := mdstore.WithLock(dir, func() error {
+		names, err := os.ReadDir(dir)
+		if err != nil {
+			return err
+		}
+		var files []bundleFile
+		for _, name := range names {
+			if name.IsDir() || !strings.HasSuffix(name.Name(), ".md") {
+				continue
+			}
+			info, err := name.Info()
+			if err != nil {
+				return err
+			}
+			data, err := os.ReadFile(filepath.Join(dir, name.Name()))
+			if err != nil {
+				return fmt.Errorf("failed to read entry: %w", err)
+			}
+			files = append(files, bundleFile{name: day + "/" + name.Name(), modTime: info.ModTime(), data: data})
+		}
+		archived = len(files)
+		if dryRun || len(files) == 0 {
+			return nil
+		}
+
+		return mdstore.WithLock(filepath.Join(root, archiveDirName), func() error {
+			bundle := bundlePath(root, day)
+			existing, err := readBundle(bundle)
+			if err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			// Live files win over older archived copies of the same entry
+			live := make(map[string]bool, len(files))
+			for _, file := range files {
+				live[file.name] = true
+			}
+			for _, file := range existing {
+				if !live[file.name] {
+					files = append(files, file)
+				}
+			}
+			if err := writeBundle(bundle, files); err != nil {
+				return err
+			}
+
+			for _, file := range files {
+				if !live[file.name] {
+					continue
+				}
+				entryPath := filepath.Join(root, filepath.FromSlash(file.name))
+				if err := os.Remove(entryPath); err != nil && !os.IsNotExist(err) {
+					return fmt.Errorf("failed to remove archived entry: %w", err)
+				}
+				if err := os.Remove(embeddings.EmbeddingPath(entryPath)); err != nil && !os.IsNotExist(err) {
+					return fmt.Errorf("failed to remove embedding: %w", err)
+				}
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to archive %s: %w", dir, err)
+	}
+	if !dryRun && archived > 0 {
+		// Only the lock file should be left; Remove fails if anything else is
+		_ = os.Remove(filepath.Join(dir, ".lock"))
+		_ = os.Remove(dir)
+	}
+	return archived, nil
+}
+
+// bundleEdit is what a rewriteBundle callback decides for one entry.
+type bundleEdit int
+
+const (
+	bundleKeep   bundleEdit = iota // keep the entry as it is
+	bundleUpdate                   // re-render the modified entry
+	bundleDrop                     // remove the entry from the bundle
+)
+
+// rewriteBundle applies fn to every entry in root's bundle for day, opening
+// encrypted bodies when the store has a passphrase, and writes the bundle
+// back if fn changed anything. Entries fn keeps are written back byte for
+// byte. The bundle is removed once no entry is left. Returns how many entries
+// fn updated and dropped.
+func (s *JournalMDStore) rewriteBundle(root, day string, dryRun bool, fn func(*models.JournalEntry) (bundleEdit, error)) (updated, dropped int, err error) {
+	bundle := bundlePath(root, day)
+	err = mdstore.WithLock(filepath.Join(root, archiveDirName), func() error {
+		files, err := readBundle(bundle)
+		if err != nil {
+			return err
+		}
+		kept := files[:0]
+		for _, file := range files {
+			if !strings.HasSuffix(file.name, ".md") {
+				kept = append(kept, file)
+				continue
+			}
+			entryPath := filepath.Join(root, filepath.FromSlash(file.name))
+			entry, err := parseJournalEntry(entryPath, string(file.data), s.cipher)
+			if err != nil {
+				kept = append(kept, file)
+				continue
+			}
+			edit, err := fn(entry)
+			if err != nil {
+				return fmt.Errorf("failed to rewrite %s: %w", entryPath, err)
+			}
+			switch edit {
+			case bundleDrop:
+				dropped++
+				continue
+			case bundleUpdate:
+				content, err := s.renderEntry(root, entry)
+				if err != nil {
+					return fmt.Errorf("failed to rewrite %s: %w", entryPath, err)
+				}
+				file.data = []byte(content)
+				updated++
+			}
+			kept = append(kept, file)
+		}
+
+		if dryRun || updated+dropped == 0 {
+			return nil
+		}
+		if len(kept) == 0 {
+			if err := os.Remove(bundle); err != nil {
+				return fmt.Errorf("failed to remove bundle: %w", err)
+			}
+			return nil
+		}
+		return writeBundle(bundle, kept)
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to rewrite bundle %s: %w", bundle, err)
+	}
+	return updated, dropped, nil
+}
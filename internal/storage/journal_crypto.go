@@ -0,0 +1,308 @@
+// ABOUTME: Optional encryption at rest for journal entry bodies using AES-256-GCM.
+// ABOUTME: Keys derive from a passphrase with PBKDF2; frontmatter stays readable so listing and filters work.
+package storage
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/harperreed/mdstore"
+
+	"github.com/2389-research/pulse/internal/models"
+)
+
+// Algorithms recorded in the frontmatter of encrypted entries.
+const (
+	encryptionCipher = "aes-256-gcm"
+	encryptionKDF    = "pbkdf2-sha256"
+)
+
+// saltFileName holds the base64 salt that new encrypted entries in a root use,
+// so a root needs one key derivation however many entries it has. Each entry
+// also records its salt, so entries stay readable if moved between roots.
+const saltFileName = "_salt"
+
+// pbkdf2Iterations is the work factor for new encrypted entries. It is a
+// variable so tests can lower it.
+var pbkdf2Iterations = 600000
+
+// encryptionHeader is the frontmatter describing how an entry body was
+// encrypted. The nonce is stored in front of the ciphertext.
+type encryptionHeader struct {
+	Cipher     string `yaml:"cipher"`
+	KDF        string `yaml:"kdf"`
+	Iterations int    `yaml:"iterations"`
+	Salt       string `yaml:"salt"`
+}
+
+// EncryptionReport summarizes an EncryptAll or DecryptAll migration.
+type EncryptionReport struct {
+	Scanned int // entries examined, including the trash
+	Changed int // entries rewritten
+}
+
+// journalCipher seals and opens entry bodies with keys derived from one
+// passphrase. Derived keys are cached by salt and work factor.
+type journalCipher struct {
+	passphrase string
+
+	mu    sync.Mutex
+	aeads map[string]cipher.AEAD // "<iterations>:<salt>" -> AEAD
+	salts map[string][]byte      // root -> salt for new entries
+}
+
+// newJournalCipher returns a cipher for passphrase, or nil for an empty one.
+func newJournalCipher(passphrase string) *journalCipher {
+	if passphrase == "" {
+		return nil
+	}
+	return &journalCipher{
+		passphrase: passphrase,
+		aeads:      make(map[string]cipher.AEAD),
+		salts:      make(map[string][]byte),
+	}
+}
+
+// errLocked reports an encrypted entry the store cannot open.
+func errLocked(path string) error {
+	return fmt.Errorf("entry %s is encrypted and no journal passphrase is configured", path)
+}
+
+// aead returns the AEAD for the key that h describes.
+func (c *journalCipher) aead(h *encryptionHeader) (cipher.AEAD, error) {
+	if h.Cipher != encryptionCipher || h.KDF != encryptionKDF {
+		return nil, fmt.Errorf("unsupported encryption %s/%s", h.Cipher, h.KDF)
+	}
+	if h.Iterations <= 0 {
+		return nil, fmt.Errorf("invalid key derivation iterations %d", h.Iterations)
+	}
+	salt, err := base64.StdEncoding.DecodeString(h.Salt)
+	if err != nil || len(salt) == 0 {
+		return nil, fmt.Errorf("invalid encryption salt")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cacheKey := fmt.Sprintf("%d:%s", h.Iterations, h.Salt)
+	if aead, ok := c.aeads[cacheKey]; ok {
+		return aead, nil
+	}
+	key, err := pbkdf2.Key(sha256.New, c.passphrase, salt, h.Iterations, 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	c.aeads[cacheKey] = aead
+	return aead, nil
+}
+
+// rootSalt returns the salt for new entries in root, creating the root's salt
+// file on first use.
+func (c *journalCipher) rootSalt(root string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if salt, ok := c.salts[root]; ok {
+		return salt, nil
+	}
+
+	path := filepath.Join(root, saltFileName)
+	if data, err := os.ReadFile(path); err == nil {
+		if salt, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data))); err == nil && len(salt) > 0 {
+			c.salts[root] = salt
+			return salt, nil
+		}
+		return nil, fmt.Errorf("invalid salt file %s", path)
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read salt: %w", err)
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+	if err := mdstore.AtomicWrite(path, []byte(base64.StdEncoding.EncodeToString(salt)+"\n")); err != nil {
+		return nil, fmt.Errorf("failed to write salt: %w", err)
+	}
+	c.salts[root] = salt
+	return salt, nil
+}
+
+// seal encrypts an entry body for storage under root. The entry ID is bound
+// as additional data, so a body cannot be swapped into another entry.
+func (c *journalCipher) seal(root, id, body string) (*encryptionHeader, string, error) {
+	salt, err := c.rootSalt(root)
+	if err != nil {
+		return nil, "", err
+	}
+	h := &encryptionHeader{
+		Cipher:     encryptionCipher,
+		KDF:        encryptionKDF,
+		Iterations: pbkdf2Iterations,
+		Salt:       base64.StdEncoding.EncodeToString(salt),
+	}
+	aead, err := c.aead(h)
+	if err != nil {
+		return nil, "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(body), []byte(id))
+	return h, wrapLines(base64.StdEncoding.EncodeToString(sealed), 76), nil
+}
+
+// open decrypts a body sealed for the entry with the given ID.
+func (c *journalCipher) open(h *encryptionHeader, id, body string) (string, error) {
+	aead, err := c.aead(h)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(body), ""))
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", fmt.Errorf("malformed encrypted body")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plain, err := aead.Open(nil, nonce, ciphertext, []byte(id))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt entry: wrong passphrase or corrupted file")
+	}
+	return string(plain), nil
+}
+
+// wrapLines breaks s into lines of at most width characters.
+func wrapLines(s string, width int) string {
+	var sb strings.Builder
+	for len(s) > width {
+		sb.WriteString(s[:width])
+		sb.WriteString("\n")
+		s = s[width:]
+	}
+	sb.WriteString(s)
+	return sb.String()
+}
+
+// renderEntry renders entry for storage under root, encrypting its body when
+// the entry is marked Encrypted.
+func (s *JournalMDStore) renderEntry(root string, entry *models.JournalEntry) (string, error) {
+	if !entry.Encrypted {
+		return renderJournalEntry(entry)
+	}
+	if s.cipher == nil || entry.Locked {
+		return "", errLocked(entry.FilePath)
+	}
+	header, body, err := s.cipher.seal(root, entry.ID.String(), renderSections(entry.Sections))
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt entry: %w", err)
+	}
+	return renderEntryBody(entry, header, "\n"+body+"\n")
+}
+
+// readEntryFile reads and parses the entry at path, or its archived copy, and
+// decrypts its body. An encrypted entry the store has no passphrase for is an error.
+func (s *JournalMDStore) readEntryFile(path string) (*models.JournalEntry, error) {
+	data, err := s.readEntryData(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read entry: %w", err)
+	}
+	entry, err := parseJournalEntry(path, string(data), s.cipher)
+	if err != nil {
+		return nil, err
+	}
+	if entry.Locked {
+		return nil, errLocked(path)
+	}
+	return entry, nil
+}
+
+// EncryptAll encrypts the body of every entry in every root, including the
+// trash and archives, that is not encrypted yet. Embedding sidecars of
+// encrypted entries are removed, since they hold the entry's text.
+func (s *JournalMDStore) EncryptAll() (*EncryptionReport, error) {
+	return s.migrateEncryption(true)
+}
+
+// DecryptAll rewrites every encrypted entry in every root, including the
+// trash and archives, with a plaintext body, and regenerates sidecars if an
+// embedder is set. New entries are still encrypted while encryption stays
+// enabled.
+func (s *JournalMDStore) DecryptAll() (*EncryptionReport, error) {
+	return s.migrateEncryption(false)
+}
+
+// migrateEncryption rewrites every entry whose encryption differs from
+// encrypt. It stops at the first entry it cannot rewrite; entries already
+// migrated stay migrated, so running it again resumes.
+func (s *JournalMDStore) migrateEncryption(encrypt bool) (*EncryptionReport, error) {
+	if s.cipher == nil {
+		return nil, fmt.Errorf("no journal passphrase is configured")
+	}
+
+	report := &EncryptionReport{}
+	for _, root := range s.allRoots() {
+		for _, dir := range []string{root, filepath.Join(root, trashDirName)} {
+			entries, err := listEntriesInRoot(dir, nil)
+			if err != nil {
+				return report, fmt.Errorf("failed to list entries in %s: %w", dir, err)
+			}
+			for _, listed := range entries {
+				report.Scanned++
+				if listed.Encrypted == encrypt {
+					continue
+				}
+				err := mdstore.WithLock(filepath.Dir(listed.FilePath), func() error {
+					entry, err := s.readEntryFile(listed.FilePath)
+					if err != nil {
+						return err
+					}
+					entry.Encrypted = encrypt
+					return s.writeEntryFile(root, entry.FilePath, entry)
+				})
+				if err != nil {
+					return report, fmt.Errorf("failed to migrate %s: %w", listed.FilePath, err)
+				}
+				report.Changed++
+			}
+		}
+
+		days, err := listBundles(root)
+		if err != nil {
+			return report, fmt.Errorf("failed to list archives in %s: %w", root, err)
+		}
+		for _, day := range days {
+			updated, _, err := s.rewriteBundle(root, day, false, func(entry *models.JournalEntry) (bundleEdit, error) {
+				report.Scanned++
+				if entry.Encrypted == encrypt {
+					return bundleKeep, nil
+				}
+				entry.Encrypted = encrypt
+				return bundleUpdate, nil
+			})
+			if err != nil {
+				return report, err
+			}
+			report.Changed += updated
+		}
+
+		if err := s.withIndex(root, nil); err != nil {
+			return report, fmt.Errorf("failed to update index: %w", err)
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,182 @@
+// ABOUTME: Tests for encryption at rest of journal entry bodies.
+// ABOUTME: Covers transparent reads and search, locked listing, wrong passphrases, and encrypt-all/decrypt-all.
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/2389-research/pulse/internal/embeddings"
+	"github.com/2389-research/pulse/internal/models"
+)
+
+// newEncryptedStore opens a store over dir with the given passphrase and
+// encryption setting, using a cheap key derivation to keep tests fast.
+func newEncryptedStore(t *testing.T, dir, passphrase string, encrypt bool) *JournalMDStore {
+	t.Helper()
+	iterations := pbkdf2Iterations
+	pbkdf2Iterations = 1000
+	t.Cleanup(func() { pbkdf2Iterations = iterations })
+
+	store, err := NewJournalMDStore(filepath.Join(dir, "project"), filepath.Join(dir, "user"),
+		WithEmbedder(embeddings.NewHashEmbedder(embeddings.DefaultDimension)),
+		WithPassphrase(passphrase), WithEncryption(encrypt), WithGitContext(false))
+	if err != nil {
+		t.Fatalf("NewJournalMDStore error: %v", err)
+	}
+	return store
+}
+
+func TestEncryptedEntryRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	store := newEncryptedStore(t, dir, "correct horse", true)
+
+	entry := models.NewJournalEntry(map[string]string{"feelings": "quietly proud of the flaky test fix"}, "user")
+	entry.Tags = []string{"testing"}
+	if err := store.WriteEntry(entry); err != nil {
+		t.Fatalf("WriteEntry error: %v", err)
+	}
+
+	data, _ := os.ReadFile(entry.FilePath)
+	if strings.Contains(string(data), "flaky") || strings.Contains(string(data), "## Feelings") {
+		t.Errorf("expected body to be encrypted on disk, got:\n%s", data)
+	}
+	if !strings.Contains(string(data), "cipher: aes-256-gcm") || !strings.Contains(string(data), "- testing") {
+		t.Errorf("expected readable frontmatter, got:\n%s", data)
+	}
+	if _, err := os.Stat(embeddings.EmbeddingPath(entry.FilePath)); !os.IsNotExist(err) {
+		t.Errorf("expected no sidecar for an encrypted entry, stat err = %v", err)
+	}
+	index, _ := os.ReadFile(filepath.Join(dir, "user", indexFileName))
+	if strings.Contains(string(index), "flaky") {
+		t.Error("expected the index not to hold words from an encrypted body")
+	}
+
+	read, err := store.ReadEntry(entry.FilePath)
+	if err != nil {
+		t.Fatalf("ReadEntry error: %v", err)
+	}
+	if read.Sections["feelings"] != "quietly proud of the flaky test fix" || !read.Encrypted {
+		t.Errorf("expected decrypted entry, got %+v", read)
+	}
+
+	results, err := store.Search("flaky", embeddings.SearchOptions{})
+	if err != nil {
+		t.Fatalf("Search error: %v", err)
+	}
+	if len(results) != 1 || results[0].Entry.ID != entry.ID {
+		t.Errorf("expected search to find the encrypted entry, got %+v", results)
+	}
+
+	// Edits keep the entry encrypted
+	updated, err := store.AppendToSection(entry.FilePath, "feelings", "and relieved")
+	if err != nil {
+		t.Fatalf("AppendToSection error: %v", err)
+	}
+	data, _ = os.ReadFile(updated.FilePath)
+	if strings.Contains(string(data), "relieved") {
+		t.Errorf("expected edited body to stay encrypted, got:\n%s", data)
+	}
+}
+
+func TestEncryptedEntryWithoutPassphrase(t *testing.T) {
+	dir := t.TempDir()
+	entry := models.NewJournalEntry(map[string]string{"feelings": "secret feelings"}, "user")
+	entry.Mood = "calm"
+	if err := newEncryptedStore(t, dir, "correct horse", true).WriteEntry(entry); err != nil {
+		t.Fatalf("WriteEntry error: %v", err)
+	}
+
+	locked := newEncryptedStore(t, dir, "", false)
+	entries, err := locked.ListEntries(ListOptions{Filter: models.MetaFilter{Mood: "calm"}})
+	if err != nil {
+		t.Fatalf("ListEntries error: %v", err)
+	}
+	if len(entries) != 1 || !entries[0].Locked || len(entries[0].Sections) != 0 {
+		t.Fatalf("expected one locked entry from the frontmatter, got %+v", entries)
+	}
+	if _, err := locked.ReadEntry(entry.FilePath); err == nil || !strings.Contains(err.Error(), "encrypted") {
+		t.Errorf("expected reading without a passphrase to fail, got %v", err)
+	}
+	if _, err := locked.UpdateEntry(entry.FilePath, map[string]string{"feelings": "overwritten"}); err == nil {
+		t.Error("expected editing without a passphrase to fail")
+	}
+	if _, err := locked.DeleteEntry(entry.FilePath, ""); err == nil {
+		t.Error("expected deleting without a passphrase to fail")
+	}
+
+	wrong := newEncryptedStore(t, dir, "battery staple", false)
+	if _, err := wrong.ReadEntry(entry.FilePath); err == nil || !strings.Contains(err.Error(), "wrong passphrase") {
+		t.Errorf("expected a wrong passphrase to fail, got %v", err)
+	}
+}
+
+func TestEncryptionRequiresPassphrase(t *testing.T) {
+	dir := t.TempDir()
+	_, err := NewJournalMDStore(filepath.Join(dir, "project"), filepath.Join(dir, "user"), WithEncryption(true))
+	if err == nil {
+		t.Error("expected encryption without a passphrase to fail")
+	}
+}
+
+func TestEncryptAllAndDecryptAll(t *testing.T) {
+	dir := t.TempDir()
+	plain := newEncryptedStore(t, dir, "correct horse", false)
+
+	kept := models.NewJournalEntry(map[string]string{"feelings": "kept around"}, "user")
+	trashed := models.NewJournalEntry(map[string]string{"technical_insights": "deleted insight"}, "project")
+	for _, entry := range []*models.JournalEntry{kept, trashed} {
+		if err := plain.WriteEntry(entry); err != nil {
+			t.Fatalf("WriteEntry error: %v", err)
+		}
+	}
+	trashedEntry, err := plain.DeleteEntry(trashed.FilePath, "")
+	if err != nil {
+		t.Fatalf("DeleteEntry error: %v", err)
+	}
+
+	report, err := plain.EncryptAll()
+	if err != nil {
+		t.Fatalf("EncryptAll error: %v", err)
+	}
+	if report.Scanned != 2 || report.Changed != 2 {
+		t.Errorf("expected 2 entries encrypted, got %+v", report)
+	}
+	for _, path := range []string{kept.FilePath, trashedEntry.FilePath} {
+		data, _ := os.ReadFile(path)
+		if strings.Contains(string(data), "kept around") || strings.Contains(string(data), "deleted insight") {
+			t.Errorf("expected %s to be encrypted, got:\n%s", path, data)
+		}
+		if _, err := os.Stat(embeddings.EmbeddingPath(path)); !os.IsNotExist(err) {
+			t.Errorf("expected the sidecar of %s to be removed", path)
+		}
+	}
+	if results, _ := plain.Search("kept", embeddings.SearchOptions{}); len(results) != 1 {
+		t.Errorf("expected search to still find the encrypted entry, got %d results", len(results))
+	}
+
+	report, err = plain.EncryptAll()
+	if err != nil || report.Changed != 0 {
+		t.Errorf("expected a second EncryptAll to change nothing, got %+v, %v", report, err)
+	}
+
+	report, err = plain.DecryptAll()
+	if err != nil {
+		t.Fatalf("DecryptAll error: %v", err)
+	}
+	if report.Changed != 2 {
+		t.Errorf("expected 2 entries decrypted, got %+v", report)
+	}
+	data, _ := os.ReadFile(kept.FilePath)
+	if !strings.Contains(string(data), "kept around") || strings.Contains(string(data), "encryption:") {
+		t.Errorf("expected a plaintext body, got:\n%s", data)
+	}
+	if _, err := os.Stat(embeddings.EmbeddingPath(kept.FilePath)); err != nil {
+		t.Errorf("expected the sidecar to be regenerated: %v", err)
+	}
+	if _, err := plain.RestoreEntry(trashedEntry.FilePath); err != nil {
+		t.Errorf("RestoreEntry error: %v", err)
+	}
+}
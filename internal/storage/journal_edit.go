@@ -0,0 +1,116 @@
+// ABOUTME: In-place editing of journal entries: section updates and appends.
+// ABOUTME: Keeps the frontmatter ID, stamps updated_at, and syncs embedding sidecars and the index.
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/harperreed/mdstore"
+
+	"github.com/2389-research/pulse/internal/embeddings"
+	"github.com/2389-research/pulse/internal/models"
+)
+
+// UpdateEntry replaces the given sections of the entry at path. An empty
+// value removes that section; sections not mentioned are left untouched.
+// The entry keeps its ID, creation date, and type, and gains an updated_at stamp.
+func (s *JournalMDStore) UpdateEntry(path string, sections map[string]string) (*models.JournalEntry, error) {
+	if len(sections) == 0 {
+		return nil, fmt.Errorf("at least one section is required")
+	}
+	for name := range sections {
+		if !models.IsValidSection(name) {
+			return nil, fmt.Errorf("invalid section %q", name)
+		}
+	}
+
+	return s.modifyEntry(path, func(entry *models.JournalEntry) error {
+		for name, content := range sections {
+			if strings.TrimSpace(content) == "" {
+				delete(entry.Sections, name)
+				continue
+			}
+			entry.Sections[name] = content
+		}
+		if len(entry.Sections) == 0 {
+			return fmt.Errorf("update would leave the entry without any sections")
+		}
+		return nil
+	})
+}
+
+// AppendToSection appends text to a section of the entry at path, separated
+// from existing content by a blank line. Missing sections are created.
+func (s *JournalMDStore) AppendToSection(path, section, text string) (*models.JournalEntry, error) {
+	if !models.IsValidSection(section) {
+		return nil, fmt.Errorf("invalid section %q", section)
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, fmt.Errorf("text to append is empty")
+	}
+
+	return s.modifyEntry(path, func(entry *models.JournalEntry) error {
+		if existing := strings.TrimSpace(entry.Sections[section]); existing != "" {
+			entry.Sections[section] = existing + "\n\n" + text
+		} else {
+			entry.Sections[section] = text
+		}
+		return nil
+	})
+}
+
+// modifyEntry reads the entry at path under its directory lock, applies fn,
+// and writes it back in place with a fresh updated_at stamp.
+func (s *JournalMDStore) modifyEntry(path string, fn func(*models.JournalEntry) error) (*models.JournalEntry, error) {
+	loc, err := s.resolveEntryPath(path)
+	if err != nil {
+		if _, archErr := s.resolveArchivedPath(path); archErr == nil {
+			return nil, errArchived(path)
+		}
+		return nil, err
+	}
+	if isTrashed(loc.rel) {
+		return nil, fmt.Errorf("entry %q is in the trash; restore it before editing", path)
+	}
+	absPath := loc.path
+
+	var entry *models.JournalEntry
+	err = mdstore.WithLock(filepath.Dir(absPath), func() error {
+		entry, err = s.readEntryFile(absPath)
+		if err != nil {
+			return err
+		}
+
+		if err := fn(entry); err != nil {
+			return err
+		}
+		if err := s.redactEntry(entry); err != nil {
+			return err
+		}
+		entry.UpdatedAt = time.Now()
+
+		if err := s.writeEntryFile(loc.root, absPath, entry); err != nil {
+			return err
+		}
+		// Without an embedder the old vectors no longer describe the entry
+		if s.embedder == nil {
+			if err := os.Remove(embeddings.EmbeddingPath(absPath)); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to remove stale embedding: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.withIndex(loc.root, nil); err != nil {
+		return nil, fmt.Errorf("failed to update index: %w", err)
+	}
+	return entry, nil
+}
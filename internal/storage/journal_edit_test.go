@@ -0,0 +1,172 @@
+// ABOUTME: Tests for editing journal entries in place.
+// ABOUTME: Covers section updates, appends, deletion, sidecar sync, and root confinement.
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/2389-research/pulse/internal/embeddings"
+	"github.com/2389-research/pulse/internal/models"
+)
+
+func TestUpdateEntryPreservesIdentity(t *testing.T) {
+	tmpDir := t.TempDir()
+	store, _ := NewJournalMDStore(filepath.Join(tmpDir, "project"), filepath.Join(tmpDir, "user"))
+
+	entry := models.NewJournalEntry(map[string]string{
+		"feelings":     "Worried",
+		"user_context": "Likes long meetings",
+	}, "user")
+	if err := store.WriteEntry(entry); err != nil {
+		t.Fatalf("WriteEntry error: %v", err)
+	}
+
+	updated, err := store.UpdateEntry(entry.FilePath, map[string]string{
+		"user_context": "Dislikes long meetings",
+		"feelings":     "",
+	})
+	if err != nil {
+		t.Fatalf("UpdateEntry error: %v", err)
+	}
+	if updated.ID != entry.ID {
+		t.Errorf("ID changed from %s to %s", entry.ID, updated.ID)
+	}
+
+	got, err := store.ReadEntry(entry.FilePath)
+	if err != nil {
+		t.Fatalf("ReadEntry error: %v", err)
+	}
+	if got.ID != entry.ID || got.Type != "user" || got.CreatedAt.Unix() != entry.CreatedAt.Unix() {
+		t.Errorf("identity not preserved: %+v", got)
+	}
+	if got.UpdatedAt.IsZero() {
+		t.Error("expected updated_at to be set")
+	}
+	if got.Sections["user_context"] != "Dislikes long meetings" {
+		t.Errorf("user_context = %q", got.Sections["user_context"])
+	}
+	if _, ok := got.Sections["feelings"]; ok {
+		t.Error("expected empty value to remove the feelings section")
+	}
+
+	data, _ := os.ReadFile(entry.FilePath)
+	if !strings.Contains(string(data), "updated_at:") {
+		t.Errorf("expected updated_at in frontmatter, got:\n%s", data)
+	}
+}
+
+func TestUpdateEntryRejectsEmptyResult(t *testing.T) {
+	tmpDir := t.TempDir()
+	store, _ := NewJournalMDStore(filepath.Join(tmpDir, "project"), filepath.Join(tmpDir, "user"))
+
+	entry := models.NewJournalEntry(map[string]string{"feelings": "only section"}, "user")
+	if err := store.WriteEntry(entry); err != nil {
+		t.Fatalf("WriteEntry error: %v", err)
+	}
+
+	if _, err := store.UpdateEntry(entry.FilePath, map[string]string{"feelings": ""}); err == nil {
+		t.Error("expected error when removing the last section")
+	}
+	if _, err := store.UpdateEntry(entry.FilePath, map[string]string{"bogus": "x"}); err == nil {
+		t.Error("expected error for invalid section")
+	}
+}
+
+func TestAppendToSectionCreatesAndExtends(t *testing.T) {
+	tmpDir := t.TempDir()
+	store, _ := NewJournalMDStore(filepath.Join(tmpDir, "project"), filepath.Join(tmpDir, "user"))
+
+	entry := models.NewJournalEntry(map[string]string{"feelings": "First thought"}, "user")
+	if err := store.WriteEntry(entry); err != nil {
+		t.Fatalf("WriteEntry error: %v", err)
+	}
+
+	if _, err := store.AppendToSection(entry.FilePath, "feelings", "Second thought"); err != nil {
+		t.Fatalf("AppendToSection error: %v", err)
+	}
+	got, err := store.AppendToSection(entry.FilePath, "world_knowledge", "Octopuses have three hearts")
+	if err != nil {
+		t.Fatalf("AppendToSection error: %v", err)
+	}
+
+	if got.Sections["feelings"] != "First thought\n\nSecond thought" {
+		t.Errorf("feelings = %q", got.Sections["feelings"])
+	}
+	if got.Sections["world_knowledge"] != "Octopuses have three hearts" {
+		t.Errorf("world_knowledge = %q", got.Sections["world_knowledge"])
+	}
+}
+
+func TestEditKeepsSidecarAndIndexInSync(t *testing.T) {
+	tmpDir := t.TempDir()
+	embedder := embeddings.NewHashEmbedder(embeddings.DefaultDimension)
+	store, _ := NewJournalMDStore(filepath.Join(tmpDir, "project"), filepath.Join(tmpDir, "user"), WithEmbedder(embedder))
+
+	entry := models.NewJournalEntry(map[string]string{"feelings": "calm morning"}, "user")
+	if err := store.WriteEntry(entry); err != nil {
+		t.Fatalf("WriteEntry error: %v", err)
+	}
+
+	if _, err := store.AppendToSection(entry.FilePath, "technical_insights", "the scheduler starves low priority jobs"); err != nil {
+		t.Fatalf("AppendToSection error: %v", err)
+	}
+
+	emb, err := embeddings.ReadEmbedding(entry.FilePath)
+	if err != nil {
+		t.Fatalf("ReadEmbedding error: %v", err)
+	}
+	if _, ok := emb.SectionVectors["technical_insights"]; !ok {
+		t.Errorf("expected sidecar to cover the appended section, got %v", emb.Sections)
+	}
+
+	results, err := store.Search("scheduler", embeddings.SearchOptions{})
+	if err != nil {
+		t.Fatalf("Search error: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected appended text to be searchable, got %d results", len(results))
+	}
+
+	if _, err := store.DeleteEntry(entry.FilePath, ""); err != nil {
+		t.Fatalf("DeleteEntry error: %v", err)
+	}
+	if _, err := os.Stat(embeddings.EmbeddingPath(entry.FilePath)); !os.IsNotExist(err) {
+		t.Errorf("expected sidecar to be removed, stat err = %v", err)
+	}
+	entries, err := store.ListEntries(ListOptions{})
+	if err != nil {
+		t.Fatalf("ListEntries error: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected no entries after delete, got %d", len(entries))
+	}
+}
+
+func TestEditRejectsPathsOutsideRoots(t *testing.T) {
+	tmpDir := t.TempDir()
+	store, _ := NewJournalMDStore(filepath.Join(tmpDir, "project"), filepath.Join(tmpDir, "user"))
+
+	outside := filepath.Join(tmpDir, "outside.md")
+	content := "---\nid: 00000000-0000-0000-0000-000000000000\ndate: 2026-01-01T00:00:00Z\ntype: user\n---\n\n## Feelings\n\nnot yours\n"
+	if err := os.WriteFile(outside, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	if _, err := store.UpdateEntry(outside, map[string]string{"feelings": "mine now"}); err == nil {
+		t.Error("expected UpdateEntry to reject a path outside roots")
+	}
+	if _, err := store.AppendToSection(outside, "feelings", "more"); err == nil {
+		t.Error("expected AppendToSection to reject a path outside roots")
+	}
+	if _, err := store.DeleteEntry(outside, ""); err == nil {
+		t.Error("expected DeleteEntry to reject a path outside roots")
+	}
+
+	data, _ := os.ReadFile(outside)
+	if string(data) != content {
+		t.Error("file outside roots was modified")
+	}
+}
@@ -0,0 +1,129 @@
+// ABOUTME: Backfill and refresh of .embedding sidecars for existing journal entries.
+// ABOUTME: Detects missing or outdated sidecars and regenerates them with bounded parallelism.
+package storage
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"sync"
+
+	"github.com/2389-research/pulse/internal/embeddings"
+	"github.com/2389-research/pulse/internal/models"
+)
+
+// BackfillEmbeddings walks both roots and regenerates missing or outdated
+// .embedding sidecars with the store's embedder.
+func (s *JournalMDStore) BackfillEmbeddings(opts EmbedOptions) (*EmbedReport, error) {
+	if s.embedder == nil {
+		return nil, fmt.Errorf("no embedder configured")
+	}
+
+	report := &EmbedReport{}
+	var stale []*models.JournalEntry
+
+	for _, root := range s.allRoots() {
+		entries, err := listEntriesInRoot(root, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list entries in %s: %w", root, err)
+		}
+		for _, entry := range entries {
+			// Sidecars hold the entry's text, so encrypted entries get none
+			if entry.Encrypted {
+				continue
+			}
+			report.Scanned++
+			switch s.sidecarState(entry.FilePath) {
+			case sidecarMissing:
+				report.Missing++
+			case sidecarOutdated:
+				report.Outdated++
+			default:
+				if !opts.Force {
+					continue
+				}
+			}
+			stale = append(stale, entry)
+		}
+	}
+
+	if opts.DryRun || len(stale) == 0 {
+		return report, nil
+	}
+
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	jobs := make(chan *models.JournalEntry)
+	done := 0
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for entry := range jobs {
+				err := embeddings.WriteEmbedding(entry.FilePath, s.embedder, entry.Sections)
+
+				mu.Lock()
+				if err != nil {
+					report.Failed++
+				} else {
+					report.Written++
+				}
+				done++
+				if opts.Progress != nil {
+					opts.Progress(done, len(stale), entry.FilePath)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, entry := range stale {
+		jobs <- entry
+	}
+	close(jobs)
+	wg.Wait()
+
+	return report, nil
+}
+
+// sidecarStatus classifies an entry's sidecar.
+type sidecarStatus int
+
+const (
+	sidecarCurrent sidecarStatus = iota
+	sidecarMissing
+	sidecarOutdated
+)
+
+// sidecarState reports whether the sidecar for mdPath exists and matches the
+// store's embedder and the entry's current content.
+func (s *JournalMDStore) sidecarState(mdPath string) sidecarStatus {
+	embInfo, err := os.Stat(embeddings.EmbeddingPath(mdPath))
+	if os.IsNotExist(err) {
+		return sidecarMissing
+	}
+	if err != nil {
+		return sidecarOutdated
+	}
+
+	emb, err := embeddings.ReadEmbedding(mdPath)
+	if err != nil || emb.Model != s.embedder.Name() || !embeddings.Compatible(emb, s.embedder) {
+		return sidecarOutdated
+	}
+	// Sidecars from before per-section vectors only carry the whole-entry vector
+	if len(emb.SectionVectors) == 0 && len(emb.Sections) > 0 {
+		return sidecarOutdated
+	}
+
+	mdInfo, err := os.Stat(mdPath)
+	if err == nil && mdInfo.ModTime().After(embInfo.ModTime()) {
+		return sidecarOutdated
+	}
+	return sidecarCurrent
+}
@@ -0,0 +1,121 @@
+// ABOUTME: Tests for embedding sidecar backfill.
+// ABOUTME: Covers missing and outdated detection, dry runs, and forced regeneration.
+package storage
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/2389-research/pulse/internal/embeddings"
+	"github.com/2389-research/pulse/internal/models"
+)
+
+func TestBackfillEmbeddingsWritesMissingSidecars(t *testing.T) {
+	tmpDir := t.TempDir()
+	projectDir := filepath.Join(tmpDir, "project")
+	userDir := filepath.Join(tmpDir, "user")
+
+	// Entries written without an embedder have no sidecars
+	plain, _ := NewJournalMDStore(projectDir, userDir)
+	var paths []string
+	for _, typ := range []string{"user", "project", "user"} {
+		entry := models.NewJournalEntry(map[string]string{"feelings": "backfill me"}, typ)
+		if err := plain.WriteEntry(entry); err != nil {
+			t.Fatalf("WriteEntry error: %v", err)
+		}
+		paths = append(paths, entry.FilePath)
+	}
+
+	store, _ := NewJournalMDStore(projectDir, userDir, WithEmbedder(embeddings.NewHashEmbedder(32)))
+
+	report, err := store.BackfillEmbeddings(EmbedOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("BackfillEmbeddings dry run error: %v", err)
+	}
+	if report.Scanned != 3 || report.Missing != 3 || report.Written != 0 {
+		t.Errorf("unexpected dry-run report: %+v", report)
+	}
+	if _, err := os.Stat(embeddings.EmbeddingPath(paths[0])); !os.IsNotExist(err) {
+		t.Error("dry run should not write sidecars")
+	}
+
+	progressCalls := 0
+	report, err = store.BackfillEmbeddings(EmbedOptions{
+		Workers:  2,
+		Progress: func(done, total int, path string) { progressCalls++ },
+	})
+	if err != nil {
+		t.Fatalf("BackfillEmbeddings error: %v", err)
+	}
+	if report.Written != 3 || report.Failed != 0 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+	if progressCalls != 3 {
+		t.Errorf("expected 3 progress calls, got %d", progressCalls)
+	}
+	for _, p := range paths {
+		emb, err := embeddings.ReadEmbedding(p)
+		if err != nil {
+			t.Fatalf("expected sidecar for %s: %v", p, err)
+		}
+		if emb.Model != "hash-ngram-v1" || emb.Dimension != 32 {
+			t.Errorf("expected model and dimension recorded, got %q/%d", emb.Model, emb.Dimension)
+		}
+	}
+
+	// Second run finds nothing to do
+	report, err = store.BackfillEmbeddings(EmbedOptions{})
+	if err != nil {
+		t.Fatalf("BackfillEmbeddings error: %v", err)
+	}
+	if report.Missing != 0 || report.Outdated != 0 || report.Written != 0 {
+		t.Errorf("expected no work on second run, got %+v", report)
+	}
+
+	// Force regenerates everything
+	report, err = store.BackfillEmbeddings(EmbedOptions{Force: true})
+	if err != nil {
+		t.Fatalf("BackfillEmbeddings error: %v", err)
+	}
+	if report.Written != 3 {
+		t.Errorf("expected 3 forced writes, got %+v", report)
+	}
+}
+
+func TestBackfillEmbeddingsReplacesOtherModels(t *testing.T) {
+	tmpDir := t.TempDir()
+	store, _ := NewJournalMDStore(
+		filepath.Join(tmpDir, "project"),
+		filepath.Join(tmpDir, "user"),
+		WithEmbedder(embeddings.NewHashEmbedder(32)),
+	)
+
+	entry := models.NewJournalEntry(map[string]string{"feelings": "old model"}, "user")
+	if err := store.WriteEntry(entry); err != nil {
+		t.Fatalf("WriteEntry error: %v", err)
+	}
+
+	// Overwrite the sidecar as if a different embedder had produced it
+	stale, _ := json.Marshal(models.Embedding{Model: "other-model", Dimension: 3, Vector: []float32{1, 0, 0}})
+	if err := os.WriteFile(embeddings.EmbeddingPath(entry.FilePath), stale, 0o644); err != nil {
+		t.Fatalf("failed to write stale sidecar: %v", err)
+	}
+
+	report, err := store.BackfillEmbeddings(EmbedOptions{})
+	if err != nil {
+		t.Fatalf("BackfillEmbeddings error: %v", err)
+	}
+	if report.Outdated != 1 || report.Written != 1 {
+		t.Errorf("expected the stale sidecar to be replaced, got %+v", report)
+	}
+}
+
+func TestBackfillEmbeddingsRequiresEmbedder(t *testing.T) {
+	tmpDir := t.TempDir()
+	store, _ := NewJournalMDStore(filepath.Join(tmpDir, "project"), filepath.Join(tmpDir, "user"))
+	if _, err := store.BackfillEmbeddings(EmbedOptions{}); err == nil {
+		t.Error("expected error without an embedder")
+	}
+}
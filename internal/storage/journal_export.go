@@ -0,0 +1,265 @@
+// ABOUTME: Journal export to JSONL, a single markdown document, and a static HTML site.
+// ABOUTME: Writers take listed entries, so exports share ListEntries filters plus a section filter.
+package storage
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/harperreed/mdstore"
+
+	"github.com/2389-research/pulse/internal/models"
+)
+
+// Export formats accepted by pulse journal export.
+const (
+	ExportJSONL    = "jsonl"
+	ExportMarkdown = "markdown"
+	ExportHTML     = "html"
+)
+
+// SelectSections returns copies of entries holding only the named sections,
+// dropping entries left with none. With no names, entries are returned as is.
+func SelectSections(entries []*models.JournalEntry, names []string) []*models.JournalEntry {
+	if len(names) == 0 {
+		return entries
+	}
+	var out []*models.JournalEntry
+	for _, entry := range entries {
+		kept := make(map[string]string)
+		for _, name := range names {
+			if content, ok := entry.Sections[name]; ok {
+				kept[name] = content
+			}
+		}
+		if len(kept) == 0 {
+			continue
+		}
+		copied := *entry
+		copied.Sections = kept
+		out = append(out, &copied)
+	}
+	return out
+}
+
+// WriteJSONL writes one JSON-encoded entry per line.
+func WriteJSONL(w io.Writer, entries []*models.JournalEntry) error {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	enc.SetEscapeHTML(false)
+	for _, entry := range entries {
+		if err := enc.Encode(entry); err != nil {
+			return fmt.Errorf("failed to encode entry %s: %w", entry.ID, err)
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	return nil
+}
+
+// WriteMarkdown writes entries as one markdown document: a table of contents
+// by day, then each entry under its day with its metadata and sections.
+func WriteMarkdown(w io.Writer, entries []*models.JournalEntry) error {
+	days := groupByDay(entries)
+
+	var sb strings.Builder
+	sb.WriteString("# Journal export\n\n")
+	if len(entries) == 0 {
+		sb.WriteString("No entries.\n")
+	} else {
+		sb.WriteString(fmt.Sprintf("%d entries from %s to %s.\n", len(entries), days[0].Date, days[len(days)-1].Date))
+	}
+
+	if len(days) > 0 {
+		sb.WriteString("\n## Contents\n\n")
+		for _, day := range days {
+			sb.WriteString(fmt.Sprintf("- [%s](#day-%s) (%d)\n", day.Date, day.Date, len(day.Entries)))
+			for _, entry := range day.Entries {
+				sb.WriteString(fmt.Sprintf("  - [%s %s](#entry-%s)\n", entry.CreatedAt.Local().Format("15:04:05"), entry.Type, entry.ID))
+			}
+		}
+	}
+
+	for _, day := range days {
+		sb.WriteString(fmt.Sprintf("\n<a id=\"day-%s\"></a>\n\n## %s\n", day.Date, day.Date))
+		for _, entry := range day.Entries {
+			sb.WriteString(fmt.Sprintf("\n<a id=\"entry-%s\"></a>\n\n### %s %s\n\n", entry.ID, entry.CreatedAt.Local().Format("15:04:05"), entry.Type))
+			sb.WriteString(fmt.Sprintf("- ID: %s\n", entry.ID))
+			if entry.FilePath != "" {
+				sb.WriteString(fmt.Sprintf("- Source: %s\n", entry.FilePath))
+			}
+			for _, field := range entry.Fields() {
+				sb.WriteString(fmt.Sprintf("- %s: %s\n", field[0], field[1]))
+			}
+			// Section headings sit a level below the entry heading
+			sb.WriteString(strings.ReplaceAll(renderSections(entry.Sections), "\n## ", "\n#### "))
+		}
+	}
+
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	return nil
+}
+
+// WriteHTMLSite writes a self-contained static site to dir: index.html lists
+// every day and searches all entries in the browser, and each day has its own
+// page. Nothing is loaded from the network.
+func WriteHTMLSite(dir string, entries []*models.JournalEntry) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create export directory: %w", err)
+	}
+	days := groupByDay(entries)
+
+	type searchDoc struct {
+		URL   string `json:"url"`
+		Title string `json:"title"`
+		Text  string `json:"text"`
+	}
+	var docs []searchDoc
+	for _, day := range days {
+		for _, entry := range day.Entries {
+			var text []string
+			for _, name := range sectionOrder(entry.Sections) {
+				text = append(text, entry.Sections[name])
+			}
+			text = append(text, entry.Tags...)
+			docs = append(docs, searchDoc{
+				URL:   fmt.Sprintf("%s.html#entry-%s", day.Date, entry.ID),
+				Title: fmt.Sprintf("%s %s %s", day.Date, entry.CreatedAt.Local().Format("15:04"), entry.Type),
+				Text:  strings.Join(strings.Fields(strings.Join(text, " ")), " "),
+			})
+		}
+	}
+
+	if err := writeTemplate(filepath.Join(dir, "index.html"), indexTemplate, map[string]interface{}{
+		"Days":  days,
+		"Count": len(entries),
+		"Docs":  docs,
+	}); err != nil {
+		return err
+	}
+	for i, day := range days {
+		data := map[string]interface{}{"Day": day}
+		if i > 0 {
+			data["Prev"] = days[i-1].Date
+		}
+		if i < len(days)-1 {
+			data["Next"] = days[i+1].Date
+		}
+		if err := writeTemplate(filepath.Join(dir, day.Date+".html"), dayTemplate, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exportDay holds the entries created on one local day.
+type exportDay struct {
+	Date    string
+	Entries []*models.JournalEntry
+}
+
+// groupByDay groups entries, assumed oldest first, by local creation date.
+func groupByDay(entries []*models.JournalEntry) []exportDay {
+	var days []exportDay
+	for _, entry := range entries {
+		date := entry.CreatedAt.Local().Format("2006-01-02")
+		if len(days) == 0 || days[len(days)-1].Date != date {
+			days = append(days, exportDay{Date: date})
+		}
+		days[len(days)-1].Entries = append(days[len(days)-1].Entries, entry)
+	}
+	return days
+}
+
+// writeTemplate renders tmpl with data and writes it atomically to path.
+func writeTemplate(path string, tmpl *template.Template, data interface{}) error {
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return fmt.Errorf("failed to render %s: %w", filepath.Base(path), err)
+	}
+	if err := mdstore.AtomicWrite(path, []byte(sb.String())); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+var exportFuncs = template.FuncMap{
+	"time":    func(e *models.JournalEntry) string { return e.CreatedAt.Local().Format("15:04:05") },
+	"title":   models.SectionTitle,
+	"section": func(e *models.JournalEntry, name string) string { return e.Sections[name] },
+	"names":   func(e *models.JournalEntry) []string { return sectionOrder(e.Sections) },
+}
+
+const exportStyle = `<style>
+body{font-family:system-ui,sans-serif;max-width:50rem;margin:2rem auto;padding:0 1rem;line-height:1.5;color:#222}
+a{color:#2457a6}nav{margin:1rem 0}nav a{margin-right:1rem}
+article{border-top:1px solid #ddd;padding-top:1rem;margin-top:1rem}
+.meta{color:#666;font-size:.9rem}.section{white-space:pre-wrap}
+input{width:100%;padding:.5rem;font-size:1rem;box-sizing:border-box}
+#results li{margin:.5rem 0}#results .snippet{color:#555;font-size:.9rem}
+</style>`
+
+var indexTemplate = template.Must(template.New("index").Funcs(exportFuncs).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head><meta charset="utf-8"><title>Journal</title>` + exportStyle + `</head>
+<body>
+<h1>Journal</h1>
+<p>{{.Count}} entries.</p>
+<input id="q" type="search" placeholder="Search entries" autofocus>
+<ul id="results"></ul>
+<h2>Days</h2>
+<ul>{{range .Days}}
+<li><a href="{{.Date}}.html">{{.Date}}</a> ({{len .Entries}})</li>{{end}}
+</ul>
+<script>
+const docs = {{.Docs}} || [];
+const input = document.getElementById("q");
+const results = document.getElementById("results");
+input.addEventListener("input", () => {
+  const words = input.value.toLowerCase().split(/\s+/).filter(Boolean);
+  results.replaceChildren();
+  if (!words.length) return;
+  for (const doc of docs) {
+    const text = doc.text.toLowerCase();
+    if (!words.every(w => text.includes(w))) continue;
+    const li = document.createElement("li");
+    const a = document.createElement("a");
+    a.href = doc.url;
+    a.textContent = doc.title;
+    const at = Math.max(0, text.indexOf(words[0]) - 40);
+    const snippet = document.createElement("div");
+    snippet.className = "snippet";
+    snippet.textContent = (at > 0 ? "..." : "") + doc.text.slice(at, at + 160);
+    li.append(a, snippet);
+    results.append(li);
+  }
+});
+</script>
+</body>
+</html>
+`))
+
+var dayTemplate = template.Must(template.New("day").Funcs(exportFuncs).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head><meta charset="utf-8"><title>Journal {{.Day.Date}}</title>` + exportStyle + `</head>
+<body>
+<nav><a href="index.html">All days</a>{{with .Prev}}<a href="{{.}}.html">&larr; {{.}}</a>{{end}}{{with .Next}}<a href="{{.}}.html">{{.}} &rarr;</a>{{end}}</nav>
+<h1>{{.Day.Date}}</h1>
+{{range .Day.Entries}}{{$entry := .}}<article id="entry-{{.ID}}">
+<h2>{{time .}} <small>{{.Type}}</small></h2>
+<div class="meta">{{range .Fields}}<div>{{index . 0}}: {{index . 1}}</div>{{end}}</div>
+{{range names .}}<h3>{{title .}}</h3>
+<div class="section">{{section $entry .}}</div>
+{{end}}</article>
+{{end}}</body>
+</html>
+`))
@@ -0,0 +1,140 @@
+// ABOUTME: Tests for journal export.
+// ABOUTME: Covers JSONL roundtrips, the markdown table of contents, the static HTML site, and section selection.
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/2389-research/pulse/internal/models"
+)
+
+func exportTestEntries() []*models.JournalEntry {
+	day := time.Date(2026, 3, 1, 9, 30, 0, 0, time.Local)
+	valence := 0.5
+	return []*models.JournalEntry{
+		{
+			ID:        uuid.New(),
+			Sections:  map[string]string{"feelings": "nervous about <script> tags", "technical_insights": "escape & test"},
+			CreatedAt: day,
+			Type:      "user",
+			ThoughtID: uuid.New(),
+			EntryMeta: models.EntryMeta{Tags: []string{"web"}, Valence: &valence},
+		},
+		{
+			ID:        uuid.New(),
+			Sections:  map[string]string{"project_notes": "shipped the export"},
+			CreatedAt: day.AddDate(0, 0, 1),
+			Type:      "project",
+		},
+	}
+}
+
+func TestWriteJSONLRoundtrip(t *testing.T) {
+	entries := exportTestEntries()
+	var buf bytes.Buffer
+	if err := WriteJSONL(&buf, entries); err != nil {
+		t.Fatalf("WriteJSONL error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "<script>") {
+		t.Errorf("expected JSONL to keep markup unescaped, got %s", buf.String())
+	}
+
+	scanner := bufio.NewScanner(&buf)
+	var decoded []*models.JournalEntry
+	for scanner.Scan() {
+		var entry models.JournalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("line %d is not an entry: %v", len(decoded)+1, err)
+		}
+		decoded = append(decoded, &entry)
+	}
+	if len(decoded) != 2 {
+		t.Fatalf("expected 2 lines, got %d", len(decoded))
+	}
+	got, want := decoded[0], entries[0]
+	if got.ID != want.ID || got.ThoughtID != want.ThoughtID || !got.CreatedAt.Equal(want.CreatedAt) {
+		t.Errorf("identity changed in roundtrip: %+v", got)
+	}
+	if got.Sections["technical_insights"] != "escape & test" || got.Tags[0] != "web" || *got.Valence != 0.5 {
+		t.Errorf("content changed in roundtrip: %+v", got)
+	}
+	if strings.Contains(buf.String(), "updated_at") {
+		t.Errorf("expected zero timestamps to be omitted")
+	}
+}
+
+func TestWriteMarkdownHasContents(t *testing.T) {
+	entries := exportTestEntries()
+	var buf bytes.Buffer
+	if err := WriteMarkdown(&buf, entries); err != nil {
+		t.Fatalf("WriteMarkdown error: %v", err)
+	}
+	md := buf.String()
+	for _, want := range []string{
+		"2 entries from 2026-03-01 to 2026-03-02.",
+		"- [2026-03-01](#day-2026-03-01) (1)",
+		"  - [09:30:00 user](#entry-" + entries[0].ID.String() + ")",
+		"<a id=\"entry-" + entries[1].ID.String() + "\"></a>",
+		"- Tags: web",
+		"#### Technical Insights\nescape & test",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+	if strings.Contains(md, "\n## Feelings") {
+		t.Error("expected section headings nested below entry headings")
+	}
+}
+
+func TestWriteHTMLSite(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "site")
+	if err := WriteHTMLSite(dir, exportTestEntries()); err != nil {
+		t.Fatalf("WriteHTMLSite error: %v", err)
+	}
+
+	index, err := os.ReadFile(filepath.Join(dir, "index.html"))
+	if err != nil {
+		t.Fatalf("missing index.html: %v", err)
+	}
+	if !strings.Contains(string(index), `href="2026-03-02.html"`) || !strings.Contains(string(index), "shipped the export") {
+		t.Errorf("index should link each day and carry the search data:\n%s", index)
+	}
+	if strings.Contains(string(index), "<script> tags") {
+		t.Error("entry text must not break out of the search data script")
+	}
+
+	page, err := os.ReadFile(filepath.Join(dir, "2026-03-01.html"))
+	if err != nil {
+		t.Fatalf("missing day page: %v", err)
+	}
+	if !strings.Contains(string(page), "nervous about &lt;script&gt; tags") {
+		t.Errorf("expected escaped entry text on the day page:\n%s", page)
+	}
+	if !strings.Contains(string(page), `href="2026-03-02.html"`) {
+		t.Error("expected a link to the next day")
+	}
+}
+
+func TestSelectSections(t *testing.T) {
+	entries := exportTestEntries()
+	selected := SelectSections(entries, []string{"technical_insights"})
+	if len(selected) != 1 {
+		t.Fatalf("expected entries without the section to be dropped, got %d", len(selected))
+	}
+	if len(selected[0].Sections) != 1 || selected[0].Sections["technical_insights"] == "" {
+		t.Errorf("expected only the selected section, got %v", selected[0].Sections)
+	}
+	if len(entries[0].Sections) != 2 {
+		t.Error("selection must not modify the original entries")
+	}
+}
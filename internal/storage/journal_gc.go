@@ -0,0 +1,258 @@
+// ABOUTME: Retention policies for journal entries: deleting old entries or sections and archiving old days.
+// ABOUTME: GC applies the rules to each journal's root, including archived bundles, and reports what it changed.
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/harperreed/mdstore"
+
+	"github.com/2389-research/pulse/internal/embeddings"
+	"github.com/2389-research/pulse/internal/models"
+	"github.com/2389-research/pulse/internal/query"
+)
+
+// RetentionAction is what a retention rule does with entries past its age.
+type RetentionAction string
+
+const (
+	RetentionDelete  RetentionAction = "delete"  // remove entries, or one section of them, permanently
+	RetentionArchive RetentionAction = "archive" // move whole days into compressed bundles
+)
+
+// RetentionRule applies an action to entries older than an age, e.g. delete
+// feelings older than 180d, or archive project entries older than 1y.
+type RetentionRule struct {
+	Type      string          // journal name, comma-separated names, or "" for all journals
+	Section   string          // delete only this section; "" for whole entries
+	OlderThan string          // an age like 180d, 12w, 6m, or 1y
+	Action    RetentionAction // RetentionDelete or RetentionArchive
+}
+
+// Validate checks that the rule can be applied. GC also checks that its
+// type names the store's journals.
+func (r RetentionRule) Validate() error {
+	switch r.Action {
+	case RetentionDelete:
+	case RetentionArchive:
+		if r.Section != "" {
+			return fmt.Errorf("archive rules apply to whole days and cannot name a section")
+		}
+	default:
+		return fmt.Errorf("invalid retention action %q: must be delete or archive", r.Action)
+	}
+	if r.Section != "" && !models.IsValidSection(r.Section) {
+		return fmt.Errorf("invalid retention section %q: must be one of: %s", r.Section, models.ValidSectionList())
+	}
+	if _, err := r.cutoff(time.Now()); err != nil {
+		return err
+	}
+	return nil
+}
+
+// cutoff returns the instant before which entries fall under the rule.
+func (r RetentionRule) cutoff(now time.Time) (time.Time, error) {
+	if r.OlderThan == "" {
+		return time.Time{}, fmt.Errorf("retention rule needs an age, e.g. 180d")
+	}
+	span, err := query.ParseDate(r.OlderThan, now)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid retention age: %w", err)
+	}
+	return span.Start, nil
+}
+
+// String describes the rule, e.g. "delete feelings in user entries older than 180d".
+func (r RetentionRule) String() string {
+	what := "entries"
+	if r.Type != "" && r.Type != "all" {
+		what = r.Type + " entries"
+	}
+	if r.Section != "" {
+		what = r.Section + " in " + what
+	}
+	return fmt.Sprintf("%s %s older than %s", r.Action, what, r.OlderThan)
+}
+
+// GCReport summarizes a GC run. In a dry run it counts what would change.
+type GCReport struct {
+	DeletedEntries  int // entries removed, including those left with no sections
+	DeletedSections int // sections removed from entries that were kept
+	ArchivedEntries int // entries moved into bundles
+	ArchivedDays    int // date directories archived
+	Skipped         int // encrypted entries a section rule could not open
+}
+
+// GC applies retention rules to every journal's root as of now. Delete rules run
+// first, over live and archived entries; archive rules then move whole date
+// directories older than their cutoff into bundles. The trash is left to
+// EmptyTrash. With dryRun nothing is changed.
+func (s *JournalMDStore) GC(rules []RetentionRule, now time.Time, dryRun bool) (*GCReport, error) {
+	for _, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			return nil, err
+		}
+		if _, _, err := s.rootsFor(rule.Type); err != nil {
+			return nil, fmt.Errorf("invalid retention type: %w", err)
+		}
+	}
+
+	report := &GCReport{}
+	for _, action := range []RetentionAction{RetentionDelete, RetentionArchive} {
+		for _, rule := range rules {
+			if rule.Action != action {
+				continue
+			}
+			cutoff, _ := rule.cutoff(now)
+			roots, journals, _ := s.rootsFor(rule.Type)
+			for _, root := range roots {
+				var err error
+				if action == RetentionDelete {
+					err = s.applyDeleteRule(root, rule, journals, cutoff, dryRun, report)
+				} else {
+					err = s.applyArchiveRule(root, journals, cutoff, dryRun, report)
+				}
+				if err != nil {
+					return report, fmt.Errorf("failed to %s: %w", rule, err)
+				}
+			}
+		}
+	}
+
+	if !dryRun {
+		for _, root := range s.allRoots() {
+			if err := s.withIndex(root, nil); err != nil {
+				return report, fmt.Errorf("failed to update index: %w", err)
+			}
+		}
+	}
+	return report, nil
+}
+
+// retentionEdit decides what a delete rule covering journals does to one
+// entry. Entries of other journals sharing the root are kept.
+func retentionEdit(entry *models.JournalEntry, rule RetentionRule, journals []string, cutoff time.Time, report *GCReport) bundleEdit {
+	if !inJournals(entry.Type, journals) {
+		return bundleKeep
+	}
+	if !entry.CreatedAt.Before(cutoff) {
+		return bundleKeep
+	}
+	if rule.Section == "" {
+		report.DeletedEntries++
+		return bundleDrop
+	}
+	if entry.Locked {
+		report.Skipped++
+		return bundleKeep
+	}
+	if _, ok := entry.Sections[rule.Section]; !ok {
+		return bundleKeep
+	}
+	delete(entry.Sections, rule.Section)
+	if len(entry.Sections) == 0 {
+		report.DeletedEntries++
+		return bundleDrop
+	}
+	report.DeletedSections++
+	return bundleUpdate
+}
+
+// applyDeleteRule applies a delete rule to root's live and archived entries.
+func (s *JournalMDStore) applyDeleteRule(root string, rule RetentionRule, journals []string, cutoff time.Time, dryRun bool, report *GCReport) error {
+	entries, err := listEntriesInRoot(root, s.cipher)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		edit := retentionEdit(entry, rule, journals, cutoff, report)
+		if dryRun || edit == bundleKeep {
+			continue
+		}
+		err := mdstore.WithLock(filepath.Dir(entry.FilePath), func() error {
+			if edit == bundleUpdate {
+				return s.writeEntryFile(root, entry.FilePath, entry)
+			}
+			if err := os.Remove(entry.FilePath); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to remove entry: %w", err)
+			}
+			if err := os.Remove(embeddings.EmbeddingPath(entry.FilePath)); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to remove embedding: %w", err)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// Bundles hold whole days, so only those before the cutoff's day can match
+	days, err := listBundles(root)
+	if err != nil {
+		return err
+	}
+	cutoffDay := cutoff.AddDate(0, 0, 1).Format("2006-01-02")
+	for _, day := range days {
+		if day >= cutoffDay {
+			continue
+		}
+		_, _, err := s.rewriteBundle(root, day, dryRun, func(entry *models.JournalEntry) (bundleEdit, error) {
+			return retentionEdit(entry, rule, journals, cutoff, report), nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyArchiveRule archives root's date directories for days before cutoff's.
+// A bundle holds a whole day, so days with entries of journals the rule does
+// not cover, which share the root, stay live.
+func (s *JournalMDStore) applyArchiveRule(root string, journals []string, cutoff time.Time, dryRun bool, report *GCReport) error {
+	foreign := make(map[string]bool)
+	err := s.withIndex(root, func(idx *journalIndex) error {
+		for entryType, paths := range idx.Types {
+			if inJournals(entryType, journals) {
+				continue
+			}
+			for _, rel := range paths {
+				foreign[rel[:strings.Index(rel, "/")]] = true
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	dirs, err := os.ReadDir(root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	cutoffDay := cutoff.Format("2006-01-02")
+	for _, dir := range dirs {
+		if !dir.IsDir() || dir.Name() >= cutoffDay || foreign[dir.Name()] {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", dir.Name()); err != nil {
+			continue
+		}
+		archived, err := s.archiveDay(root, dir.Name(), dryRun)
+		if err != nil {
+			return err
+		}
+		if archived > 0 {
+			report.ArchivedEntries += archived
+			report.ArchivedDays++
+		}
+	}
+	return nil
+}
@@ -0,0 +1,228 @@
+// ABOUTME: Tests for journal retention rules and archive bundles.
+// ABOUTME: Covers section and entry deletion, dry runs, archiving days, and reading archived entries back.
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/2389-research/pulse/internal/embeddings"
+	"github.com/2389-research/pulse/internal/models"
+)
+
+// writeAged writes an entry created the given number of days ago.
+func writeAged(t *testing.T, store *JournalMDStore, entryType string, days int, sections map[string]string) *models.JournalEntry {
+	t.Helper()
+	entry := models.NewJournalEntry(sections, entryType)
+	entry.CreatedAt = time.Now().AddDate(0, 0, -days)
+	if err := store.WriteEntry(entry); err != nil {
+		t.Fatalf("WriteEntry error: %v", err)
+	}
+	return entry
+}
+
+func TestGCDeletesSections(t *testing.T) {
+	dir := t.TempDir()
+	store, _ := NewJournalMDStore(filepath.Join(dir, "project"), filepath.Join(dir, "user"), WithGitContext(false))
+
+	mixed := writeAged(t, store, "user", 200, map[string]string{"feelings": "old mood", "technical_insights": "old insight"})
+	onlyFeelings := writeAged(t, store, "user", 200, map[string]string{"feelings": "only mood"})
+	recent := writeAged(t, store, "user", 10, map[string]string{"feelings": "recent mood"})
+
+	rules := []RetentionRule{{Type: "user", Section: "feelings", OlderThan: "180d", Action: RetentionDelete}}
+	report, err := store.GC(rules, time.Now(), true)
+	if err != nil {
+		t.Fatalf("GC dry run error: %v", err)
+	}
+	if report.DeletedSections != 1 || report.DeletedEntries != 1 {
+		t.Errorf("expected dry run to count 1 section and 1 entry, got %+v", report)
+	}
+	if _, err := os.Stat(onlyFeelings.FilePath); err != nil {
+		t.Fatalf("expected dry run to change nothing: %v", err)
+	}
+
+	if _, err := store.GC(rules, time.Now(), false); err != nil {
+		t.Fatalf("GC error: %v", err)
+	}
+	read, err := store.ReadEntry(mixed.FilePath)
+	if err != nil {
+		t.Fatalf("ReadEntry error: %v", err)
+	}
+	if _, ok := read.Sections["feelings"]; ok || read.Sections["technical_insights"] != "old insight" {
+		t.Errorf("expected only feelings removed, got %v", read.Sections)
+	}
+	if _, err := os.Stat(onlyFeelings.FilePath); !os.IsNotExist(err) {
+		t.Errorf("expected an entry left without sections to be deleted, stat err = %v", err)
+	}
+	if read, _ := store.ReadEntry(recent.FilePath); read == nil || read.Sections["feelings"] != "recent mood" {
+		t.Errorf("expected recent entry untouched, got %+v", read)
+	}
+	if results, _ := store.Search("mood", embeddings.SearchOptions{}); len(results) != 1 {
+		t.Errorf("expected the index to drop deleted text, got %d results", len(results))
+	}
+}
+
+func TestGCArchivesOldDays(t *testing.T) {
+	dir := t.TempDir()
+	store, _ := NewJournalMDStore(filepath.Join(dir, "project"), filepath.Join(dir, "user"),
+		WithEmbedder(embeddings.NewHashEmbedder(embeddings.DefaultDimension)), WithGitContext(false))
+
+	old := writeAged(t, store, "project", 400, map[string]string{"project_notes": "migrated the billing schema"})
+	sibling := writeAged(t, store, "project", 400, map[string]string{"project_notes": "second note that day"})
+	recent := writeAged(t, store, "project", 30, map[string]string{"project_notes": "recent billing work"})
+	user := writeAged(t, store, "user", 400, map[string]string{"feelings": "old but user"})
+
+	report, err := store.GC([]RetentionRule{{Type: "project", OlderThan: "1y", Action: RetentionArchive}}, time.Now(), false)
+	if err != nil {
+		t.Fatalf("GC error: %v", err)
+	}
+	if report.ArchivedEntries != 2 || report.ArchivedDays != 1 {
+		t.Errorf("expected 2 entries from 1 day archived, got %+v", report)
+	}
+
+	day := old.CreatedAt.Format("2006-01-02")
+	if _, err := os.Stat(filepath.Join(dir, "project", day)); !os.IsNotExist(err) {
+		t.Errorf("expected the archived date directory to be removed, stat err = %v", err)
+	}
+	if _, err := os.Stat(bundlePath(filepath.Join(dir, "project"), day)); err != nil {
+		t.Errorf("expected a bundle for %s: %v", day, err)
+	}
+	if _, err := os.Stat(user.FilePath); err != nil {
+		t.Errorf("expected the user root to be left alone: %v", err)
+	}
+
+	read, err := store.ReadEntry(old.FilePath)
+	if err != nil {
+		t.Fatalf("ReadEntry of archived entry error: %v", err)
+	}
+	if read.ID != old.ID || read.Sections["project_notes"] != "migrated the billing schema" {
+		t.Errorf("unexpected archived entry %+v", read)
+	}
+	if _, err := store.UpdateEntry(old.FilePath, map[string]string{"project_notes": "changed"}); err == nil || !strings.Contains(err.Error(), "archived") {
+		t.Errorf("expected editing an archived entry to fail, got %v", err)
+	}
+	if _, err := store.DeleteEntry(sibling.FilePath, ""); err == nil || !strings.Contains(err.Error(), "archived") {
+		t.Errorf("expected deleting an archived entry to fail, got %v", err)
+	}
+
+	// A fresh store rebuilds its view from disk, bundles included
+	fresh, _ := NewJournalMDStore(filepath.Join(dir, "project"), filepath.Join(dir, "user"), WithGitContext(false))
+	if count, err := fresh.Reindex(); err != nil || count != 4 {
+		t.Errorf("expected Reindex to count 4 entries, got %d, %v", count, err)
+	}
+	entries, err := fresh.ListEntries(ListOptions{Type: "project", Order: OldestFirst})
+	if err != nil || len(entries) != 3 || entries[2].ID != recent.ID {
+		t.Fatalf("expected archived and live project entries listed, got %d, %v", len(entries), err)
+	}
+	results, err := fresh.Search("billing", embeddings.SearchOptions{Type: "project"})
+	if err != nil || len(results) != 2 {
+		t.Errorf("expected search to find archived and live entries, got %d, %v", len(results), err)
+	}
+
+	// Delete rules reach into bundles too
+	report, err = fresh.GC([]RetentionRule{{Type: "project", OlderThan: "1y", Action: RetentionDelete}}, time.Now(), false)
+	if err != nil || report.DeletedEntries != 2 {
+		t.Fatalf("expected 2 archived entries deleted, got %+v, %v", report, err)
+	}
+	if _, err := os.Stat(bundlePath(filepath.Join(dir, "project"), day)); !os.IsNotExist(err) {
+		t.Errorf("expected the emptied bundle to be removed, stat err = %v", err)
+	}
+	if entries, _ := fresh.ListEntries(ListOptions{Type: "project"}); len(entries) != 1 {
+		t.Errorf("expected only the recent entry left, got %d", len(entries))
+	}
+}
+
+func TestGCDeleteSkipsOtherJournalsInSharedRoot(t *testing.T) {
+	// Running from the home directory gives project and user one root
+	root := filepath.Join(t.TempDir(), "journal")
+	store, _ := NewJournalMDStore(root, root, WithGitContext(false))
+
+	user := writeAged(t, store, "user", 200, map[string]string{"feelings": "old user mood"})
+	project := writeAged(t, store, "project", 200, map[string]string{"feelings": "old project mood"})
+
+	report, err := store.GC([]RetentionRule{{Type: "user", OlderThan: "180d", Action: RetentionDelete}}, time.Now(), false)
+	if err != nil {
+		t.Fatalf("GC error: %v", err)
+	}
+	if report.DeletedEntries != 1 {
+		t.Errorf("expected only the user entry deleted, got %+v", report)
+	}
+	if _, err := os.Stat(user.FilePath); !os.IsNotExist(err) {
+		t.Errorf("expected the user entry deleted, stat err = %v", err)
+	}
+	if _, err := os.Stat(project.FilePath); err != nil {
+		t.Errorf("expected the project entry in the shared root kept: %v", err)
+	}
+
+	// Archiving only user entries would take the project entry's day with them
+	userOnly := writeAged(t, store, "user", 300, map[string]string{"feelings": "older user mood"})
+	report, err = store.GC([]RetentionRule{{Type: "user", OlderThan: "180d", Action: RetentionArchive}}, time.Now(), false)
+	if err != nil {
+		t.Fatalf("GC archive error: %v", err)
+	}
+	if report.ArchivedDays != 1 || report.ArchivedEntries != 1 {
+		t.Errorf("expected only the user-only day archived, got %+v", report)
+	}
+	if _, err := os.Stat(userOnly.FilePath); !os.IsNotExist(err) {
+		t.Errorf("expected the user-only day archived, stat err = %v", err)
+	}
+	if _, err := os.Stat(project.FilePath); err != nil {
+		t.Errorf("expected the project entry still live: %v", err)
+	}
+
+	// Archived days in the shared root hold both journals too
+	if _, err := store.GC([]RetentionRule{{OlderThan: "180d", Action: RetentionArchive}}, time.Now(), false); err != nil {
+		t.Fatalf("GC archive error: %v", err)
+	}
+	other := writeAged(t, store, "user", 200, map[string]string{"feelings": "another user mood"})
+	if _, err := store.GC([]RetentionRule{{OlderThan: "180d", Action: RetentionArchive}}, time.Now(), false); err != nil {
+		t.Fatalf("GC archive error: %v", err)
+	}
+	report, err = store.GC([]RetentionRule{{Type: "user", Section: "feelings", OlderThan: "180d", Action: RetentionDelete}}, time.Now(), false)
+	if err != nil {
+		t.Fatalf("GC error: %v", err)
+	}
+	if report.DeletedEntries != 2 {
+		t.Errorf("expected only the archived user entries deleted, got %+v", report)
+	}
+	if read, err := store.ReadEntry(project.FilePath); err != nil || read.Sections["feelings"] != "old project mood" {
+		t.Errorf("expected the archived project entry kept, got %+v, %v", read, err)
+	}
+	for _, entry := range []*models.JournalEntry{other, userOnly} {
+		if _, err := store.ReadEntry(entry.FilePath); err == nil {
+			t.Errorf("expected the archived user entry %s deleted", entry.ID)
+		}
+	}
+}
+
+func TestRetentionRuleValidate(t *testing.T) {
+	for _, rule := range []RetentionRule{
+		{OlderThan: "180d", Action: "shred"},
+		{OlderThan: "180d", Action: RetentionArchive, Section: "feelings"},
+		{OlderThan: "180d", Action: RetentionDelete, Section: "nope"},
+		{OlderThan: "soon", Action: RetentionDelete},
+		{Action: RetentionDelete},
+	} {
+		if err := rule.Validate(); err == nil {
+			t.Errorf("expected %+v to be rejected", rule)
+		}
+	}
+	rule := RetentionRule{Type: "user", Section: "feelings", OlderThan: "180d", Action: RetentionDelete}
+	if err := rule.Validate(); err != nil {
+		t.Errorf("Validate error: %v", err)
+	}
+	if rule.String() != "delete feelings in user entries older than 180d" {
+		t.Errorf("unexpected description %q", rule.String())
+	}
+
+	// Types are checked against the store's journals
+	dir := t.TempDir()
+	store, _ := NewJournalMDStore(filepath.Join(dir, "project"), filepath.Join(dir, "user"), WithGitContext(false))
+	_, err := store.GC([]RetentionRule{{Type: "team", OlderThan: "1y", Action: RetentionDelete}}, time.Now(), true)
+	if err == nil || !strings.Contains(err.Error(), `unknown journal "team"`) {
+		t.Errorf("expected an unknown journal error, got %v", err)
+	}
+}
@@ -0,0 +1,203 @@
+// ABOUTME: Git integration for journal roots: context capture and exposure checks.
+// ABOUTME: Records the enclosing repository root, branch, HEAD commit, and dirty status via the git CLI.
+// ABOUTME: Also keeps journal roots out of git by excluding them locally and reporting tracked files.
+package storage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/2389-research/pulse/internal/models"
+)
+
+// gitTimeout bounds each git invocation so a slow or hung repository never
+// stalls a journal write.
+const gitTimeout = 2 * time.Second
+
+// gitContext describes the code state of a working tree.
+type gitContext struct {
+	Root   string // repository top-level directory
+	Branch string // empty on a detached HEAD
+	Commit string // empty before the first commit
+	Dirty  bool   // uncommitted changes are present
+}
+
+// captureGitContext inspects the git work tree enclosing dir. ok is false
+// when git is unavailable or dir is not inside a work tree.
+func captureGitContext(dir string) (gitContext, bool) {
+	root, err := runGit(dir, "rev-parse", "--show-toplevel")
+	if err != nil || root == "" {
+		return gitContext{}, false
+	}
+
+	gc := gitContext{Root: root}
+	if branch, err := runGit(dir, "symbolic-ref", "--short", "-q", "HEAD"); err == nil {
+		gc.Branch = branch
+	}
+	if commit, err := runGit(dir, "rev-parse", "-q", "--verify", "HEAD"); err == nil {
+		gc.Commit = commit
+	}
+	if status, err := runGit(dir, "status", "--porcelain", "--untracked-files=no"); err == nil {
+		gc.Dirty = status != ""
+	}
+	return gc, true
+}
+
+// runGit runs git with args in dir and returns its trimmed standard output.
+func runGit(dir string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), gitTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...)
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// applyGitContext fills in the git fields of a project entry from the work
+// tree enclosing the project root. Git fields the caller already set win,
+// and the dirty flag is only recorded alongside a captured commit.
+func (s *JournalMDStore) applyGitContext(entry *models.JournalEntry) {
+	if entry.Type != "project" || !s.captureGit {
+		return
+	}
+	gc, ok := captureGitContext(filepath.Dir(resolveRoot(s.projectPath)))
+	if !ok {
+		return
+	}
+	if entry.Repo == "" {
+		entry.Repo = gc.Root
+	}
+	if entry.Branch == "" {
+		entry.Branch = gc.Branch
+	}
+	if entry.Commit == "" && gc.Commit != "" {
+		entry.Commit = gc.Commit
+		entry.Dirty = gc.Dirty
+	}
+}
+
+// GitExposure describes how a journal root relates to the git work tree
+// around it.
+type GitExposure struct {
+	Root    string   // journal root as configured
+	Repo    string   // top-level directory of the enclosing work tree
+	Pattern string   // anchored exclude pattern for the root, e.g. "/.private-journal/"
+	Ignored bool     // git already ignores the root
+	Tracked []string // journal files git tracks, relative to Repo
+}
+
+// inspectGitExposure reports how git sees the journal root. ok is false when
+// git is unavailable or the root does not lie inside a work tree.
+func inspectGitExposure(root string) (GitExposure, bool) {
+	// The root may not exist before its first write, so resolve its parent
+	parent, err := filepath.EvalSymlinks(filepath.Dir(resolveRoot(root)))
+	if err != nil {
+		return GitExposure{}, false
+	}
+	top, err := runGit(parent, "rev-parse", "--show-toplevel")
+	if err != nil || top == "" {
+		return GitExposure{}, false
+	}
+	rel, err := filepath.Rel(top, filepath.Join(parent, filepath.Base(root)))
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return GitExposure{}, false
+	}
+	rel = filepath.ToSlash(rel)
+
+	exp := GitExposure{Root: root, Repo: top, Pattern: "/" + rel + "/"}
+	// check-ignore exits non-zero when the path is not ignored; --no-index
+	// checks the rules alone, since a tracked path never counts as ignored
+	if _, err := runGit(top, "check-ignore", "-q", "--no-index", rel+"/"); err == nil {
+		exp.Ignored = true
+	}
+	if tracked, err := runGit(top, "ls-files", "--", rel); err == nil && tracked != "" {
+		exp.Tracked = strings.Split(tracked, "\n")
+	}
+	return exp, true
+}
+
+// exclude appends the root's pattern to the repository's info/exclude file,
+// which ignores it locally without touching any committed .gitignore.
+func (exp GitExposure) exclude() error {
+	path, err := runGit(exp.Repo, "rev-parse", "--git-path", "info/exclude")
+	if err != nil {
+		return fmt.Errorf("failed to locate git exclude file: %w", err)
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(exp.Repo, path)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create git info directory: %w", err)
+	}
+
+	existing, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read git exclude file: %w", err)
+	}
+	var sb strings.Builder
+	if len(existing) > 0 && !strings.HasSuffix(string(existing), "\n") {
+		sb.WriteString("\n")
+	}
+	sb.WriteString("# private journal written by pulse\n" + exp.Pattern + "\n")
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open git exclude file: %w", err)
+	}
+	if _, err := f.WriteString(sb.String()); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write git exclude file: %w", err)
+	}
+	return f.Close()
+}
+
+// guardProjectJournal keeps the project journal out of git, once per store
+// before its first project write: it excludes the journal in the enclosing
+// repository's info/exclude unless git already ignores it, and warns when
+// git already tracks journal files, which excluding cannot undo.
+func (s *JournalMDStore) guardProjectJournal() {
+	if !s.guardGit {
+		return
+	}
+	s.guardOnce.Do(func() {
+		exp, ok := inspectGitExposure(s.projectPath)
+		if !ok {
+			return
+		}
+		if len(exp.Tracked) > 0 {
+			s.warn(fmt.Sprintf("%d project journal files are tracked by git in %s; run `pulse journal doctor`", len(exp.Tracked), exp.Repo))
+		}
+		if !exp.Ignored {
+			if err := exp.exclude(); err != nil {
+				s.warn(fmt.Sprintf("could not exclude the project journal from git: %v", err))
+			}
+		}
+	})
+}
+
+// CheckGitExposure reports, for each journal root inside a git work tree,
+// whether git ignores it and which of its files git tracks.
+func (s *JournalMDStore) CheckGitExposure() []GitExposure {
+	var out []GitExposure
+	for _, root := range s.allRoots() {
+		if exp, ok := inspectGitExposure(root); ok {
+			out = append(out, exp)
+		}
+	}
+	return out
+}
+
+// warn reports a problem that should not fail the operation in progress.
+func (s *JournalMDStore) warn(msg string) {
+	if s.warnings != nil {
+		s.warnings(msg)
+	}
+}
@@ -0,0 +1,184 @@
+// ABOUTME: Tests for git context capture on project journal entries.
+// ABOUTME: Uses a throwaway repository and skips when the git CLI is unavailable.
+package storage
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/2389-research/pulse/internal/models"
+)
+
+// initGitRepo creates a repository with one commit on branch main.
+func initGitRepo(t *testing.T, dir string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0o644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main"},
+		{"add", "main.go"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "initial"},
+	} {
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+}
+
+func TestWriteEntryCapturesGitContext(t *testing.T) {
+	tmpDir, _ := filepath.EvalSymlinks(t.TempDir())
+	work := filepath.Join(tmpDir, "work")
+	if err := os.MkdirAll(work, 0o755); err != nil {
+		t.Fatalf("failed to create work dir: %v", err)
+	}
+	initGitRepo(t, work)
+	head, err := runGit(work, "rev-parse", "HEAD")
+	if err != nil {
+		t.Fatalf("rev-parse failed: %v", err)
+	}
+
+	store, _ := NewJournalMDStore(filepath.Join(work, ".private-journal"), filepath.Join(tmpDir, "user"))
+
+	entries, err := store.WriteSections(map[string]string{
+		"project_notes": "clean tree",
+		"feelings":      "fine",
+	}, RouteOptions{})
+	if err != nil {
+		t.Fatalf("WriteSections error: %v", err)
+	}
+	project, err := store.ReadEntry(entries[0].FilePath)
+	if err != nil {
+		t.Fatalf("ReadEntry error: %v", err)
+	}
+	if project.Repo != work || project.Branch != "main" || project.Commit != head || project.Dirty {
+		t.Errorf("unexpected git context: %+v", project.EntryMeta)
+	}
+	if entries[1].Commit != "" || entries[1].Repo != "" {
+		t.Errorf("expected no git context on the user entry, got %+v", entries[1].EntryMeta)
+	}
+
+	if err := os.WriteFile(filepath.Join(work, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0o644); err != nil {
+		t.Fatalf("failed to modify file: %v", err)
+	}
+	dirty := models.NewJournalEntry(map[string]string{"project_notes": "dirty tree"}, "project")
+	if err := store.WriteEntry(dirty); err != nil {
+		t.Fatalf("WriteEntry error: %v", err)
+	}
+	if !dirty.Dirty {
+		t.Error("expected uncommitted changes to mark the entry dirty")
+	}
+
+	explicit := models.NewJournalEntry(map[string]string{"project_notes": "pinned"}, "project")
+	explicit.Commit = "deadbeef"
+	if err := store.WriteEntry(explicit); err != nil {
+		t.Fatalf("WriteEntry error: %v", err)
+	}
+	if explicit.Commit != "deadbeef" || explicit.Dirty || explicit.Branch != "main" {
+		t.Errorf("expected explicit commit to win, got %+v", explicit.EntryMeta)
+	}
+
+	found, err := store.ListEntries(ListOptions{Filter: models.MetaFilter{Commit: head[:7]}})
+	if err != nil {
+		t.Fatalf("ListEntries error: %v", err)
+	}
+	if len(found) != 2 {
+		t.Errorf("expected 2 entries at %s, got %d", head[:7], len(found))
+	}
+}
+
+func TestWriteEntryWithoutGitContext(t *testing.T) {
+	tmpDir, _ := filepath.EvalSymlinks(t.TempDir())
+	initGitRepo(t, tmpDir)
+
+	store, _ := NewJournalMDStore(filepath.Join(tmpDir, ".private-journal"), filepath.Join(tmpDir, "user"), WithGitContext(false))
+	entry := models.NewJournalEntry(map[string]string{"project_notes": "no context"}, "project")
+	if err := store.WriteEntry(entry); err != nil {
+		t.Fatalf("WriteEntry error: %v", err)
+	}
+	if entry.Repo != "" || entry.Commit != "" {
+		t.Errorf("expected no git context when disabled, got %+v", entry.EntryMeta)
+	}
+}
+
+func TestFirstProjectWriteExcludesJournalFromGit(t *testing.T) {
+	tmpDir, _ := filepath.EvalSymlinks(t.TempDir())
+	work := filepath.Join(tmpDir, "work")
+	if err := os.MkdirAll(work, 0o755); err != nil {
+		t.Fatalf("failed to create work dir: %v", err)
+	}
+	initGitRepo(t, work)
+
+	for i := 0; i < 2; i++ {
+		store, _ := NewJournalMDStore(filepath.Join(work, ".private-journal"), filepath.Join(tmpDir, "user"))
+		entry := models.NewJournalEntry(map[string]string{"project_notes": "excluded"}, "project")
+		if err := store.WriteEntry(entry); err != nil {
+			t.Fatalf("WriteEntry error: %v", err)
+		}
+	}
+
+	exclude, err := os.ReadFile(filepath.Join(work, ".git", "info", "exclude"))
+	if err != nil {
+		t.Fatalf("failed to read exclude file: %v", err)
+	}
+	if n := strings.Count(string(exclude), "/.private-journal/\n"); n != 1 {
+		t.Errorf("expected the journal to be excluded once, got %d times:\n%s", n, exclude)
+	}
+	if status, _ := runGit(work, "status", "--porcelain"); status != "" {
+		t.Errorf("expected journal files to be invisible to git, got:\n%s", status)
+	}
+}
+
+func TestTrackedJournalFilesAreReported(t *testing.T) {
+	tmpDir, _ := filepath.EvalSymlinks(t.TempDir())
+	initGitRepo(t, tmpDir)
+	journal := filepath.Join(tmpDir, ".private-journal")
+
+	unguarded, _ := NewJournalMDStore(journal, filepath.Join(tmpDir, "user"), WithGitGuard(false))
+	entry := models.NewJournalEntry(map[string]string{"project_notes": "committed by mistake"}, "project")
+	if err := unguarded.WriteEntry(entry); err != nil {
+		t.Fatalf("WriteEntry error: %v", err)
+	}
+	if _, err := runGit(tmpDir, "add", ".private-journal"); err != nil {
+		t.Fatalf("git add failed: %v", err)
+	}
+
+	var warnings []string
+	store, _ := NewJournalMDStore(journal, filepath.Join(tmpDir, "user"), WithWarnings(func(msg string) {
+		warnings = append(warnings, msg)
+	}))
+	if err := store.WriteEntry(models.NewJournalEntry(map[string]string{"project_notes": "again"}, "project")); err != nil {
+		t.Fatalf("WriteEntry error: %v", err)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "tracked by git") {
+		t.Errorf("expected a warning about tracked files, got %v", warnings)
+	}
+
+	var project *GitExposure
+	for _, exp := range store.CheckGitExposure() {
+		if exp.Root == journal {
+			project = &exp
+		}
+	}
+	if project == nil {
+		t.Fatal("expected the project journal to be reported")
+	}
+	if !project.Ignored || len(project.Tracked) != 2 {
+		t.Errorf("expected an ignored root with the entry and its index tracked, got %+v", project)
+	}
+}
+
+func TestJournalOutsideGitIsNotReported(t *testing.T) {
+	tmpDir := t.TempDir()
+	store, _ := NewJournalMDStore(filepath.Join(tmpDir, ".private-journal"), filepath.Join(tmpDir, "user"))
+	if exposures := store.CheckGitExposure(); len(exposures) != 0 {
+		t.Errorf("expected no exposure outside git, got %+v", exposures)
+	}
+}
@@ -0,0 +1,483 @@
+// ABOUTME: Journal import from Pulse JSONL exports, markdown files, and CSV.
+// ABOUTME: Importers read entries keeping their IDs and timestamps; ImportEntries skips duplicates by ID or content.
+package storage
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/mdstore"
+	"gopkg.in/yaml.v3"
+
+	"github.com/2389-research/pulse/internal/models"
+	"github.com/2389-research/pulse/internal/query"
+	"github.com/2389-research/pulse/internal/redact"
+)
+
+// Import formats accepted by pulse journal import.
+const (
+	ImportJSONL    = "jsonl"
+	ImportMarkdown = "markdown"
+	ImportCSV      = "csv"
+)
+
+// Importer reads journal entries from another tool's files. Entries keep the
+// IDs and timestamps the source records; their FilePath names where each one
+// came from, for reporting, and is replaced when the entry is imported.
+type Importer interface {
+	Read(path string) ([]*models.JournalEntry, error)
+}
+
+// DetectImportFormat guesses the import format from path: directories and .md
+// files are markdown, .csv is CSV, and anything else is JSONL.
+func DetectImportFormat(path string) string {
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return ImportMarkdown
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".md", ".markdown":
+		return ImportMarkdown
+	case ".csv":
+		return ImportCSV
+	}
+	return ImportJSONL
+}
+
+// JSONLImporter reads the output of pulse journal export --format jsonl.
+type JSONLImporter struct{}
+
+// Read decodes one entry per non-empty line.
+func (JSONLImporter) Read(path string) ([]*models.JournalEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var entries []*models.JournalEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var entry models.JournalEntry
+		if err := json.Unmarshal([]byte(text), &entry); err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid entry: %w", path, line, err)
+		}
+		entry.FilePath = fmt.Sprintf("%s:%d", path, line)
+		entries = append(entries, &entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return entries, nil
+}
+
+// MarkdownImporter reads markdown files with "## Section" headings, such as
+// Pulse entries or older private-journal layouts. path may be one file or a
+// directory, which is searched recursively for .md files.
+type MarkdownImporter struct{}
+
+// importFrontmatter accepts Pulse frontmatter plus the fields older journal
+// layouts used for the creation time.
+type importFrontmatter struct {
+	journalFrontmatter `yaml:",inline"`
+	CreatedAt          string `yaml:"created_at"`
+	Timestamp          int64  `yaml:"timestamp"` // Unix milliseconds
+}
+
+// legacyEntryName matches entry paths like 2025-05-31/14-15-30-123456.md.
+var legacyEntryName = regexp.MustCompile(`(\d{4}-\d{2}-\d{2})[/\\](\d{2}-\d{2}-\d{2})`)
+
+// Read parses each markdown file into one entry.
+func (MarkdownImporter) Read(path string) ([]*models.JournalEntry, error) {
+	var files []string
+	err := filepath.WalkDir(path, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		// Hidden directories, such as the trash, hold no live entries
+		if d.IsDir() && p != path && strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
+		if !d.IsDir() && strings.EqualFold(filepath.Ext(p), ".md") {
+			files = append(files, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var entries []*models.JournalEntry
+	for _, file := range files {
+		entry, err := readMarkdownImport(file)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// readMarkdownImport parses one markdown file. The creation time comes from
+// the frontmatter, then the file's date/time path, then its modification time.
+func readMarkdownImport(path string) (*models.JournalEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	yamlStr, body := mdstore.ParseFrontmatter(string(data))
+	var fm importFrontmatter
+	if yamlStr != "" {
+		if err := yaml.Unmarshal([]byte(yamlStr), &fm); err != nil {
+			return nil, fmt.Errorf("failed to parse frontmatter in %s: %w", path, err)
+		}
+	} else {
+		body = string(data)
+	}
+
+	entry := &models.JournalEntry{
+		Sections: models.ParseSections(body),
+		Type:     fm.Type,
+		FilePath: path,
+	}
+	if id, err := uuid.Parse(fm.ID); err == nil {
+		entry.ID = id
+	}
+	if thoughtID, err := uuid.Parse(fm.ThoughtID); err == nil {
+		entry.ThoughtID = thoughtID
+	}
+	entry.LinkedIDs = parseLinkedIDs(fm.LinkedIDs)
+	entry.EntryMeta = models.EntryMeta{
+		Tags:       fm.Tags,
+		Mood:       fm.Mood,
+		Valence:    fm.Valence,
+		Agent:      fm.Agent,
+		Repo:       fm.Repo,
+		Branch:     fm.Branch,
+		Commit:     fm.Commit,
+		Dirty:      fm.Dirty,
+		Attributes: fm.Attributes,
+	}
+
+	for _, value := range []string{fm.Date, fm.CreatedAt} {
+		if t, err := mdstore.ParseTime(value); err == nil {
+			entry.CreatedAt = t
+			break
+		}
+	}
+	if entry.CreatedAt.IsZero() && fm.Timestamp > 0 {
+		entry.CreatedAt = time.UnixMilli(fm.Timestamp)
+	}
+	if entry.CreatedAt.IsZero() {
+		if m := legacyEntryName.FindStringSubmatch(filepath.ToSlash(path)); m != nil {
+			if t, err := time.ParseInLocation("2006-01-02 15-04-05", m[1]+" "+m[2], time.Local); err == nil {
+				entry.CreatedAt = t
+			}
+		}
+	}
+	if entry.CreatedAt.IsZero() {
+		if info, err := os.Stat(path); err == nil {
+			entry.CreatedAt = info.ModTime()
+		}
+	}
+	return entry, nil
+}
+
+// CSVImporter reads a CSV file with a header row. Mapping maps entry fields
+// (id, created_at, type, tags, mood, valence, agent, repo, branch, commit) and
+// section names to column headers. Columns whose header already names a field
+// or section are mapped without being listed.
+type CSVImporter struct {
+	Mapping map[string]string
+}
+
+// csvFields are the non-section entry fields a CSV column can fill.
+var csvFields = map[string]bool{
+	"id": true, "created_at": true, "type": true, "tags": true, "mood": true,
+	"valence": true, "agent": true, "repo": true, "branch": true, "commit": true,
+}
+
+// Read turns each data row into one entry.
+func (c CSVImporter) Read(path string) ([]*models.JournalEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1
+	header, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header from %s: %w", path, err)
+	}
+	columns, err := c.columns(header)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*models.JournalEntry
+	now := time.Now()
+	for line := 2; ; line++ {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		entry := &models.JournalEntry{
+			Sections: make(map[string]string),
+			FilePath: fmt.Sprintf("%s:%d", path, line),
+		}
+		for field, col := range columns {
+			if col >= len(record) {
+				continue
+			}
+			value := strings.TrimSpace(record[col])
+			if value == "" {
+				continue
+			}
+			if err := setCSVField(entry, field, value, now); err != nil {
+				return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// columns resolves the mapping against the header to field -> column index.
+func (c CSVImporter) columns(header []string) (map[string]int, error) {
+	byName := make(map[string]int, len(header))
+	for i, name := range header {
+		byName[strings.TrimSpace(name)] = i
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		key := models.SectionKey(name)
+		if csvFields[key] || models.IsValidSection(key) {
+			columns[key] = i
+		}
+	}
+	for field, column := range c.Mapping {
+		if !csvFields[field] && !models.IsValidSection(field) {
+			return nil, fmt.Errorf("unknown field %q in CSV mapping: use id, created_at, type, tags, mood, valence, agent, repo, branch, commit, or a section (%s)", field, models.ValidSectionList())
+		}
+		i, ok := byName[column]
+		if !ok {
+			return nil, fmt.Errorf("CSV has no column %q for %s", column, field)
+		}
+		columns[field] = i
+	}
+	return columns, nil
+}
+
+// setCSVField stores one CSV cell in the entry field it maps to.
+func setCSVField(entry *models.JournalEntry, field, value string, now time.Time) error {
+	switch field {
+	case "id":
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return fmt.Errorf("invalid id %q", value)
+		}
+		entry.ID = id
+	case "created_at":
+		if t, err := mdstore.ParseTime(value); err == nil {
+			entry.CreatedAt = t
+			return nil
+		}
+		r, err := query.ParseDate(value, now)
+		if err != nil {
+			return fmt.Errorf("invalid created_at: %w", err)
+		}
+		entry.CreatedAt = r.Start
+	case "type":
+		entry.Type = strings.ToLower(value)
+	case "tags":
+		entry.Tags = strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ';' })
+	case "mood":
+		entry.Mood = value
+	case "valence":
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid valence %q", value)
+		}
+		entry.Valence = &v
+	case "agent":
+		entry.Agent = value
+	case "repo":
+		entry.Repo = value
+	case "branch":
+		entry.Branch = value
+	case "commit":
+		entry.Commit = value
+	default:
+		entry.Sections[field] = value
+	}
+	return nil
+}
+
+// ImportOptions configures ImportEntries.
+type ImportOptions struct {
+	DefaultType string // journal for entries that carry no type: "user" (default), "project", or a configured one
+	DryRun      bool   // report what would be imported without writing
+}
+
+// ImportReport summarizes an import.
+type ImportReport struct {
+	Read             int                    // entries read from the source
+	Imported         []*models.JournalEntry // entries written, or that would be with DryRun
+	DuplicateID      int                    // skipped: an entry with the same ID exists
+	DuplicateContent int                    // skipped: an entry with the same sections exists
+	Invalid          []string               // skipped entries, as "source: reason"
+	Redacted         []string               // imported entries the redactor changed or flagged, as "source: findings"
+}
+
+// ImportEntries writes entries read by an Importer, keeping their IDs and
+// creation times. Entries whose ID or sections match an existing entry, or an
+// earlier entry in the same import, are skipped as duplicates. Imported
+// entries are redacted as written ones are, and in block mode nothing is
+// imported if any entry holds sensitive text; no git context is captured.
+func (s *JournalMDStore) ImportEntries(entries []*models.JournalEntry, opts ImportOptions) (*ImportReport, error) {
+	if opts.DefaultType == "" {
+		opts.DefaultType = "user"
+	}
+	if _, ok := s.journalRoot(opts.DefaultType); !ok {
+		return nil, fmt.Errorf("invalid type %q: must be one of: %s", opts.DefaultType, strings.Join(s.Journals(), ", "))
+	}
+
+	// Refuse blocked text before importing anything, as WriteSections does
+	if s.redactor.Mode() == redact.ModeBlock {
+		for _, entry := range entries {
+			if _, _, err := s.redactor.Sections(entry.Sections); err != nil {
+				return nil, fmt.Errorf("failed to import %s: %w", entry.FilePath, err)
+			}
+		}
+	}
+
+	refs, err := s.indexedEntries("all", "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list existing entries: %w", err)
+	}
+	ids := make(map[uuid.UUID]bool, len(refs))
+	paths := make([]string, len(refs))
+	for i, ref := range refs {
+		ids[ref.id()] = true
+		paths[i] = ref.path
+	}
+	hashes := make(map[string]bool, len(refs))
+	for _, existing := range s.readEntryFiles(paths) {
+		hashes[contentHash(existing.Sections)] = true
+	}
+
+	report := &ImportReport{Read: len(entries)}
+	var written []string // roots to refresh once the entries are stored
+	for _, entry := range entries {
+		source := entry.FilePath
+		if entry.Type == "" {
+			entry.Type = opts.DefaultType
+		}
+		entry.Tags = models.NormalizeTags(entry.Tags)
+		if _, ok := s.journalRoot(entry.Type); !ok {
+			report.Invalid = append(report.Invalid, fmt.Sprintf("%s: unknown journal %q", source, entry.Type))
+			continue
+		}
+		if reason := importProblem(entry); reason != "" {
+			report.Invalid = append(report.Invalid, fmt.Sprintf("%s: %s", source, reason))
+			continue
+		}
+		if entry.ID == uuid.Nil {
+			entry.ID = uuid.New()
+		} else if ids[entry.ID] {
+			report.DuplicateID++
+			continue
+		}
+		// Hash the redacted text, which is what an earlier import stored
+		if err := s.redactEntry(entry); err != nil {
+			return report, fmt.Errorf("failed to import %s: %w", source, err)
+		}
+		hash := contentHash(entry.Sections)
+		if hashes[hash] {
+			report.DuplicateContent++
+			continue
+		}
+		ids[entry.ID] = true
+		hashes[hash] = true
+
+		// Store under the local date, as entries written here are
+		entry.CreatedAt = entry.CreatedAt.Local()
+		entry.FilePath = ""
+		entry.DeletedAt, entry.DeleteReason = time.Time{}, ""
+		if !opts.DryRun {
+			root, err := s.storeEntry(entry)
+			if err != nil {
+				return report, fmt.Errorf("failed to import %s: %w", source, err)
+			}
+			if !slices.Contains(written, root) {
+				written = append(written, root)
+			}
+		}
+		report.Imported = append(report.Imported, entry)
+		if lines := redact.Report(entry.Redactions); len(lines) > 0 {
+			report.Redacted = append(report.Redacted, fmt.Sprintf("%s: %s", source, strings.Join(lines, "; ")))
+		}
+	}
+	for _, root := range written {
+		if err := s.withIndex(root, nil); err != nil {
+			return report, fmt.Errorf("failed to update index: %w", err)
+		}
+	}
+	return report, nil
+}
+
+// importProblem explains why an entry cannot be imported, or returns "".
+// The caller checks that its journal exists.
+func importProblem(entry *models.JournalEntry) string {
+	if entry.CreatedAt.IsZero() {
+		return "no creation time"
+	}
+	if len(sectionOrder(entry.Sections)) == 0 {
+		return "no sections"
+	}
+	for name := range entry.Sections {
+		if !sectionNamePattern.MatchString(name) {
+			return fmt.Sprintf("invalid section name %q", name)
+		}
+	}
+	if err := entry.EntryMeta.Validate(); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+// sectionNamePattern matches the snake_case keys section headings become.
+var sectionNamePattern = regexp.MustCompile(`^[a-z0-9]+(_[a-z0-9]+)*$`)
+
+// contentHash fingerprints an entry's non-empty sections, ignoring surrounding
+// whitespace, so the same note imported twice under different IDs is caught.
+func contentHash(sections map[string]string) string {
+	h := sha256.New()
+	for _, name := range sectionOrder(sections) {
+		fmt.Fprintf(h, "%s\x00%s\x00", name, strings.TrimSpace(sections[name]))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
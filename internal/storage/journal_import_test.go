@@ -0,0 +1,209 @@
+// ABOUTME: Tests for journal import.
+// ABOUTME: Covers JSONL roundtrips, markdown and legacy layouts, CSV mappings, duplicate detection, and dry runs.
+package storage
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/2389-research/pulse/internal/models"
+)
+
+func newImportStore(t *testing.T) *JournalMDStore {
+	t.Helper()
+	tmpDir := t.TempDir()
+	store, err := NewJournalMDStore(filepath.Join(tmpDir, "project-journal"), filepath.Join(tmpDir, "user-journal"), WithGitContext(false))
+	if err != nil {
+		t.Fatalf("NewJournalMDStore error: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	return store
+}
+
+func TestImportJSONLRoundtrip(t *testing.T) {
+	entries := exportTestEntries()
+	var buf bytes.Buffer
+	if err := WriteJSONL(&buf, entries); err != nil {
+		t.Fatalf("WriteJSONL error: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	read, err := JSONLImporter{}.Read(path)
+	if err != nil {
+		t.Fatalf("Read error: %v", err)
+	}
+	store := newImportStore(t)
+	report, err := store.ImportEntries(read, ImportOptions{})
+	if err != nil {
+		t.Fatalf("ImportEntries error: %v", err)
+	}
+	if report.Read != 2 || len(report.Imported) != 2 {
+		t.Fatalf("expected 2 imported, got %+v", report)
+	}
+
+	listed, err := store.ListEntries(ListOptions{Order: OldestFirst})
+	if err != nil {
+		t.Fatalf("ListEntries error: %v", err)
+	}
+	if len(listed) != 2 {
+		t.Fatalf("expected 2 stored entries, got %d", len(listed))
+	}
+	if listed[0].ID != entries[0].ID || !listed[0].CreatedAt.Equal(entries[0].CreatedAt) || listed[0].ThoughtID != entries[0].ThoughtID {
+		t.Errorf("expected ID, time, and thought to survive import, got %+v", listed[0])
+	}
+	if !strings.Contains(listed[0].FilePath, "2026-03-01") || listed[1].Type != "project" {
+		t.Errorf("expected entries filed by original date and type, got %s %s", listed[0].FilePath, listed[1].Type)
+	}
+
+	// Importing the same file again finds every entry by ID
+	report, err = store.ImportEntries(read, ImportOptions{})
+	if err != nil {
+		t.Fatalf("ImportEntries error: %v", err)
+	}
+	if len(report.Imported) != 0 || report.DuplicateID != 2 {
+		t.Errorf("expected 2 ID duplicates on reimport, got %+v", report)
+	}
+}
+
+func TestImportMarkdownLayouts(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		// An older private-journal entry: millisecond timestamp, no ID or type
+		"2025-05-31/14-15-30-123456.md": "---\ntitle: \"2:15:30 PM - May 31, 2025\"\ntimestamp: 1748700930123\n---\n\n## Feelings\nNervous about the launch\n",
+		// No frontmatter: the time comes from the path
+		"2025-06-01/09-00-00-000000.md": "## Technical Insights\nUse a context timeout\n",
+		// No sections at all
+		"notes/readme.md": "just some text\n",
+		// The trash is never imported
+		".trash/2025-06-01/10-00-00-000000.md": "## Feelings\ngone\n",
+	}
+	for rel, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	read, err := MarkdownImporter{}.Read(dir)
+	if err != nil {
+		t.Fatalf("Read error: %v", err)
+	}
+	if len(read) != 3 {
+		t.Fatalf("expected 3 markdown files outside the trash, got %d", len(read))
+	}
+
+	store := newImportStore(t)
+	report, err := store.ImportEntries(read, ImportOptions{DefaultType: "project"})
+	if err != nil {
+		t.Fatalf("ImportEntries error: %v", err)
+	}
+	if len(report.Imported) != 2 || len(report.Invalid) != 1 || !strings.Contains(report.Invalid[0], "no sections") {
+		t.Fatalf("expected 2 imported and 1 invalid, got %+v", report)
+	}
+
+	byText := make(map[string]*models.JournalEntry)
+	for _, entry := range report.Imported {
+		byText[entry.Sections["feelings"]+entry.Sections["technical_insights"]] = entry
+	}
+	legacy := byText["Nervous about the launch"]
+	if legacy == nil || !legacy.CreatedAt.Equal(time.UnixMilli(1748700930123)) || legacy.Type != "project" {
+		t.Errorf("expected the legacy timestamp and default type, got %+v", legacy)
+	}
+	fromPath := byText["Use a context timeout"]
+	if fromPath == nil || !fromPath.CreatedAt.Equal(time.Date(2025, 6, 1, 9, 0, 0, 0, time.Local)) {
+		t.Errorf("expected the time from the file path, got %+v", fromPath)
+	}
+
+	// New IDs are generated for files without one, so a second import is
+	// caught by content instead
+	read, err = MarkdownImporter{}.Read(dir)
+	if err != nil {
+		t.Fatalf("Read error: %v", err)
+	}
+	report, err = store.ImportEntries(read, ImportOptions{})
+	if err != nil {
+		t.Fatalf("ImportEntries error: %v", err)
+	}
+	if len(report.Imported) != 0 || report.DuplicateContent != 2 {
+		t.Errorf("expected 2 content duplicates, got %+v", report)
+	}
+}
+
+func TestImportCSVMappingAndDryRun(t *testing.T) {
+	dir := t.TempDir()
+	header := "When,Body,Feelings,Labels\n"
+	good := "2026-02-01 08:30,Fixed the flaky test,relieved,\"ci, tests\"\n"
+	mapping := map[string]string{"created_at": "When", "project_notes": "Body", "tags": "Labels"}
+
+	badPath := filepath.Join(dir, "bad.csv")
+	if err := os.WriteFile(badPath, []byte(header+good+"not a date,Broken row,,\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (CSVImporter{Mapping: map[string]string{"nonsense": "Body"}}).Read(badPath); err == nil {
+		t.Error("expected an unknown mapping field to be rejected")
+	}
+	if _, err := (CSVImporter{Mapping: mapping}).Read(badPath); err == nil || !strings.Contains(err.Error(), ":3:") {
+		t.Errorf("expected the bad date to be reported with its line, got %v", err)
+	}
+
+	path := filepath.Join(dir, "notes.csv")
+	if err := os.WriteFile(path, []byte(header+good), 0644); err != nil {
+		t.Fatal(err)
+	}
+	read, err := CSVImporter{Mapping: mapping}.Read(path)
+	if err != nil {
+		t.Fatalf("Read error: %v", err)
+	}
+	if len(read) != 1 {
+		t.Fatalf("expected 1 row, got %d", len(read))
+	}
+	entry := read[0]
+	if entry.Sections["project_notes"] != "Fixed the flaky test" || entry.Sections["feelings"] != "relieved" {
+		t.Errorf("expected mapped and self-named columns as sections, got %v", entry.Sections)
+	}
+	if !entry.CreatedAt.Equal(time.Date(2026, 2, 1, 8, 30, 0, 0, time.Local)) {
+		t.Errorf("expected local created_at, got %v", entry.CreatedAt)
+	}
+
+	store := newImportStore(t)
+	report, err := store.ImportEntries(read, ImportOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("ImportEntries error: %v", err)
+	}
+	if len(report.Imported) != 1 || strings.Join(report.Imported[0].Tags, ",") != "ci,tests" {
+		t.Errorf("expected one entry with normalized tags, got %+v", report.Imported)
+	}
+	listed, err := store.ListEntries(ListOptions{})
+	if err != nil {
+		t.Fatalf("ListEntries error: %v", err)
+	}
+	if len(listed) != 0 {
+		t.Errorf("dry run must not write entries, found %d", len(listed))
+	}
+}
+
+func TestDetectImportFormat(t *testing.T) {
+	dir := t.TempDir()
+	tests := map[string]string{
+		dir:                   ImportMarkdown,
+		"entry.md":            ImportMarkdown,
+		"notes.CSV":           ImportCSV,
+		"export.jsonl":        ImportJSONL,
+		"export-no-extension": ImportJSONL,
+	}
+	for path, want := range tests {
+		if got := DetectImportFormat(path); got != want {
+			t.Errorf("DetectImportFormat(%q) = %s, want %s", path, got, want)
+		}
+	}
+}
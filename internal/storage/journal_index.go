@@ -0,0 +1,745 @@
+// ABOUTME: Persistent inverted index stored in each journal root as _index.json.
+// ABOUTME: Maps terms, dates, types, and sections to entry paths, keeps compact sidecar vectors, and refreshes incrementally from mtimes.
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/harperreed/mdstore"
+
+	"github.com/2389-research/pulse/internal/embeddings"
+	"github.com/2389-research/pulse/internal/models"
+	"github.com/2389-research/pulse/internal/query"
+)
+
+// quantizationSlack widens the similarity needed to become a candidate, as
+// indexed vectors are rounded; embeddings.Search applies the exact threshold.
+const quantizationSlack = 0.02
+
+// indexFileName is the name of the index file inside each journal root.
+const indexFileName = "_index.json"
+
+// indexVersion is bumped whenever the on-disk index layout changes; older
+// indexes are discarded and rebuilt.
+const indexVersion = 6
+
+// journalIndex is the on-disk inverted index for one journal root.
+// Paths are relative to the root, e.g. "2026-03-28/10-15-00-000000-abcd1234.md".
+// Archived entries are indexed under the path they had before archiving.
+type journalIndex struct {
+	Version  int                      `json:"version"`
+	Dirs     map[string]int64         `json:"dirs"`     // date dir -> mtime (unix nanos) when last scanned
+	Archives map[string]int64         `json:"archives"` // date dir -> bundle mtime (unix nanos) when last read
+	Entries  map[string]*indexedEntry `json:"entries"`  // path -> entry metadata
+	Terms    map[string][]string      `json:"terms"`    // token -> paths
+	Dates    map[string][]string      `json:"dates"`    // date dir -> paths
+	Types    map[string][]string      `json:"types"`    // frontmatter type -> paths
+	Sections map[string][]string      `json:"sections"` // section name -> paths
+}
+
+// indexedEntry is the per-entry metadata kept in the index.
+type indexedEntry struct {
+	ID        string           `json:"id"`
+	CreatedAt time.Time        `json:"created_at"`
+	Type      string           `json:"type"`
+	ThoughtID string           `json:"thought_id,omitempty"`
+	Meta      models.EntryMeta `json:"meta"` // filterable metadata, so filters need not parse entries
+	Sections  []string         `json:"sections"`
+	Terms     []string         `json:"terms,omitempty"`     // distinct tokens, so removal touches only their postings
+	Length    int              `json:"length"`              // token count across all sections, for BM25
+	Encrypted bool             `json:"encrypted,omitempty"` // body encrypted; sections and terms are not indexed
+	Archived  bool             `json:"archived,omitempty"`  // read from the day's bundle in .archive/
+	Size      int64            `json:"size"`
+	ModTime   int64            `json:"mod_time"`
+
+	// The sidecar's vectors, packed by embeddings.Quantize, so search can
+	// pick semantic candidates without reading every sidecar. Keyed by section,
+	// or "" for the whole entry when the sidecar has no section vectors.
+	Vectors       map[string]string `json:"vectors,omitempty"`
+	VectorModel   string            `json:"vector_model,omitempty"`
+	VectorModTime int64             `json:"vector_mod_time,omitempty"` // sidecar mtime when read; 0 without one
+}
+
+// readEmbedding reads a sidecar; tests swap it to count reads.
+var readEmbedding = embeddings.ReadEmbedding
+
+// loadVectors records the vectors of the sidecar for the entry at path, whose
+// mtime is mtime (0 when there is none).
+func (m *indexedEntry) loadVectors(path string, mtime int64) {
+	m.Vectors, m.VectorModel, m.VectorModTime = nil, "", mtime
+	if mtime == 0 {
+		return
+	}
+	emb, err := readEmbedding(path)
+	if err != nil {
+		return
+	}
+	m.VectorModel = emb.Model
+	m.Vectors = make(map[string]string, len(emb.SectionVectors))
+	for name, vec := range emb.SectionVectors {
+		m.Vectors[name] = embeddings.Quantize(vec)
+	}
+	if len(m.Vectors) == 0 {
+		m.Vectors[""] = embeddings.Quantize(emb.Vector)
+	}
+}
+
+// embedding rebuilds the indexed vectors as an embedding for embedder, or
+// returns nil if there are none or another model made them.
+func (m *indexedEntry) embedding(embedder embeddings.Embedder) *models.Embedding {
+	if len(m.Vectors) == 0 || (m.VectorModel != "" && m.VectorModel != embedder.Name()) {
+		return nil
+	}
+	emb := &models.Embedding{Model: m.VectorModel, Sections: m.Sections}
+	for name, packed := range m.Vectors {
+		vec, err := embeddings.Dequantize(packed)
+		if err != nil || len(vec) != embedder.Dimension() {
+			return nil
+		}
+		if name == "" {
+			emb.Vector = vec
+			continue
+		}
+		if emb.SectionVectors == nil {
+			emb.SectionVectors = make(map[string][]float32)
+		}
+		emb.SectionVectors[name] = vec
+	}
+	return emb
+}
+
+// sidecarModTime returns the mtime of the sidecar for the entry at path, or 0.
+func sidecarModTime(path string) int64 {
+	info, err := os.Stat(embeddings.EmbeddingPath(path))
+	if err != nil {
+		return 0
+	}
+	return info.ModTime().UnixNano()
+}
+
+// indexedRef pairs index metadata with the entry's absolute path.
+type indexedRef struct {
+	path string
+	meta *indexedEntry
+}
+
+// id parses the entry's ID, yielding uuid.Nil for a malformed one.
+func (r indexedRef) id() uuid.UUID {
+	id, _ := uuid.Parse(r.meta.ID)
+	return id
+}
+
+// newJournalIndex returns an empty index.
+func newJournalIndex() *journalIndex {
+	return &journalIndex{
+		Version:  indexVersion,
+		Dirs:     make(map[string]int64),
+		Archives: make(map[string]int64),
+		Entries:  make(map[string]*indexedEntry),
+		Terms:    make(map[string][]string),
+		Dates:    make(map[string][]string),
+		Types:    make(map[string][]string),
+		Sections: make(map[string][]string),
+	}
+}
+
+// loadJournalIndex reads the index file for root. A missing, unreadable, or
+// outdated index yields an empty one that the next refresh fills in.
+func loadJournalIndex(root string) *journalIndex {
+	data, err := os.ReadFile(filepath.Join(root, indexFileName))
+	if err != nil {
+		return newJournalIndex()
+	}
+	var idx journalIndex
+	if err := json.Unmarshal(data, &idx); err != nil || idx.Version != indexVersion {
+		return newJournalIndex()
+	}
+	if idx.Dirs == nil || idx.Archives == nil || idx.Entries == nil {
+		return newJournalIndex()
+	}
+	for _, m := range []*map[string][]string{&idx.Terms, &idx.Dates, &idx.Types, &idx.Sections} {
+		if *m == nil {
+			*m = make(map[string][]string)
+		}
+	}
+	return &idx
+}
+
+// save writes the index atomically into root.
+func (idx *journalIndex) save(root string) error {
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return fmt.Errorf("failed to marshal index: %w", err)
+	}
+	return mdstore.AtomicWrite(filepath.Join(root, indexFileName), data)
+}
+
+// saveIndex saves an index; tests swap it to count saves.
+var saveIndex = (*journalIndex).save
+
+// refresh brings the index up to date with the files under root. Only date
+// directories whose mtime changed since the last scan are rescanned, and only
+// files whose size or mtime changed are re-parsed; bundles are re-read
+// whole when their mtime changes. Returns true if the index changed.
+func (idx *journalIndex) refresh(root string) (bool, error) {
+	dirEntries, err := os.ReadDir(root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			changed := len(idx.Entries) > 0 || len(idx.Dirs) > 0
+			if changed {
+				*idx = *newJournalIndex()
+			}
+			return changed, nil
+		}
+		return false, err
+	}
+
+	changed := false
+	present := make(map[string]bool)
+	for _, d := range dirEntries {
+		if !d.IsDir() {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", d.Name()); err != nil {
+			continue
+		}
+		present[d.Name()] = true
+
+		// Stat before reading so a write racing with the scan leaves a stale
+		// mtime behind and is picked up next time.
+		info, err := d.Info()
+		if err != nil {
+			continue
+		}
+		mtime := info.ModTime().UnixNano()
+		if last, ok := idx.Dirs[d.Name()]; ok && last == mtime {
+			continue
+		}
+		if err := idx.rescanDir(root, d.Name()); err != nil {
+			continue
+		}
+		idx.Dirs[d.Name()] = mtime
+		changed = true
+	}
+
+	for dir := range idx.Dirs {
+		if present[dir] {
+			continue
+		}
+		for _, rel := range append([]string(nil), idx.Dates[dir]...) {
+			if !idx.Entries[rel].Archived {
+				idx.remove(rel)
+			}
+		}
+		delete(idx.Dirs, dir)
+		changed = true
+	}
+
+	archivesChanged, err := idx.refreshArchives(root)
+	if err != nil {
+		return changed, err
+	}
+	return changed || archivesChanged, nil
+}
+
+// refreshArchives re-reads the bundles under root whose mtime changed since
+// the last refresh and drops the entries of bundles that are gone. Returns
+// true if the index changed.
+func (idx *journalIndex) refreshArchives(root string) (bool, error) {
+	days, err := listBundles(root)
+	if err != nil {
+		return false, err
+	}
+
+	changed := false
+	present := make(map[string]bool)
+	for _, day := range days {
+		present[day] = true
+		info, err := os.Stat(bundlePath(root, day))
+		if err != nil {
+			continue
+		}
+		mtime := info.ModTime().UnixNano()
+		if last, ok := idx.Archives[day]; ok && last == mtime {
+			continue
+		}
+		if err := idx.rescanBundle(root, day); err != nil {
+			continue
+		}
+		idx.Archives[day] = mtime
+		changed = true
+	}
+
+	for day := range idx.Archives {
+		if present[day] {
+			continue
+		}
+		for _, rel := range append([]string(nil), idx.Dates[day]...) {
+			if idx.Entries[rel].Archived {
+				idx.remove(rel)
+			}
+		}
+		delete(idx.Archives, day)
+		changed = true
+	}
+	return changed, nil
+}
+
+// rescanBundle re-indexes the entries in root's bundle for day. An entry
+// that also exists as a live file keeps its live version.
+func (idx *journalIndex) rescanBundle(root, day string) error {
+	bundle := bundlePath(root, day)
+	files, err := readBundle(bundle)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(bundle)
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool)
+	for _, file := range files {
+		if !strings.HasSuffix(file.name, ".md") || !strings.HasPrefix(file.name, day+"/") {
+			continue
+		}
+		if existing, ok := idx.Entries[file.name]; ok && !existing.Archived {
+			continue
+		}
+		seen[file.name] = true
+		entry, err := parseJournalEntry(filepath.Join(root, filepath.FromSlash(file.name)), string(file.data), nil)
+		if err != nil {
+			idx.remove(file.name)
+			continue
+		}
+		idx.add(file.name, entry, info)
+		idx.Entries[file.name].Archived = true
+	}
+
+	for _, rel := range append([]string(nil), idx.Dates[day]...) {
+		if idx.Entries[rel].Archived && !seen[rel] {
+			idx.remove(rel)
+		}
+	}
+	return nil
+}
+
+// rescanDir re-indexes the markdown files in one date directory.
+func (idx *journalIndex) rescanDir(root, dir string) error {
+	files, err := os.ReadDir(filepath.Join(root, dir))
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool)
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".md") {
+			continue
+		}
+		info, err := file.Info()
+		if err != nil {
+			continue
+		}
+		rel := dir + "/" + file.Name()
+		seen[rel] = true
+
+		path := filepath.Join(root, dir, file.Name())
+		vectorTime := sidecarModTime(path)
+		if existing, ok := idx.Entries[rel]; ok &&
+			existing.Size == info.Size() && existing.ModTime == info.ModTime().UnixNano() {
+			// Sidecars are written after their entry and by backfills
+			if existing.VectorModTime != vectorTime {
+				existing.loadVectors(path, vectorTime)
+			}
+			continue
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		entry, err := parseJournalEntry(path, string(data), nil)
+		if err != nil {
+			idx.remove(rel)
+			continue
+		}
+		idx.add(rel, entry, info)
+		idx.Entries[rel].loadVectors(path, vectorTime)
+	}
+
+	for _, rel := range append([]string(nil), idx.Dates[dir]...) {
+		if !seen[rel] && !idx.Entries[rel].Archived {
+			idx.remove(rel)
+		}
+	}
+	return nil
+}
+
+// add indexes an entry under rel, replacing any previous version. Entries
+// are parsed without a passphrase, so an encrypted entry contributes only its
+// frontmatter and the index never holds words from an encrypted body.
+func (idx *journalIndex) add(rel string, entry *models.JournalEntry, info os.FileInfo) {
+	idx.remove(rel)
+
+	var sections []string
+	var text []string
+	for name, content := range entry.Sections {
+		sections = append(sections, name)
+		text = append(text, content)
+	}
+	sort.Strings(sections)
+	tokens := embeddings.Tokenize(strings.Join(text, "\n"))
+
+	meta := &indexedEntry{
+		ID:        entry.ID.String(),
+		CreatedAt: entry.CreatedAt,
+		Type:      entry.Type,
+		Meta:      entry.EntryMeta,
+		Sections:  sections,
+		Length:    len(tokens),
+		Encrypted: entry.Encrypted,
+		Size:      info.Size(),
+		ModTime:   info.ModTime().UnixNano(),
+	}
+	if entry.ThoughtID != uuid.Nil {
+		meta.ThoughtID = entry.ThoughtID.String()
+	}
+	idx.Entries[rel] = meta
+
+	dir := rel[:strings.Index(rel, "/")]
+	idx.Dates[dir] = append(idx.Dates[dir], rel)
+	idx.Types[entry.Type] = append(idx.Types[entry.Type], rel)
+	for _, name := range sections {
+		idx.Sections[name] = append(idx.Sections[name], rel)
+	}
+	seen := make(map[string]bool)
+	for _, tok := range tokens {
+		if seen[tok] {
+			continue
+		}
+		seen[tok] = true
+		meta.Terms = append(meta.Terms, tok)
+		idx.Terms[tok] = append(idx.Terms[tok], rel)
+	}
+}
+
+// remove drops rel from the entry table and from the posting lists its
+// metadata names.
+func (idx *journalIndex) remove(rel string) {
+	meta, ok := idx.Entries[rel]
+	if !ok {
+		return
+	}
+	delete(idx.Entries, rel)
+	unpost(idx.Dates, rel[:strings.Index(rel, "/")], rel)
+	unpost(idx.Types, meta.Type, rel)
+	for _, name := range meta.Sections {
+		unpost(idx.Sections, name, rel)
+	}
+	for _, tok := range meta.Terms {
+		unpost(idx.Terms, tok, rel)
+	}
+}
+
+// unpost removes rel from postings[key], dropping the key once it is empty.
+func unpost(postings map[string][]string, key, rel string) {
+	paths := postings[key]
+	for i, p := range paths {
+		if p == rel {
+			paths = append(paths[:i], paths[i+1:]...)
+			break
+		}
+	}
+	if len(paths) == 0 {
+		delete(postings, key)
+	} else {
+		postings[key] = paths
+	}
+}
+
+// termMatches returns the paths whose entries contain a token equal to or
+// starting with term, so partial words still find candidates.
+func (idx *journalIndex) termMatches(term string) []string {
+	var out []string
+	for tok, paths := range idx.Terms {
+		if strings.HasPrefix(tok, term) {
+			out = append(out, paths...)
+		}
+	}
+	return out
+}
+
+// withIndex runs fn with the refreshed index for root, saving it first if the
+// refresh changed anything. Indexes are cached per root for the store's lifetime.
+func (s *JournalMDStore) withIndex(root string, fn func(*journalIndex) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	idx, ok := s.indexes[root]
+	if !ok {
+		idx = loadJournalIndex(root)
+		s.indexes[root] = idx
+	}
+
+	changed, err := idx.refresh(root)
+	if err != nil {
+		return fmt.Errorf("failed to refresh index for %s: %w", root, err)
+	}
+	// Never create a root just to hold an empty index
+	if _, statErr := os.Stat(root); changed && statErr == nil {
+		if err := saveIndex(idx, root); err != nil {
+			return fmt.Errorf("failed to save index for %s: %w", root, err)
+		}
+	}
+
+	if fn == nil {
+		return nil
+	}
+	return fn(idx)
+}
+
+// Reindex discards and rebuilds the index in every journal root from a full
+// scan. Returns the number of entries indexed.
+func (s *JournalMDStore) Reindex() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	total := 0
+	for _, root := range s.allRoots() {
+		idx, err := buildJournalIndex(root)
+		if err != nil {
+			return total, fmt.Errorf("failed to rebuild index for %s: %w", root, err)
+		}
+		s.indexes[root] = idx
+		total += len(idx.Entries)
+		if _, err := os.Stat(root); err == nil {
+			if err := saveIndex(idx, root); err != nil {
+				return total, fmt.Errorf("failed to save index for %s: %w", root, err)
+			}
+		}
+	}
+	return total, nil
+}
+
+// buildJournalIndex indexes every entry under root, including archived ones,
+// from scratch.
+func buildJournalIndex(root string) (*journalIndex, error) {
+	idx := newJournalIndex()
+
+	// Record directory mtimes before listing, so anything written during the
+	// scan triggers a rescan on the next refresh.
+	dirEntries, err := os.ReadDir(root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return idx, nil
+		}
+		return nil, err
+	}
+	for _, d := range dirEntries {
+		if !d.IsDir() {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", d.Name()); err != nil {
+			continue
+		}
+		if info, err := d.Info(); err == nil {
+			idx.Dirs[d.Name()] = info.ModTime().UnixNano()
+		}
+	}
+
+	entries, err := listEntriesInRoot(root, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		rel, err := filepath.Rel(root, entry.FilePath)
+		if err != nil {
+			continue
+		}
+		rel = filepath.ToSlash(rel)
+		if _, ok := idx.Dirs[filepath.Dir(filepath.FromSlash(rel))]; !ok {
+			continue
+		}
+		info, err := os.Stat(entry.FilePath)
+		if err != nil {
+			continue
+		}
+		idx.add(rel, entry, info)
+		idx.Entries[rel].loadVectors(entry.FilePath, sidecarModTime(entry.FilePath))
+	}
+	if _, err := idx.refreshArchives(root); err != nil {
+		return nil, err
+	}
+	return idx, nil
+}
+
+// indexedEntries returns index metadata for the roots covered by entryType,
+// keeping only entries in date directories on or after sinceDir (if set).
+func (s *JournalMDStore) indexedEntries(entryType string, sinceDir string) ([]indexedRef, error) {
+	roots, journals, err := s.rootsFor(entryType)
+	if err != nil {
+		return nil, err
+	}
+	var refs []indexedRef
+	for _, root := range roots {
+		err := s.withIndex(root, func(idx *journalIndex) error {
+			for dir, paths := range idx.Dates {
+				if sinceDir != "" && dir < sinceDir {
+					continue
+				}
+				for _, rel := range paths {
+					if !inJournals(idx.Entries[rel].Type, journals) {
+						continue
+					}
+					refs = append(refs, indexedRef{path: filepath.Join(root, filepath.FromSlash(rel)), meta: idx.Entries[rel]})
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return refs, nil
+}
+
+// searchCandidates returns the entries that can match query and BM25 corpus
+// statistics for the searched roots. Candidates are entries sharing a term
+// (or term prefix) with the query plus, when an embedder is configured, entries
+// whose indexed vectors are similar enough to the query. No sidecar is read;
+// embeddings.Search reads those of the candidates to score them exactly.
+func (s *JournalMDStore) searchCandidates(q *query.Query, opts embeddings.SearchOptions) ([]string, *embeddings.CorpusStats, error) {
+	text := q.Text()
+	terms := embeddings.Tokenize(text)
+	stats := &embeddings.CorpusStats{DocFreq: make(map[string]int)}
+	var totalLen int
+	var candidates []string
+	seen := make(map[string]bool)
+
+	// Boolean queries are matched exactly by embeddings.Search. The term
+	// postings can only narrow them when every match must contain one of
+	// the query's indexed words; otherwise every entry is a candidate.
+	scanAll := len(terms) == 0
+	if q.Boolean() {
+		scanAll = scanAll || !q.RequiresTerm() || hasUnindexedWord(text)
+	}
+	sections := opts.Sections
+	if len(sections) == 0 {
+		sections = q.Sections()
+	}
+
+	var queryVec []float32
+	if s.embedder != nil && !q.Boolean() && len(terms) > 0 {
+		vec, err := s.embedder.Embed(text)
+		if err != nil {
+			return nil, nil, err
+		}
+		queryVec = vec
+	}
+
+	roots, journals, err := s.rootsFor(opts.Type)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, root := range roots {
+		err := s.withIndex(root, func(idx *journalIndex) error {
+			stats.DocCount += len(idx.Entries)
+			for _, meta := range idx.Entries {
+				totalLen += meta.Length
+			}
+			for _, term := range terms {
+				stats.DocFreq[term] += len(idx.Terms[term])
+			}
+
+			addCandidate := func(rel string) {
+				meta, ok := idx.Entries[rel]
+				if !ok || seen[root+rel] || !inJournals(meta.Type, journals) || (!meta.Encrypted && !hasAnySection(meta.Sections, sections)) {
+					return
+				}
+				if meta.CreatedAt.Before(opts.Since) || !opts.Filter.Matches(meta.Meta) {
+					return
+				}
+				seen[root+rel] = true
+				candidates = append(candidates, filepath.Join(root, filepath.FromSlash(rel)))
+			}
+
+			for _, term := range terms {
+				for _, rel := range idx.termMatches(term) {
+					addCandidate(rel)
+				}
+			}
+
+			// Substring fallback for queries that tokenize to nothing (e.g. all
+			// stopwords), and full scans for boolean queries
+			if scanAll {
+				for rel := range idx.Entries {
+					addCandidate(rel)
+				}
+			}
+
+			// Encrypted bodies have no postings, so any of them may match
+			if s.cipher != nil {
+				for rel, meta := range idx.Entries {
+					if meta.Encrypted {
+						addCandidate(rel)
+					}
+				}
+			}
+
+			if queryVec != nil {
+				for rel, meta := range idx.Entries {
+					if seen[root+rel] {
+						continue
+					}
+					emb := meta.embedding(s.embedder)
+					if emb == nil {
+						continue
+					}
+					if sim, _, ok := embeddings.BestSectionSimilarity(queryVec, emb, sections); ok && sim >= embeddings.MinSimilarity-quantizationSlack {
+						addCandidate(rel)
+					}
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if stats.DocCount > 0 {
+		stats.AvgLength = float64(totalLen) / float64(stats.DocCount)
+	}
+	return candidates, stats, nil
+}
+
+// hasUnindexedWord reports whether text contains a word the index does not
+// record, such as a stopword, so its postings cannot prove a match.
+func hasUnindexedWord(text string) bool {
+	for _, word := range strings.Fields(text) {
+		if len(embeddings.Tokenize(word)) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// hasAnySection reports whether entrySections includes any of want, or want is empty.
+func hasAnySection(entrySections, want []string) bool {
+	if len(want) == 0 {
+		return true
+	}
+	for _, w := range want {
+		if containsTag(entrySections, w) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,290 @@
+// ABOUTME: Tests for the persistent per-root journal index.
+// ABOUTME: Covers incremental maintenance, direct removal, batched imports, external changes, rebuilds, and index-backed search.
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/2389-research/pulse/internal/embeddings"
+	"github.com/2389-research/pulse/internal/models"
+)
+
+func readIndexFile(t *testing.T, root string) *journalIndex {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(root, indexFileName))
+	if err != nil {
+		t.Fatalf("failed to read index: %v", err)
+	}
+	var idx journalIndex
+	if err := json.Unmarshal(data, &idx); err != nil {
+		t.Fatalf("failed to parse index: %v", err)
+	}
+	return &idx
+}
+
+func TestJournalIndexMaintainedByWriteEntry(t *testing.T) {
+	tmpDir := t.TempDir()
+	userDir := filepath.Join(tmpDir, "user")
+	store, _ := NewJournalMDStore(filepath.Join(tmpDir, "project"), userDir)
+
+	entry := models.NewJournalEntry(map[string]string{"technical_insights": "Goroutine leak in the poller"}, "user")
+	if err := store.WriteEntry(entry); err != nil {
+		t.Fatalf("WriteEntry error: %v", err)
+	}
+
+	idx := readIndexFile(t, userDir)
+	rel, _ := filepath.Rel(userDir, entry.FilePath)
+	rel = filepath.ToSlash(rel)
+
+	meta, ok := idx.Entries[rel]
+	if !ok {
+		t.Fatalf("expected %s in index, got %v", rel, idx.Entries)
+	}
+	if meta.ID != entry.ID.String() {
+		t.Errorf("indexed ID = %s, want %s", meta.ID, entry.ID)
+	}
+	if !containsTag(idx.Terms["goroutine"], rel) {
+		t.Errorf("expected term posting for 'goroutine', got %v", idx.Terms["goroutine"])
+	}
+	if !containsTag(idx.Sections["technical_insights"], rel) {
+		t.Errorf("expected section posting, got %v", idx.Sections)
+	}
+	if !containsTag(idx.Types["user"], rel) {
+		t.Errorf("expected type posting, got %v", idx.Types)
+	}
+	if !containsTag(idx.Dates[entry.CreatedAt.Format("2006-01-02")], rel) {
+		t.Errorf("expected date posting, got %v", idx.Dates)
+	}
+}
+
+func TestJournalIndexPicksUpExternalChanges(t *testing.T) {
+	tmpDir := t.TempDir()
+	projectDir := filepath.Join(tmpDir, "project")
+	userDir := filepath.Join(tmpDir, "user")
+
+	store, _ := NewJournalMDStore(projectDir, userDir)
+	if _, err := store.ListEntries(ListOptions{}); err != nil {
+		t.Fatalf("ListEntries error: %v", err)
+	}
+
+	// A second store (another process) writes into the same root
+	other, _ := NewJournalMDStore(projectDir, userDir)
+	entry := models.NewJournalEntry(map[string]string{"feelings": "written elsewhere"}, "user")
+	if err := other.WriteEntry(entry); err != nil {
+		t.Fatalf("WriteEntry error: %v", err)
+	}
+
+	entries, err := store.ListEntries(ListOptions{})
+	if err != nil {
+		t.Fatalf("ListEntries error: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected external entry to be listed, got %d entries", len(entries))
+	}
+
+	// Deleting the file drops it from the listing and the index
+	if err := os.Remove(entry.FilePath); err != nil {
+		t.Fatalf("failed to remove entry: %v", err)
+	}
+	entries, err = store.ListEntries(ListOptions{})
+	if err != nil {
+		t.Fatalf("ListEntries error: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected deleted entry to disappear, got %d entries", len(entries))
+	}
+	if idx := readIndexFile(t, userDir); len(idx.Entries) != 0 {
+		t.Errorf("expected empty index after delete, got %v", idx.Entries)
+	}
+}
+
+func TestJournalReindexRecoversFromCorruptIndex(t *testing.T) {
+	tmpDir := t.TempDir()
+	userDir := filepath.Join(tmpDir, "user")
+	store, _ := NewJournalMDStore(filepath.Join(tmpDir, "project"), userDir)
+
+	for _, text := range []string{"one", "two", "three"} {
+		if err := store.WriteEntry(models.NewJournalEntry(map[string]string{"feelings": text}, "user")); err != nil {
+			t.Fatalf("WriteEntry error: %v", err)
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(userDir, indexFileName), []byte("{not json"), 0o644); err != nil {
+		t.Fatalf("failed to corrupt index: %v", err)
+	}
+
+	fresh, _ := NewJournalMDStore(filepath.Join(tmpDir, "project"), userDir)
+	count, err := fresh.Reindex()
+	if err != nil {
+		t.Fatalf("Reindex error: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("expected 3 entries indexed, got %d", count)
+	}
+	if idx := readIndexFile(t, userDir); len(idx.Entries) != 3 {
+		t.Errorf("expected rebuilt index with 3 entries, got %d", len(idx.Entries))
+	}
+}
+
+func TestJournalSearchUsesIndexCandidates(t *testing.T) {
+	tmpDir := t.TempDir()
+	store, _ := NewJournalMDStore(filepath.Join(tmpDir, "project"), filepath.Join(tmpDir, "user"))
+
+	texts := []string{"the flaky integration test", "lunch was good", "flaky network again"}
+	for _, text := range texts {
+		if err := store.WriteEntry(models.NewJournalEntry(map[string]string{"feelings": text}, "user")); err != nil {
+			t.Fatalf("WriteEntry error: %v", err)
+		}
+	}
+
+	results, err := store.Search("flaky", embeddings.SearchOptions{})
+	if err != nil {
+		t.Fatalf("Search error: %v", err)
+	}
+	if len(results) != 2 {
+		t.Errorf("expected 2 results, got %d", len(results))
+	}
+
+	// Partial words still match through term prefixes
+	results, err = store.Search("integ", embeddings.SearchOptions{})
+	if err != nil {
+		t.Fatalf("Search error: %v", err)
+	}
+	if len(results) != 1 {
+		t.Errorf("expected 1 prefix result, got %d", len(results))
+	}
+}
+
+func TestJournalSearchReadsNoSidecarsForCandidates(t *testing.T) {
+	tmpDir := t.TempDir()
+	embedder := embeddings.NewHashEmbedder(embeddings.DefaultDimension)
+	store, _ := NewJournalMDStore(filepath.Join(tmpDir, "project"), filepath.Join(tmpDir, "user"),
+		WithEmbedder(embedder), WithGitContext(false))
+
+	related := models.NewJournalEntry(map[string]string{"technical_insights": "migrating the billing database"}, "user")
+	for _, entry := range []*models.JournalEntry{
+		related,
+		models.NewJournalEntry(map[string]string{"feelings": "calm afternoon"}, "user"),
+		models.NewJournalEntry(map[string]string{"world_knowledge": "owls hunt at night"}, "user"),
+	} {
+		if err := store.WriteEntry(entry); err != nil {
+			t.Fatalf("WriteEntry error: %v", err)
+		}
+	}
+
+	reads := 0
+	readEmbedding = func(path string) (*models.Embedding, error) {
+		reads++
+		return embeddings.ReadEmbedding(path)
+	}
+	t.Cleanup(func() { readEmbedding = embeddings.ReadEmbedding })
+
+	// "migration" shares no indexed term with the entry, only meaning
+	results, err := store.Search("migration", embeddings.SearchOptions{})
+	if err != nil {
+		t.Fatalf("Search error: %v", err)
+	}
+	if len(results) != 1 || results[0].Entry.ID != related.ID {
+		t.Fatalf("expected the related entry by meaning, got %+v", results)
+	}
+	if reads != 0 {
+		t.Errorf("expected candidates to come from indexed vectors, read %d sidecars", reads)
+	}
+
+	// A sidecar rewritten behind the index's back is picked up on refresh
+	if err := embeddings.WriteEmbedding(related.FilePath, embedder, map[string]string{"technical_insights": "owls"}); err != nil {
+		t.Fatalf("WriteEmbedding error: %v", err)
+	}
+	if _, err := store.Search("migration", embeddings.SearchOptions{}); err != nil {
+		t.Fatalf("Search error: %v", err)
+	}
+	if reads != 1 {
+		t.Errorf("expected only the changed sidecar to be read, read %d", reads)
+	}
+}
+
+// sortedPostings copies postings with each path list sorted, for comparison.
+func sortedPostings(postings map[string][]string) map[string][]string {
+	out := make(map[string][]string, len(postings))
+	for key, paths := range postings {
+		out[key] = append([]string(nil), paths...)
+		sort.Strings(out[key])
+	}
+	return out
+}
+
+func TestJournalIndexRemovalMatchesRebuild(t *testing.T) {
+	tmpDir := t.TempDir()
+	userDir := filepath.Join(tmpDir, "user")
+	store, _ := NewJournalMDStore(filepath.Join(tmpDir, "project"), userDir, WithGitContext(false))
+
+	var entries []*models.JournalEntry
+	for _, text := range []string{"poller goroutine leak", "billing migration plan", "quiet poller morning"} {
+		entry := models.NewJournalEntry(map[string]string{"technical_insights": text, "feelings": "fine"}, "user")
+		if err := store.WriteEntry(entry); err != nil {
+			t.Fatalf("WriteEntry error: %v", err)
+		}
+		entries = append(entries, entry)
+	}
+	if _, err := store.UpdateEntry(entries[0].FilePath, map[string]string{"technical_insights": "leak fixed"}); err != nil {
+		t.Fatalf("UpdateEntry error: %v", err)
+	}
+	if _, err := store.DeleteEntry(entries[1].FilePath, ""); err != nil {
+		t.Fatalf("DeleteEntry error: %v", err)
+	}
+
+	got := readIndexFile(t, userDir)
+	want, err := buildJournalIndex(userDir)
+	if err != nil {
+		t.Fatalf("buildJournalIndex error: %v", err)
+	}
+	for name, pair := range map[string][2]map[string][]string{
+		"terms":    {got.Terms, want.Terms},
+		"dates":    {got.Dates, want.Dates},
+		"types":    {got.Types, want.Types},
+		"sections": {got.Sections, want.Sections},
+	} {
+		if !reflect.DeepEqual(sortedPostings(pair[0]), sortedPostings(pair[1])) {
+			t.Errorf("%s postings after edits = %v, want %v", name, pair[0], pair[1])
+		}
+	}
+	if _, ok := got.Terms["billing"]; ok {
+		t.Errorf("expected the deleted entry's terms dropped, got %v", got.Terms["billing"])
+	}
+}
+
+func TestJournalImportSavesIndexOnce(t *testing.T) {
+	tmpDir := t.TempDir()
+	store, _ := NewJournalMDStore(filepath.Join(tmpDir, "project"), filepath.Join(tmpDir, "user"), WithGitContext(false))
+
+	saves := 0
+	saveIndex = func(idx *journalIndex, root string) error {
+		saves++
+		return idx.save(root)
+	}
+	t.Cleanup(func() { saveIndex = (*journalIndex).save })
+
+	var entries []*models.JournalEntry
+	for i := range 20 {
+		entry := models.NewJournalEntry(map[string]string{"feelings": fmt.Sprintf("imported note %d", i)}, "user")
+		entry.CreatedAt = time.Now().Add(-time.Duration(i) * 24 * time.Hour)
+		entries = append(entries, entry)
+	}
+	report, err := store.ImportEntries(entries, ImportOptions{})
+	if err != nil || len(report.Imported) != 20 {
+		t.Fatalf("expected 20 entries imported, got %+v, %v", report, err)
+	}
+	if saves != 1 {
+		t.Errorf("expected the index saved once for the import, saved %d times", saves)
+	}
+	if listed, _ := store.ListEntries(ListOptions{}); len(listed) != 20 {
+		t.Errorf("expected the imported entries listed, got %d", len(listed))
+	}
+}
@@ -1,4 +1,4 @@
-// ABOUTME: Markdown-based journal storage with dual-root support.
+// ABOUTME: Markdown-based journal storage with a root per journal: project, user, and configured ones.
 // ABOUTME: Stores entries as markdown files with YAML frontmatter in date-based directories.
 package storage
 
@@ -6,44 +6,214 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/harperreed/mdstore"
 	"gopkg.in/yaml.v3"
 
+	"github.com/2389-research/pulse/internal/embeddings"
 	"github.com/2389-research/pulse/internal/models"
+	"github.com/2389-research/pulse/internal/query"
+	"github.com/2389-research/pulse/internal/redact"
 )
 
-// JournalMDStore stores journal entries as markdown files in dual-root directories.
+// JournalMDStore stores journal entries as markdown files, one root
+// directory per journal.
 type JournalMDStore struct {
-	projectPath string // project-local root (.private-journal/ in cwd)
-	userPath    string // user-global root (~/.private-journal/)
+	projectPath string              // project-local root (.private-journal/ in cwd)
+	userPath    string              // user-global root (~/.private-journal/)
+	journals    map[string]string   // configured journals beyond project and user: name -> root
+	embedder    embeddings.Embedder // optional; enables .embedding sidecars and semantic search
+	captureGit  bool                // record git context on project entries; see applyGitContext
+	cipher      *journalCipher      // opens encrypted bodies; nil without a passphrase
+	encrypt     bool                // encrypt the bodies of new entries
+	guardGit    bool                // exclude the project journal from git; see guardProjectJournal
+	guardOnce   sync.Once           // runs guardProjectJournal once per store
+	warnings    func(msg string)    // optional; receives problems that do not fail an operation
+	redactor    *redact.Redactor    // optional; scans section text before it is written
+
+	mu      sync.Mutex               // guards indexes
+	indexes map[string]*journalIndex // cached per-root indexes, keyed by root path
+
+	archiveMu sync.Mutex               // guards archives
+	archives  map[string]*cachedBundle // decompressed bundles, keyed by bundle path; see readArchived
+}
+
+// JournalOption configures optional JournalMDStore dependencies.
+type JournalOption func(*JournalMDStore)
+
+// WithEmbedder sets the embedder used to write sidecars and rank search results.
+func WithEmbedder(e embeddings.Embedder) JournalOption {
+	return func(s *JournalMDStore) {
+		s.embedder = e
+	}
+}
+
+// WithGitContext enables or disables recording the enclosing repository,
+// branch, commit, and dirty status on project entries. Enabled by default.
+func WithGitContext(enabled bool) JournalOption {
+	return func(s *JournalMDStore) {
+		s.captureGit = enabled
+	}
+}
+
+// WithGitGuard enables or disables excluding the project journal from an
+// enclosing git repository on its first write. Enabled by default.
+func WithGitGuard(enabled bool) JournalOption {
+	return func(s *JournalMDStore) {
+		s.guardGit = enabled
+	}
+}
+
+// WithWarnings sets a handler for problems that do not fail an operation,
+// such as journal files being tracked by git.
+func WithWarnings(fn func(msg string)) JournalOption {
+	return func(s *JournalMDStore) {
+		s.warnings = fn
+	}
+}
+
+// WithRedactor scans the sections of every written or edited entry for
+// secrets and personal data, masking, reporting, or refusing them per its mode.
+func WithRedactor(r *redact.Redactor) JournalOption {
+	return func(s *JournalMDStore) {
+		s.redactor = r
+	}
+}
+
+// WithJournals adds named journals, such as work or oncall, each stored in
+// its own root directory, alongside the project and user journals.
+func WithJournals(roots map[string]string) JournalOption {
+	return func(s *JournalMDStore) {
+		for name, root := range roots {
+			s.journals[name] = root
+		}
+	}
+}
+
+// WithPassphrase lets the store read entries whose bodies were encrypted with
+// passphrase. On its own it does not encrypt new entries; see WithEncryption.
+func WithPassphrase(passphrase string) JournalOption {
+	return func(s *JournalMDStore) {
+		s.cipher = newJournalCipher(passphrase)
+	}
+}
+
+// WithEncryption encrypts the bodies of entries written from now on, leaving
+// their frontmatter readable. It requires WithPassphrase.
+func WithEncryption(enabled bool) JournalOption {
+	return func(s *JournalMDStore) {
+		s.encrypt = enabled
+	}
 }
 
 // journalFrontmatter is the YAML frontmatter for journal entry files.
 type journalFrontmatter struct {
-	ID   string `yaml:"id"`
-	Date string `yaml:"date"`
-	Type string `yaml:"type"`
+	ID           string   `yaml:"id"`
+	Date         string   `yaml:"date"`
+	Type         string   `yaml:"type"`
+	UpdatedAt    string   `yaml:"updated_at,omitempty"`
+	DeletedAt    string   `yaml:"deleted_at,omitempty"`
+	DeleteReason string   `yaml:"delete_reason,omitempty"`
+	LinkedIDs    []string `yaml:"linked_ids,omitempty"`
+	ThoughtID    string   `yaml:"thought_id,omitempty"`
+
+	Tags       []string          `yaml:"tags,omitempty"`
+	Mood       string            `yaml:"mood,omitempty"`
+	Valence    *float64          `yaml:"valence,omitempty"`
+	Agent      string            `yaml:"agent,omitempty"`
+	Repo       string            `yaml:"repo,omitempty"`
+	Branch     string            `yaml:"branch,omitempty"`
+	Commit     string            `yaml:"commit,omitempty"`
+	Dirty      bool              `yaml:"dirty,omitempty"`
+	Attributes map[string]string `yaml:"attributes,omitempty"`
+
+	Encryption *encryptionHeader `yaml:"encryption,omitempty"` // set when the body is encrypted
 }
 
-// NewJournalMDStore creates a journal store with the given project and user root paths.
-func NewJournalMDStore(projectPath, userPath string) (*JournalMDStore, error) {
-	return &JournalMDStore{
+// NewJournalMDStore creates a journal store with the given project and user
+// root paths. WithJournals adds more.
+func NewJournalMDStore(projectPath, userPath string, opts ...JournalOption) (*JournalMDStore, error) {
+	s := &JournalMDStore{
 		projectPath: projectPath,
 		userPath:    userPath,
-	}, nil
+		captureGit:  true,
+		guardGit:    true,
+		journals:    make(map[string]string),
+		indexes:     make(map[string]*journalIndex),
+		archives:    make(map[string]*cachedBundle),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	for name := range s.journals {
+		if err := models.CheckJournalName(name); err != nil {
+			return nil, err
+		}
+	}
+	for _, def := range models.Sections() {
+		if _, ok := s.journalRoot(def.Route); !ok {
+			return nil, fmt.Errorf("section %q routes to unknown journal %q: must be one of: %s", def.Name, def.Route, strings.Join(s.Journals(), ", "))
+		}
+	}
+	if s.encrypt && s.cipher == nil {
+		return nil, fmt.Errorf("journal encryption is enabled but no passphrase is configured")
+	}
+	return s, nil
 }
 
-// WriteEntry persists a journal entry to the appropriate root directory.
+// WriteEntry persists a journal entry to the root of the journal its type names.
+// Project entries also record the git context of the enclosing work tree.
 func (s *JournalMDStore) WriteEntry(entry *models.JournalEntry) error {
-	root := s.userPath
+	if err := s.redactEntry(entry); err != nil {
+		return err
+	}
+	s.applyGitContext(entry)
+	root, err := s.storeEntry(entry)
+	if err != nil {
+		return err
+	}
+	// Refreshing rescans only the date directories whose mtime changed,
+	// picking up this entry and anything written concurrently by another process.
+	if err := s.withIndex(root, nil); err != nil {
+		return fmt.Errorf("failed to update index: %w", err)
+	}
+	return nil
+}
+
+// redactEntry applies the store's redactor to entry's sections, recording
+// what it found in entry.Redactions.
+func (s *JournalMDStore) redactEntry(entry *models.JournalEntry) error {
+	sections, findings, err := s.redactor.Sections(entry.Sections)
+	if err != nil {
+		return err
+	}
+	entry.Sections = sections
+	entry.Redactions = findings
+	return nil
+}
+
+// storeEntry writes entry to its journal's root under a path derived from its
+// creation time and ID, and returns that root. The caller refreshes the root's
+// index, once per batch when storing many entries.
+func (s *JournalMDStore) storeEntry(entry *models.JournalEntry) (string, error) {
+	if entry.Type == "" {
+		entry.Type = "user"
+	}
+	root, ok := s.journalRoot(entry.Type)
+	if !ok {
+		return "", fmt.Errorf("unknown journal %q: must be one of: %s", entry.Type, strings.Join(s.Journals(), ", "))
+	}
 	if entry.Type == "project" {
-		root = s.projectPath
+		s.guardProjectJournal()
 	}
+	entry.Encrypted = s.encrypt
+	entry.Locked = false
 
 	dateDir := entry.CreatedAt.Format("2006-01-02")
 	timeStr := entry.CreatedAt.Format("15-04-05-000000")
@@ -52,109 +222,332 @@ func (s *JournalMDStore) WriteEntry(entry *models.JournalEntry) error {
 	dir := filepath.Join(root, dateDir)
 	path := filepath.Join(dir, filename)
 
-	fm := journalFrontmatter{
-		ID:   entry.ID.String(),
-		Date: mdstore.FormatTime(entry.CreatedAt),
-		Type: entry.Type,
+	if err := s.writeEntryFile(root, path, entry); err != nil {
+		return "", err
 	}
+	return root, nil
+}
 
-	body := renderSections(entry.Sections)
-
-	content, err := mdstore.RenderFrontmatter(fm, body)
+// ReadEntry reads a journal entry from the given file path.
+// The path must be within one of the journal roots.
+// Archived entries are read from their bundle by their original path.
+func (s *JournalMDStore) ReadEntry(path string) (*models.JournalEntry, error) {
+	loc, err := s.resolveEntryPath(path)
 	if err != nil {
-		return fmt.Errorf("failed to render frontmatter: %w", err)
-	}
-
-	if err := mdstore.AtomicWrite(path, []byte(content)); err != nil {
-		return fmt.Errorf("failed to write entry: %w", err)
+		archived, archErr := s.resolveArchivedPath(path)
+		if archErr != nil {
+			return nil, err
+		}
+		loc = archived
 	}
+	return s.readEntryFile(loc.path)
+}
 
-	entry.FilePath = path
-	return nil
+// entryLocation is an entry path resolved against the journal roots.
+type entryLocation struct {
+	path    string // absolute, symlink-free path to the entry file
+	root    string // configured root containing it, as passed to NewJournalMDStore
+	absRoot string // absolute, symlink-free form of root
+	rel     string // slash-separated path relative to absRoot
 }
 
-// ReadEntry reads a journal entry from the given file path.
-// The path must be within one of the journal roots (project or user).
-func (s *JournalMDStore) ReadEntry(path string) (*models.JournalEntry, error) {
+// resolveEntryPath resolves path to an absolute, symlink-free path and locates
+// the root containing it. Paths outside every root are rejected.
+func (s *JournalMDStore) resolveEntryPath(path string) (entryLocation, error) {
 	absPath, err := filepath.Abs(path)
 	if err != nil {
-		return nil, fmt.Errorf("invalid path: %w", err)
+		return entryLocation{}, fmt.Errorf("invalid path: %w", err)
 	}
 
 	// Resolve symlinks to prevent traversal via symlink targets outside roots
 	absPath, err = filepath.EvalSymlinks(absPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to resolve path: %w", err)
+		return entryLocation{}, fmt.Errorf("failed to resolve path: %w", err)
 	}
 
-	// Resolve symlinks in roots too, in case the journal root itself contains symlinks
-	absProject, _ := filepath.Abs(s.projectPath)
-	absUser, _ := filepath.Abs(s.userPath)
-	if resolved, err := filepath.EvalSymlinks(absProject); err == nil {
-		absProject = resolved
-	}
-	if resolved, err := filepath.EvalSymlinks(absUser); err == nil {
-		absUser = resolved
+	for _, root := range s.allRoots() {
+		absRoot := resolveRoot(root)
+		if strings.HasPrefix(absPath, absRoot+string(filepath.Separator)) {
+			rel, _ := filepath.Rel(absRoot, absPath)
+			return entryLocation{path: absPath, root: root, absRoot: absRoot, rel: filepath.ToSlash(rel)}, nil
+		}
 	}
 
-	if !strings.HasPrefix(absPath, absProject+string(filepath.Separator)) &&
-		!strings.HasPrefix(absPath, absUser+string(filepath.Separator)) {
-		return nil, fmt.Errorf("path %q is outside journal roots", path)
+	return entryLocation{}, fmt.Errorf("path %q is outside journal roots", path)
+}
+
+// resolveRoot returns root as an absolute path, with symlinks resolved when
+// the root exists, in case the journal root itself contains symlinks.
+func resolveRoot(root string) string {
+	absRoot, _ := filepath.Abs(root)
+	if resolved, err := filepath.EvalSymlinks(absRoot); err == nil {
+		absRoot = resolved
 	}
+	return absRoot
+}
 
-	data, err := os.ReadFile(absPath)
+// writeEntryFile renders entry with its frontmatter and writes it atomically
+// to path within root, then refreshes its embedding sidecar.
+func (s *JournalMDStore) writeEntryFile(root, path string, entry *models.JournalEntry) error {
+	content, err := s.renderEntry(root, entry)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read entry: %w", err)
+		return err
+	}
+
+	if err := mdstore.AtomicWrite(path, []byte(content)); err != nil {
+		return fmt.Errorf("failed to write entry: %w", err)
+	}
+
+	entry.FilePath = path
+
+	// Sidecars hold the entry's text, so encrypted entries have none
+	if entry.Encrypted {
+		if err := os.Remove(embeddings.EmbeddingPath(path)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove embedding: %w", err)
+		}
+		return nil
 	}
 
-	return parseJournalEntry(absPath, string(data))
+	// The sidecar is derived data and can be regenerated, so a failure here
+	// must not fail the write; search falls back to keyword matching.
+	if s.embedder != nil {
+		_ = embeddings.WriteEmbedding(path, s.embedder, entry.Sections)
+	}
+	return nil
 }
 
-// ListEntries lists journal entries, filtered by type and date range.
-func (s *JournalMDStore) ListEntries(entryType string, limit int, days int) ([]*models.JournalEntry, error) {
-	var entries []*models.JournalEntry
+// renderJournalEntry renders an entry as markdown with YAML frontmatter.
+func renderJournalEntry(entry *models.JournalEntry) (string, error) {
+	return renderEntryBody(entry, nil, renderSections(entry.Sections))
+}
 
-	var roots []struct {
-		path     string
-		fileType string
+// renderEntryBody renders entry's frontmatter above body. A non-nil header
+// marks body as encrypted.
+func renderEntryBody(entry *models.JournalEntry, header *encryptionHeader, body string) (string, error) {
+	fm := journalFrontmatter{
+		ID:           entry.ID.String(),
+		Date:         mdstore.FormatTime(entry.CreatedAt),
+		Type:         entry.Type,
+		DeleteReason: entry.DeleteReason,
+		Tags:         entry.Tags,
+		Mood:         entry.Mood,
+		Valence:      entry.Valence,
+		Agent:        entry.Agent,
+		Repo:         entry.Repo,
+		Branch:       entry.Branch,
+		Commit:       entry.Commit,
+		Dirty:        entry.Dirty,
+		Attributes:   entry.Attributes,
+		Encryption:   header,
+	}
+	for _, id := range entry.LinkedIDs {
+		fm.LinkedIDs = append(fm.LinkedIDs, id.String())
+	}
+	if entry.ThoughtID != uuid.Nil {
+		fm.ThoughtID = entry.ThoughtID.String()
+	}
+	if !entry.UpdatedAt.IsZero() {
+		fm.UpdatedAt = mdstore.FormatTime(entry.UpdatedAt)
+	}
+	if !entry.DeletedAt.IsZero() {
+		fm.DeletedAt = mdstore.FormatTime(entry.DeletedAt)
 	}
 
-	if entryType == "both" || entryType == "user" || entryType == "" {
-		roots = append(roots, struct {
-			path     string
-			fileType string
-		}{s.userPath, "user"})
+	content, err := mdstore.RenderFrontmatter(fm, body)
+	if err != nil {
+		return "", fmt.Errorf("failed to render frontmatter: %w", err)
 	}
-	if entryType == "both" || entryType == "project" || entryType == "" {
-		roots = append(roots, struct {
-			path     string
-			fileType string
-		}{s.projectPath, "project"})
+	return content, nil
+}
+
+// SortOrder orders listed entries by creation time.
+type SortOrder int
+
+const (
+	NewestFirst SortOrder = iota
+	OldestFirst
+)
+
+// ListOptions selects and orders the entries returned by ListEntries.
+type ListOptions struct {
+	Type   string            // journal name, comma-separated names, or "all" (default)
+	Since  time.Time         // entries created at or after this; zero for no lower bound
+	Until  time.Time         // entries created before this; zero for no upper bound
+	Offset int               // entries to skip after sorting
+	Limit  int               // maximum entries to return; 0 for no limit
+	Order  SortOrder         // NewestFirst (default) or OldestFirst
+	Filter models.MetaFilter // tag, mood, agent, and git context filter
+	After  models.Cursor     // resume strictly after this position in Order; zero to start at the top
+}
+
+// ListEntries lists journal entries matching opts. The index narrows the
+// listing so only the returned entries are parsed.
+func (s *JournalMDStore) ListEntries(opts ListOptions) ([]*models.JournalEntry, error) {
+	if opts.Offset < 0 || opts.Limit < 0 {
+		return nil, fmt.Errorf("offset and limit must be non-negative")
 	}
 
-	var cutoff time.Time
-	if days > 0 {
-		cutoff = time.Now().AddDate(0, 0, -days)
+	// Date directories are named in the writer's local time, so allow a day
+	// of slack for zone differences and compare exact timestamps below.
+	var sinceDir string
+	if !opts.Since.IsZero() {
+		sinceDir = opts.Since.AddDate(0, 0, -1).Format("2006-01-02")
 	}
 
-	for _, root := range roots {
-		rootEntries, err := listEntriesInRoot(root.path, cutoff)
-		if err != nil {
-			return nil, fmt.Errorf("failed to list entries in %s: %w", root.path, err)
+	refs, err := s.indexedEntries(opts.Type, sinceDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list entries: %w", err)
+	}
+	kept := refs[:0]
+	for _, ref := range refs {
+		created := ref.meta.CreatedAt
+		if !opts.Since.IsZero() && created.Before(opts.Since) {
+			continue
+		}
+		if !opts.Until.IsZero() && !created.Before(opts.Until) {
+			continue
 		}
-		entries = append(entries, rootEntries...)
+		if !opts.Filter.Matches(ref.meta.Meta) {
+			continue
+		}
+		if !opts.After.IsZero() {
+			cmp := opts.After.Compare(created, ref.id())
+			if (opts.Order == OldestFirst && cmp <= 0) || (opts.Order == NewestFirst && cmp >= 0) {
+				continue
+			}
+		}
+		kept = append(kept, ref)
+	}
+	refs = kept
+
+	// Order by (CreatedAt, ID) so entries sharing a timestamp, such as
+	// siblings split from one write, page in a stable order
+	sort.Slice(refs, func(i, j int) bool {
+		cursor := models.Cursor{CreatedAt: refs[j].meta.CreatedAt, ID: refs[j].id()}
+		cmp := cursor.Compare(refs[i].meta.CreatedAt, refs[i].id())
+		if opts.Order == OldestFirst {
+			return cmp < 0
+		}
+		return cmp > 0
+	})
+
+	if opts.Offset >= len(refs) {
+		return nil, nil
+	}
+	refs = refs[opts.Offset:]
+	if opts.Limit > 0 && len(refs) > opts.Limit {
+		refs = refs[:opts.Limit]
+	}
+
+	paths := make([]string, len(refs))
+	for i, ref := range refs {
+		paths[i] = ref.path
+	}
+	return s.readEntryFiles(paths), nil
+}
+
+// Search ranks entries matching queryString, written in the query language of
+// package query and filtered by type and sections, using the hybrid BM25 and
+// embedding engine. The index selects candidate entries so only those are
+// parsed. Without an embedder, ranking is purely lexical.
+func (s *JournalMDStore) Search(queryString string, opts embeddings.SearchOptions) ([]embeddings.SearchResult, error) {
+	q, err := query.Parse(queryString)
+	if err != nil {
+		return nil, err
+	}
+	if err := q.CheckTypes(s.Journals()); err != nil {
+		return nil, err
+	}
+	paths, stats, err := s.searchCandidates(q, opts)
+	if err != nil {
+		return nil, err
 	}
 
-	// Sort by date descending (most recent first)
+	entries := s.readEntryFiles(paths)
 	sort.Slice(entries, func(i, j int) bool {
 		return entries[i].CreatedAt.After(entries[j].CreatedAt)
 	})
 
-	if limit > 0 && len(entries) > limit {
-		entries = entries[:limit]
+	opts.Corpus = stats
+	return embeddings.Search(s.embedder, entries, queryString, opts)
+}
+
+// readEntryFiles parses the given entry files, reading archived ones from
+// their bundles and skipping any that vanished or fail to parse. Encrypted
+// entries the store cannot open are returned locked, with their frontmatter
+// but no sections.
+func (s *JournalMDStore) readEntryFiles(paths []string) []*models.JournalEntry {
+	entries := make([]*models.JournalEntry, 0, len(paths))
+	for _, path := range paths {
+		data, err := s.readEntryData(path)
+		if err != nil {
+			continue
+		}
+		entry, err := parseJournalEntry(path, string(data), s.cipher)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, entry)
 	}
+	return entries
+}
 
-	return entries, nil
+// Journals returns the names of the store's journals: project, user, then
+// the configured ones sorted by name.
+func (s *JournalMDStore) Journals() []string {
+	extra := make([]string, 0, len(s.journals))
+	for name := range s.journals {
+		extra = append(extra, name)
+	}
+	sort.Strings(extra)
+	return append(models.BuiltinJournals(), extra...)
+}
+
+// journalRoot returns the root directory of the named journal.
+func (s *JournalMDStore) journalRoot(name string) (string, bool) {
+	switch name {
+	case "project":
+		return s.projectPath, true
+	case "user":
+		return s.userPath, true
+	}
+	root, ok := s.journals[name]
+	return root, ok
+}
+
+// rootsFor returns the root directories covered by an entry type filter (a
+// journal name, a comma-separated list of names, or "all" or empty) and the
+// journals it selects. A root may also hold entries of other journals; see
+// inJournals.
+func (s *JournalMDStore) rootsFor(entryType string) (roots, journals []string, err error) {
+	names, err := models.SelectJournals(entryType, s.Journals())
+	if err != nil {
+		return nil, nil, err
+	}
+	// Running from the home directory makes the project and user roots the
+	// same directory, and configured journals may share one too
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		root, _ := s.journalRoot(name)
+		if !seen[filepath.Clean(root)] {
+			seen[filepath.Clean(root)] = true
+			roots = append(roots, root)
+		}
+	}
+	return roots, names, nil
+}
+
+// inJournals reports whether an entry of type entryType belongs to journals.
+// Journals can share a root, so a root's listing includes other journals'
+// entries; entries written before types were recorded belong to their root.
+func inJournals(entryType string, journals []string) bool {
+	return entryType == "" || slices.Contains(journals, entryType)
+}
+
+// allRoots returns the root directory of every journal.
+func (s *JournalMDStore) allRoots() []string {
+	roots, _, _ := s.rootsFor("all")
+	return roots
 }
 
 // Close releases any resources held by the store.
@@ -162,8 +555,9 @@ func (s *JournalMDStore) Close() error {
 	return nil
 }
 
-// listEntriesInRoot scans a root directory for journal entries.
-func listEntriesInRoot(root string, cutoff time.Time) ([]*models.JournalEntry, error) {
+// listEntriesInRoot scans a root directory for journal entries, opening
+// encrypted bodies with c. With a nil c they are returned locked.
+func listEntriesInRoot(root string, c *journalCipher) ([]*models.JournalEntry, error) {
 	if _, err := os.Stat(root); os.IsNotExist(err) {
 		return nil, nil
 	}
@@ -180,17 +574,9 @@ func listEntriesInRoot(root string, cutoff time.Time) ([]*models.JournalEntry, e
 			continue
 		}
 
-		// Check date cutoff by directory name
-		if !cutoff.IsZero() {
-			dirDate, err := time.Parse("2006-01-02", dateDir.Name())
-			if err != nil {
-				continue
-			}
-			// Compare dates only: truncate cutoff to start of day
-			cutoffDate := cutoff.Truncate(24 * time.Hour)
-			if dirDate.Before(cutoffDate) {
-				continue
-			}
+		// Only date directories hold live entries; this skips .trash/
+		if _, err := time.Parse("2006-01-02", dateDir.Name()); err != nil {
+			continue
 		}
 
 		dirPath := filepath.Join(root, dateDir.Name())
@@ -210,7 +596,7 @@ func listEntriesInRoot(root string, cutoff time.Time) ([]*models.JournalEntry, e
 				continue
 			}
 
-			entry, err := parseJournalEntry(filePath, string(data))
+			entry, err := parseJournalEntry(filePath, string(data), c)
 			if err != nil {
 				continue
 			}
@@ -222,8 +608,10 @@ func listEntriesInRoot(root string, cutoff time.Time) ([]*models.JournalEntry, e
 	return entries, nil
 }
 
-// parseJournalEntry parses a markdown file into a JournalEntry.
-func parseJournalEntry(path string, content string) (*models.JournalEntry, error) {
+// parseJournalEntry parses a markdown file into a JournalEntry, decrypting an
+// encrypted body with c. With a nil c an encrypted entry is returned locked:
+// its frontmatter is parsed but its sections are left empty.
+func parseJournalEntry(path string, content string, c *journalCipher) (*models.JournalEntry, error) {
 	yamlStr, body := mdstore.ParseFrontmatter(content)
 	if yamlStr == "" {
 		return nil, fmt.Errorf("no frontmatter found in %s", path)
@@ -244,59 +632,76 @@ func parseJournalEntry(path string, content string) (*models.JournalEntry, error
 		return nil, fmt.Errorf("invalid date in frontmatter: %w", err)
 	}
 
-	sections := parseSections(body)
-
-	return &models.JournalEntry{
+	entry := &models.JournalEntry{
 		ID:        id,
-		Sections:  sections,
 		CreatedAt: createdAt,
 		FilePath:  path,
 		Type:      fm.Type,
-	}, nil
+		Encrypted: fm.Encryption != nil,
+	}
+	switch {
+	case fm.Encryption == nil:
+		entry.Sections = models.ParseSections(body)
+	case c == nil:
+		entry.Sections = make(map[string]string)
+		entry.Locked = true
+	default:
+		plain, err := c.open(fm.Encryption, fm.ID, body)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		entry.Sections = models.ParseSections(plain)
+	}
+	if fm.UpdatedAt != "" {
+		if updatedAt, err := mdstore.ParseTime(fm.UpdatedAt); err == nil {
+			entry.UpdatedAt = updatedAt
+		}
+	}
+	if fm.DeletedAt != "" {
+		if deletedAt, err := mdstore.ParseTime(fm.DeletedAt); err == nil {
+			entry.DeletedAt = deletedAt
+		}
+	}
+	entry.DeleteReason = fm.DeleteReason
+	entry.LinkedIDs = parseLinkedIDs(fm.LinkedIDs)
+	if fm.ThoughtID != "" {
+		if thoughtID, err := uuid.Parse(fm.ThoughtID); err == nil {
+			entry.ThoughtID = thoughtID
+		}
+	}
+	entry.EntryMeta = models.EntryMeta{
+		Tags:       fm.Tags,
+		Mood:       fm.Mood,
+		Valence:    fm.Valence,
+		Agent:      fm.Agent,
+		Repo:       fm.Repo,
+		Branch:     fm.Branch,
+		Commit:     fm.Commit,
+		Dirty:      fm.Dirty,
+		Attributes: fm.Attributes,
+	}
+	return entry, nil
 }
 
-// renderSections converts a sections map to markdown body text.
+// renderSections converts a sections map to markdown body text. Sections
+// render in schema order; any the schema no longer declares follow by name,
+// so edits never drop content.
 func renderSections(sections map[string]string) string {
-	// Render in a stable order
 	var sb strings.Builder
-	for _, name := range models.GetValidSections() {
-		content, ok := sections[name]
-		if !ok || content == "" {
-			continue
-		}
-		sb.WriteString(fmt.Sprintf("\n## %s\n%s\n", models.SectionTitle(name), content))
+	for _, name := range sectionOrder(sections) {
+		sb.WriteString(fmt.Sprintf("\n## %s\n%s\n", models.SectionTitle(name), sections[name]))
 	}
 	return sb.String()
 }
 
-// parseSections extracts sections from markdown body text.
-func parseSections(body string) map[string]string {
-	sections := make(map[string]string)
-	lines := strings.Split(body, "\n")
-
-	var currentSection string
-	var currentContent strings.Builder
-
-	for _, line := range lines {
-		if strings.HasPrefix(line, "## ") {
-			// Save previous section
-			if currentSection != "" {
-				sections[currentSection] = strings.TrimSpace(currentContent.String())
-			}
-			// Start new section
-			heading := strings.TrimPrefix(line, "## ")
-			currentSection = models.SectionKey(heading)
-			currentContent.Reset()
-		} else if currentSection != "" {
-			currentContent.WriteString(line)
-			currentContent.WriteString("\n")
+// sectionOrder returns the names of an entry's non-empty sections in the
+// order of models.SectionNames.
+func sectionOrder(sections map[string]string) []string {
+	var names []string
+	for _, name := range models.SectionNames(sections) {
+		if sections[name] != "" {
+			names = append(names, name)
 		}
 	}
-
-	// Save last section
-	if currentSection != "" {
-		sections[currentSection] = strings.TrimSpace(currentContent.String())
-	}
-
-	return sections
+	return names
 }
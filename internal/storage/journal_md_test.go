@@ -1,8 +1,9 @@
 // ABOUTME: Tests for markdown-based journal storage.
-// ABOUTME: Covers write/read roundtrip, dual-root listing, date ordering, and section parsing.
+// ABOUTME: Covers write/read roundtrip, dual-root, shared-root and named journal listing, date ordering, section parsing, and search.
 package storage
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -11,7 +12,9 @@ import (
 
 	"github.com/google/uuid"
 
+	"github.com/2389-research/pulse/internal/embeddings"
 	"github.com/2389-research/pulse/internal/models"
+	"github.com/2389-research/pulse/internal/query"
 )
 
 func TestJournalWriteReadRoundtrip(t *testing.T) {
@@ -117,7 +120,7 @@ func TestJournalDualRootListing(t *testing.T) {
 	}
 
 	// List both types
-	entries, err := store.ListEntries("both", 0, 0)
+	entries, err := store.ListEntries(ListOptions{})
 	if err != nil {
 		t.Fatalf("ListEntries (both) error: %v", err)
 	}
@@ -126,7 +129,7 @@ func TestJournalDualRootListing(t *testing.T) {
 	}
 
 	// List only user entries
-	userEntries, err := store.ListEntries("user", 0, 0)
+	userEntries, err := store.ListEntries(ListOptions{Type: "user"})
 	if err != nil {
 		t.Fatalf("ListEntries (user) error: %v", err)
 	}
@@ -138,7 +141,7 @@ func TestJournalDualRootListing(t *testing.T) {
 	}
 
 	// List only project entries
-	projectEntries, err := store.ListEntries("project", 0, 0)
+	projectEntries, err := store.ListEntries(ListOptions{Type: "project"})
 	if err != nil {
 		t.Fatalf("ListEntries (project) error: %v", err)
 	}
@@ -182,7 +185,7 @@ func TestJournalDateOrdering(t *testing.T) {
 		t.Fatalf("WriteEntry (newer) error: %v", err)
 	}
 
-	entries, err := store.ListEntries("both", 0, 0)
+	entries, err := store.ListEntries(ListOptions{})
 	if err != nil {
 		t.Fatalf("ListEntries error: %v", err)
 	}
@@ -220,7 +223,7 @@ func TestJournalLimit(t *testing.T) {
 		}
 	}
 
-	entries, err := store.ListEntries("both", 3, 0)
+	entries, err := store.ListEntries(ListOptions{Limit: 3})
 	if err != nil {
 		t.Fatalf("ListEntries error: %v", err)
 	}
@@ -253,7 +256,7 @@ func TestJournalDaysFilter(t *testing.T) {
 	}
 
 	// List with 1 day filter should include today's entry
-	entries, err := store.ListEntries("both", 0, 1)
+	entries, err := store.ListEntries(ListOptions{Since: query.DaysAgo(1, time.Now())})
 	if err != nil {
 		t.Fatalf("ListEntries error: %v", err)
 	}
@@ -263,6 +266,121 @@ func TestJournalDaysFilter(t *testing.T) {
 	}
 }
 
+func TestJournalListOptions(t *testing.T) {
+	tmpDir := t.TempDir()
+	store, err := NewJournalMDStore(filepath.Join(tmpDir, "project-journal"), filepath.Join(tmpDir, "user-journal"))
+	if err != nil {
+		t.Fatalf("NewJournalMDStore error: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.Local)
+	for i := 0; i < 5; i++ {
+		entry := &models.JournalEntry{
+			ID:        uuid.New(),
+			Sections:  map[string]string{"feelings": fmt.Sprintf("day %d", i)},
+			CreatedAt: base.AddDate(0, 0, i),
+			Type:      "user",
+		}
+		if err := store.WriteEntry(entry); err != nil {
+			t.Fatalf("WriteEntry error: %v", err)
+		}
+	}
+
+	days := func(entries []*models.JournalEntry) []string {
+		var out []string
+		for _, e := range entries {
+			out = append(out, e.Sections["feelings"])
+		}
+		return out
+	}
+
+	entries, err := store.ListEntries(ListOptions{
+		Since: base.AddDate(0, 0, 1),
+		Until: base.AddDate(0, 0, 4),
+		Order: OldestFirst,
+	})
+	if err != nil {
+		t.Fatalf("ListEntries error: %v", err)
+	}
+	if got := strings.Join(days(entries), ","); got != "day 1,day 2,day 3" {
+		t.Errorf("since inclusive, until exclusive, oldest first: got %s", got)
+	}
+
+	entries, err = store.ListEntries(ListOptions{Offset: 1, Limit: 2})
+	if err != nil {
+		t.Fatalf("ListEntries error: %v", err)
+	}
+	if got := strings.Join(days(entries), ","); got != "day 3,day 2" {
+		t.Errorf("offset and limit page newest first: got %s", got)
+	}
+
+	entries, err = store.ListEntries(ListOptions{Offset: 10})
+	if err != nil {
+		t.Fatalf("ListEntries error: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("offset past the end should return nothing, got %d", len(entries))
+	}
+
+	if _, err := store.ListEntries(ListOptions{Offset: -1}); err == nil {
+		t.Error("expected error for negative offset")
+	}
+}
+
+func TestJournalListAfterCursor(t *testing.T) {
+	tmpDir := t.TempDir()
+	store, err := NewJournalMDStore(filepath.Join(tmpDir, "project-journal"), filepath.Join(tmpDir, "user-journal"))
+	if err != nil {
+		t.Fatalf("NewJournalMDStore error: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	// Entries sharing a timestamp, as split siblings do, must still page
+	// without repeats or gaps
+	created := time.Now().Truncate(time.Second)
+	for i := 0; i < 5; i++ {
+		entry := &models.JournalEntry{
+			ID:        uuid.New(),
+			Sections:  map[string]string{"feelings": fmt.Sprintf("entry %d", i)},
+			CreatedAt: created.Add(time.Duration(i/2) * time.Minute),
+			Type:      "user",
+		}
+		if err := store.WriteEntry(entry); err != nil {
+			t.Fatalf("WriteEntry error: %v", err)
+		}
+	}
+
+	for _, order := range []SortOrder{NewestFirst, OldestFirst} {
+		var paged []*models.JournalEntry
+		var after models.Cursor
+		for {
+			page, err := store.ListEntries(ListOptions{Limit: 2, Order: order, After: after})
+			if err != nil {
+				t.Fatalf("ListEntries error: %v", err)
+			}
+			if len(page) == 0 {
+				break
+			}
+			paged = append(paged, page...)
+			after = models.CursorAt(page[len(page)-1])
+		}
+
+		all, err := store.ListEntries(ListOptions{Order: order})
+		if err != nil {
+			t.Fatalf("ListEntries error: %v", err)
+		}
+		if len(paged) != len(all) {
+			t.Fatalf("order %d: paged %d entries, want %d", order, len(paged), len(all))
+		}
+		for i := range all {
+			if paged[i].ID != all[i].ID {
+				t.Errorf("order %d: page position %d is %s, want %s", order, i, paged[i].ID, all[i].ID)
+			}
+		}
+	}
+}
+
 func TestJournalSectionParsing(t *testing.T) {
 	tmpDir := t.TempDir()
 	projectDir := filepath.Join(tmpDir, "project-journal")
@@ -406,7 +524,7 @@ func TestJournalEmptyRoots(t *testing.T) {
 	defer func() { _ = store.Close() }()
 
 	// Listing non-existent roots should return empty, not error
-	entries, err := store.ListEntries("both", 0, 0)
+	entries, err := store.ListEntries(ListOptions{})
 	if err != nil {
 		t.Fatalf("ListEntries error: %v", err)
 	}
@@ -414,3 +532,355 @@ func TestJournalEmptyRoots(t *testing.T) {
 		t.Errorf("expected 0 entries, got %d", len(entries))
 	}
 }
+
+func TestJournalWriteEntryWritesEmbeddingSidecar(t *testing.T) {
+	tmpDir := t.TempDir()
+	store, err := NewJournalMDStore(
+		filepath.Join(tmpDir, "project-journal"),
+		filepath.Join(tmpDir, "user-journal"),
+		WithEmbedder(embeddings.NewHashEmbedder(64)),
+	)
+	if err != nil {
+		t.Fatalf("NewJournalMDStore error: %v", err)
+	}
+
+	entry := models.NewJournalEntry(map[string]string{"feelings": "calm and focused"}, "user")
+	if err := store.WriteEntry(entry); err != nil {
+		t.Fatalf("WriteEntry error: %v", err)
+	}
+
+	embPath := strings.TrimSuffix(entry.FilePath, ".md") + ".embedding"
+	if _, err := os.Stat(embPath); err != nil {
+		t.Fatalf("expected embedding sidecar at %s: %v", embPath, err)
+	}
+
+	// Sidecars must not show up as entries
+	entries, err := store.ListEntries(ListOptions{})
+	if err != nil {
+		t.Fatalf("ListEntries error: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected 1 entry, got %d", len(entries))
+	}
+}
+
+func TestJournalSearchSubstringWithoutEmbedder(t *testing.T) {
+	tmpDir := t.TempDir()
+	store, _ := NewJournalMDStore(filepath.Join(tmpDir, "project"), filepath.Join(tmpDir, "user"))
+
+	for _, text := range []string{"fixed the flaky test", "lunch was good"} {
+		if err := store.WriteEntry(models.NewJournalEntry(map[string]string{"feelings": text}, "user")); err != nil {
+			t.Fatalf("WriteEntry error: %v", err)
+		}
+	}
+
+	results, err := store.Search("FLAKY", embeddings.SearchOptions{Type: "both"})
+	if err != nil {
+		t.Fatalf("Search error: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
+	}
+	if results[0].Entry == nil || results[0].Entry.Sections["feelings"] != "fixed the flaky test" {
+		t.Errorf("unexpected result entry: %+v", results[0].Entry)
+	}
+}
+
+func TestJournalSearchRanksBySimilarity(t *testing.T) {
+	tmpDir := t.TempDir()
+	store, _ := NewJournalMDStore(
+		filepath.Join(tmpDir, "project"),
+		filepath.Join(tmpDir, "user"),
+		WithEmbedder(embeddings.NewHashEmbedder(embeddings.DefaultDimension)),
+	)
+
+	texts := []string{
+		"Spent the afternoon migrating the database schema to the new layout",
+		"The weather was lovely and I went for a long walk in the park",
+	}
+	for _, text := range texts {
+		if err := store.WriteEntry(models.NewJournalEntry(map[string]string{"technical_insights": text}, "user")); err != nil {
+			t.Fatalf("WriteEntry error: %v", err)
+		}
+	}
+
+	// No literal substring hit: the match comes from embeddings alone
+	results, err := store.Search("database migrations", embeddings.SearchOptions{})
+	if err != nil {
+		t.Fatalf("Search error: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected 1 semantic result, got %d", len(results))
+	}
+	if results[0].Entry.Sections["technical_insights"] != texts[0] {
+		t.Errorf("expected migration entry, got %q", results[0].Entry.Sections["technical_insights"])
+	}
+	if results[0].Score <= 0 {
+		t.Errorf("expected positive score, got %f", results[0].Score)
+	}
+}
+
+func TestRenderSectionsFollowsSchema(t *testing.T) {
+	defaults := models.DefaultSections()
+	t.Cleanup(func() { _ = models.SetSections(defaults) })
+	custom := []models.SectionDef{
+		{Name: "retro", Route: "user"},
+		{Name: "feelings", Route: "user"},
+	}
+	if err := models.SetSections(custom); err != nil {
+		t.Fatalf("SetSections error: %v", err)
+	}
+
+	body := renderSections(map[string]string{
+		"feelings":      "ok",
+		"retro":         "went well",
+		"project_notes": "no longer in the schema",
+	})
+
+	retro := strings.Index(body, "## Retro")
+	feelings := strings.Index(body, "## Feelings")
+	legacy := strings.Index(body, "## Project Notes")
+	if retro < 0 || feelings < 0 || legacy < 0 {
+		t.Fatalf("expected all sections rendered, got:\n%s", body)
+	}
+	if !(retro < feelings && feelings < legacy) {
+		t.Errorf("expected schema order then undeclared sections, got:\n%s", body)
+	}
+}
+
+func TestJournalMetadataRoundtripAndFilter(t *testing.T) {
+	tmpDir := t.TempDir()
+	store, _ := NewJournalMDStore(filepath.Join(tmpDir, "project"), filepath.Join(tmpDir, "user"))
+
+	valence := -0.5
+	written, err := store.WriteSections(map[string]string{"feelings": "the outage rattled me"}, RouteOptions{
+		Meta: models.EntryMeta{
+			Tags:       []string{"Incident", "incident", "db"},
+			Mood:       "rattled",
+			Valence:    &valence,
+			Agent:      "claude",
+			Repo:       "pulse",
+			Branch:     "main",
+			Commit:     "abc1234",
+			Attributes: map[string]string{"ticket": "OPS-1"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("WriteSections error: %v", err)
+	}
+	if _, err := store.WriteSections(map[string]string{"feelings": "quiet day"}, RouteOptions{
+		Meta: models.EntryMeta{Branch: "dev"},
+	}); err != nil {
+		t.Fatalf("WriteSections error: %v", err)
+	}
+
+	got, err := store.ReadEntry(written[0].FilePath)
+	if err != nil {
+		t.Fatalf("ReadEntry error: %v", err)
+	}
+	if len(got.Tags) != 2 || got.Tags[0] != "incident" || got.Tags[1] != "db" {
+		t.Errorf("Tags = %v, want normalized [incident db]", got.Tags)
+	}
+	if got.Valence == nil || *got.Valence != -0.5 || got.Mood != "rattled" || got.Agent != "claude" {
+		t.Errorf("mood metadata not round-tripped: %+v", got.EntryMeta)
+	}
+	if got.Repo != "pulse" || got.Branch != "main" || got.Commit != "abc1234" || got.Attributes["ticket"] != "OPS-1" {
+		t.Errorf("context metadata not round-tripped: %+v", got.EntryMeta)
+	}
+
+	entries, err := store.ListEntries(ListOptions{Filter: models.MetaFilter{Tags: []string{"incident"}, Branch: "main"}})
+	if err != nil {
+		t.Fatalf("ListEntries error: %v", err)
+	}
+	if len(entries) != 1 || entries[0].ID != got.ID {
+		t.Errorf("expected only the tagged entry, got %d entries", len(entries))
+	}
+
+	results, err := store.Search("day", embeddings.SearchOptions{Filter: models.MetaFilter{Branch: "main"}})
+	if err != nil {
+		t.Fatalf("Search error: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("expected branch filter to exclude the dev entry, got %d results", len(results))
+	}
+
+	tooHigh := 2.0
+	if _, err := store.WriteSections(map[string]string{"feelings": "x"}, RouteOptions{
+		Meta: models.EntryMeta{Valence: &tooHigh},
+	}); err == nil {
+		t.Error("expected error for out-of-range valence")
+	}
+}
+
+func TestJournalSearchQueryLanguage(t *testing.T) {
+	tmpDir := t.TempDir()
+	store, _ := NewJournalMDStore(filepath.Join(tmpDir, "project"), filepath.Join(tmpDir, "user"),
+		WithEmbedder(embeddings.NewHashEmbedder(embeddings.DefaultDimension)))
+
+	write := func(sections map[string]string, tags ...string) {
+		t.Helper()
+		if _, err := store.WriteSections(sections, RouteOptions{Meta: models.EntryMeta{Tags: tags}}); err != nil {
+			t.Fatalf("WriteSections error: %v", err)
+		}
+	}
+	write(map[string]string{"feelings": "the flaky test suite wore me down"}, "ci")
+	write(map[string]string{"project_notes": "flaky test fixed before the deploy"}, "ci")
+	write(map[string]string{"feelings": "the outage was stressful"}, "incident")
+
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{"tag:ci", 2},
+		{`"flaky test" -deploy`, 1},
+		{"flaky AND type:project", 1},
+		{"tag:incident OR deploy", 2},
+		{"section:feelings NOT outage", 1},
+		{"the AND outage", 1},
+	}
+	for _, tt := range tests {
+		results, err := store.Search(tt.query, embeddings.SearchOptions{})
+		if err != nil {
+			t.Fatalf("Search(%q) error: %v", tt.query, err)
+		}
+		if len(results) != tt.want {
+			t.Errorf("Search(%q) returned %d results, want %d", tt.query, len(results), tt.want)
+		}
+	}
+
+	if _, err := store.Search("flaky OR", embeddings.SearchOptions{}); err == nil || !strings.Contains(err.Error(), "column 9") {
+		t.Errorf("expected a syntax error at column 9, got %v", err)
+	}
+}
+
+func TestNamedJournals(t *testing.T) {
+	t.Cleanup(func() { _ = models.SetSections(models.DefaultSections()) })
+	if err := models.SetSections(append(models.DefaultSections(), models.SectionDef{Name: "pages", Route: "oncall"})); err != nil {
+		t.Fatalf("SetSections error: %v", err)
+	}
+
+	tmpDir := t.TempDir()
+	workDir := filepath.Join(tmpDir, "work")
+	store, err := NewJournalMDStore(filepath.Join(tmpDir, "project"), filepath.Join(tmpDir, "user"),
+		WithJournals(map[string]string{"work": workDir, "oncall": filepath.Join(tmpDir, "oncall")}), WithGitContext(false))
+	if err != nil {
+		t.Fatalf("NewJournalMDStore error: %v", err)
+	}
+	if got := strings.Join(store.Journals(), ","); got != "project,user,oncall,work" {
+		t.Errorf("Journals() = %s", got)
+	}
+
+	work := models.NewJournalEntry(map[string]string{"technical_insights": "quarterly planning for the billing rewrite"}, "work")
+	if err := store.WriteEntry(work); err != nil {
+		t.Fatalf("WriteEntry error: %v", err)
+	}
+	if !strings.HasPrefix(work.FilePath, workDir+string(filepath.Separator)) {
+		t.Errorf("expected the entry under the work root, got %s", work.FilePath)
+	}
+	entries, err := store.WriteSections(map[string]string{"pages": "paged at 3am for billing", "feelings": "tired"}, RouteOptions{})
+	if err != nil || len(entries) != 2 || entries[0].Type != "user" || entries[1].Type != "oncall" {
+		t.Fatalf("expected user and oncall entries, got %+v, %v", entries, err)
+	}
+
+	for _, tt := range []struct {
+		selector string
+		want     int
+	}{
+		{"work", 1},
+		{"oncall,work", 2},
+		{"all", 3},
+		{"both", 3},
+		{"project", 0},
+	} {
+		listed, err := store.ListEntries(ListOptions{Type: tt.selector})
+		if err != nil || len(listed) != tt.want {
+			t.Errorf("ListEntries(%q) = %d entries, %v; want %d", tt.selector, len(listed), err, tt.want)
+		}
+	}
+	if _, err := store.ListEntries(ListOptions{Type: "team"}); err == nil {
+		t.Error("expected an unknown journal to be rejected")
+	}
+
+	results, err := store.Search("billing type:oncall", embeddings.SearchOptions{})
+	if err != nil || len(results) != 1 || results[0].Entry.Type != "oncall" {
+		t.Errorf("expected one oncall result, got %d, %v", len(results), err)
+	}
+	read, err := store.ReadEntry(work.FilePath)
+	if err != nil || read.Type != "work" {
+		t.Errorf("expected to read the work entry back, got %+v, %v", read, err)
+	}
+
+	if _, err := store.WriteSections(map[string]string{"feelings": "x"}, RouteOptions{Type: "team"}); err == nil {
+		t.Error("expected an unknown journal override to be rejected")
+	}
+	if _, err := store.Search("billing type:team", embeddings.SearchOptions{}); err == nil || !strings.Contains(err.Error(), `invalid type "team"`) {
+		t.Errorf("expected a query naming an unknown journal to be rejected, got %v", err)
+	}
+	if _, err := NewJournalMDStore(tmpDir, tmpDir, WithJournals(map[string]string{"all": tmpDir})); err == nil {
+		t.Error("expected a reserved journal name to be rejected")
+	}
+	if _, err := NewJournalMDStore(tmpDir, tmpDir, WithJournals(map[string]string{"work": workDir})); err == nil || !strings.Contains(err.Error(), `routes to unknown journal "oncall"`) {
+		t.Errorf("expected a section routed to a missing journal to be rejected, got %v", err)
+	}
+}
+
+func TestJournalSharedRootFiltersByType(t *testing.T) {
+	// Running from the home directory gives project and user one root
+	root := filepath.Join(t.TempDir(), "journal")
+	store, err := NewJournalMDStore(root, root, WithGitContext(false))
+	if err != nil {
+		t.Fatalf("NewJournalMDStore error: %v", err)
+	}
+
+	user := models.NewJournalEntry(map[string]string{"feelings": "calm about the deploy"}, "user")
+	project := models.NewJournalEntry(map[string]string{"technical_insights": "the deploy script needs a retry"}, "project")
+	for _, entry := range []*models.JournalEntry{user, project} {
+		if err := store.WriteEntry(entry); err != nil {
+			t.Fatalf("WriteEntry error: %v", err)
+		}
+	}
+	// Entries written before types were recorded belong to the root
+	now := time.Now()
+	legacyID := uuid.New()
+	legacy := fmt.Sprintf("---\nid: %s\ndate: %s\n---\n\n## Feelings\n\nunsure about the deploy\n", legacyID, now.Format(time.RFC3339))
+	legacyPath := filepath.Join(root, now.Format("2006-01-02"), now.Format("15-04-05")+"-000000-"+legacyID.String()[:8]+".md")
+	if err := os.WriteFile(legacyPath, []byte(legacy), 0o600); err != nil {
+		t.Fatalf("WriteFile error: %v", err)
+	}
+
+	for _, tt := range []struct {
+		selector string
+		want     int
+	}{
+		{"user", 2},
+		{"project", 2},
+		{"all", 3},
+	} {
+		listed, err := store.ListEntries(ListOptions{Type: tt.selector})
+		if err != nil || len(listed) != tt.want {
+			t.Errorf("ListEntries(%q) = %d entries, %v; want %d", tt.selector, len(listed), err, tt.want)
+		}
+		results, err := store.Search("deploy", embeddings.SearchOptions{Type: tt.selector})
+		if err != nil || len(results) != tt.want {
+			t.Errorf("Search(type %q) = %d results, %v; want %d", tt.selector, len(results), err, tt.want)
+		}
+		for _, result := range results {
+			if result.Entry.Type != "" && tt.selector != "all" && result.Entry.Type != tt.selector {
+				t.Errorf("Search(type %q) returned a %s entry", tt.selector, result.Entry.Type)
+			}
+		}
+	}
+
+	for _, entry := range []*models.JournalEntry{user, project} {
+		if _, err := store.DeleteEntry(entry.FilePath, ""); err != nil {
+			t.Fatalf("DeleteEntry error: %v", err)
+		}
+	}
+	trashed, err := store.ListTrash("user")
+	if err != nil || len(trashed) != 1 || trashed[0].ID != user.ID {
+		t.Errorf("expected only the user entry in the user trash, got %d, %v", len(trashed), err)
+	}
+	if trashed, _ := store.ListTrash("all"); len(trashed) != 2 {
+		t.Errorf("expected both entries in the trash, got %d", len(trashed))
+	}
+}
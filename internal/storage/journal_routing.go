@@ -0,0 +1,155 @@
+// ABOUTME: Routing policy that splits journal sections between journals by each section's route.
+// ABOUTME: Shared by the MCP process_thoughts tool and the CLI; split entries share a thought_id.
+package storage
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/2389-research/pulse/internal/models"
+	"github.com/2389-research/pulse/internal/redact"
+)
+
+// RouteOptions controls how WriteSections turns sections into entries.
+type RouteOptions struct {
+	// Type forces every section into one entry in this journal, e.g.
+	// "project", "user", or a configured one. Empty routes each section by
+	// its configured route.
+	Type string
+
+	// Linked records each written entry's ID in the others when the
+	// sections are split across journals.
+	Linked bool
+
+	// Meta is recorded on every written entry. Tags are normalized.
+	Meta models.EntryMeta
+}
+
+// RouteSections splits sections into per-type buckets. With an override type
+// all sections land in that bucket; otherwise each follows its configured
+// route, and sections the schema does not declare go to the user journal.
+func RouteSections(sections map[string]string, override string) map[string]map[string]string {
+	buckets := make(map[string]map[string]string)
+	for name, content := range sections {
+		entryType := override
+		if entryType == "" {
+			entryType = "user"
+			if def, ok := models.LookupSection(name); ok {
+				entryType = def.Route
+			}
+		}
+		if buckets[entryType] == nil {
+			buckets[entryType] = make(map[string]string)
+		}
+		buckets[entryType][name] = content
+	}
+	return buckets
+}
+
+// WriteSections routes sections into one entry per journal and writes them
+// in Journals order, project first. Returns the written entries.
+func (s *JournalMDStore) WriteSections(sections map[string]string, opts RouteOptions) ([]*models.JournalEntry, error) {
+	if _, ok := s.journalRoot(opts.Type); opts.Type != "" && !ok {
+		return nil, fmt.Errorf("invalid type %q: must be one of: %s", opts.Type, strings.Join(s.Journals(), ", "))
+	}
+	if len(sections) == 0 {
+		return nil, fmt.Errorf("at least one section is required")
+	}
+	if err := opts.Meta.Validate(); err != nil {
+		return nil, err
+	}
+	opts.Meta.Tags = models.NormalizeTags(opts.Meta.Tags)
+
+	// Refuse blocked text before writing anything, so a split write is never half done
+	if s.redactor.Mode() == redact.ModeBlock {
+		if _, _, err := s.redactor.Sections(sections); err != nil {
+			return nil, err
+		}
+	}
+
+	buckets := RouteSections(sections, opts.Type)
+	for entryType := range buckets {
+		if _, ok := s.journalRoot(entryType); !ok {
+			return nil, fmt.Errorf("sections route to unknown journal %q", entryType)
+		}
+	}
+	var entries []*models.JournalEntry
+	for _, entryType := range s.Journals() {
+		if bucket, ok := buckets[entryType]; ok {
+			entry := models.NewJournalEntry(bucket, entryType)
+			entry.EntryMeta = opts.Meta
+			entries = append(entries, entry)
+		}
+	}
+
+	// Split entries share one thought ID and timestamp so they read as one thought
+	if len(entries) > 1 {
+		thoughtID := uuid.New()
+		for _, entry := range entries {
+			entry.ThoughtID = thoughtID
+			entry.CreatedAt = entries[0].CreatedAt
+		}
+	}
+
+	if opts.Linked && len(entries) > 1 {
+		for _, entry := range entries {
+			for _, other := range entries {
+				if other != entry {
+					entry.LinkedIDs = append(entry.LinkedIDs, other.ID)
+				}
+			}
+		}
+	}
+
+	for i, entry := range entries {
+		if err := s.WriteEntry(entry); err != nil {
+			return entries[:i], fmt.Errorf("failed to write %s entry: %w", entry.Type, err)
+		}
+	}
+	return entries, nil
+}
+
+// ReadThought reads the entry at path together with every entry sharing its
+// thought ID, across every journal, project entries first.
+func (s *JournalMDStore) ReadThought(path string) ([]*models.JournalEntry, error) {
+	entry, err := s.ReadEntry(path)
+	if err != nil {
+		return nil, err
+	}
+	if entry.ThoughtID == uuid.Nil {
+		return []*models.JournalEntry{entry}, nil
+	}
+
+	// Siblings share a timestamp, so they live in the same date directory
+	refs, err := s.indexedEntries("all", filepath.Base(filepath.Dir(entry.FilePath)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find thought siblings: %w", err)
+	}
+	var paths []string
+	for _, ref := range refs {
+		if ref.meta.ThoughtID == entry.ThoughtID.String() && ref.meta.ID != entry.ID.String() {
+			paths = append(paths, ref.path)
+		}
+	}
+
+	entries := append([]*models.JournalEntry{entry}, s.readEntryFiles(paths)...)
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Type == "project" && entries[j].Type != "project"
+	})
+	return entries, nil
+}
+
+// parseLinkedIDs converts frontmatter linked IDs, skipping malformed ones.
+func parseLinkedIDs(ids []string) []uuid.UUID {
+	var out []uuid.UUID
+	for _, id := range ids {
+		if parsed, err := uuid.Parse(id); err == nil {
+			out = append(out, parsed)
+		}
+	}
+	return out
+}
@@ -0,0 +1,166 @@
+// ABOUTME: Tests for the shared section routing policy.
+// ABOUTME: Covers per-section routes, type overrides, and linked entry pairs.
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/2389-research/pulse/internal/models"
+)
+
+func TestRouteSections(t *testing.T) {
+	sections := map[string]string{
+		"feelings":      "fine",
+		"project_notes": "refactor storage",
+		"unknown":       "kept",
+	}
+
+	buckets := RouteSections(sections, "")
+	if len(buckets["project"]) != 1 || buckets["project"]["project_notes"] == "" {
+		t.Errorf("expected project_notes in project bucket, got %v", buckets["project"])
+	}
+	if len(buckets["user"]) != 2 {
+		t.Errorf("expected feelings and undeclared sections in user bucket, got %v", buckets["user"])
+	}
+
+	buckets = RouteSections(sections, "project")
+	if len(buckets) != 1 || len(buckets["project"]) != 3 {
+		t.Errorf("expected override to put everything in project, got %v", buckets)
+	}
+}
+
+func TestWriteSectionsRoutesByConfig(t *testing.T) {
+	defaults := models.DefaultSections()
+	t.Cleanup(func() { _ = models.SetSections(defaults) })
+	custom := models.DefaultSections()
+	for i := range custom {
+		if custom[i].Name == "technical_insights" {
+			custom[i].Route = "project"
+		}
+	}
+	if err := models.SetSections(custom); err != nil {
+		t.Fatalf("SetSections error: %v", err)
+	}
+
+	tmpDir := t.TempDir()
+	store, _ := NewJournalMDStore(filepath.Join(tmpDir, "project"), filepath.Join(tmpDir, "user"))
+
+	entries, err := store.WriteSections(map[string]string{
+		"feelings":           "good",
+		"technical_insights": "routes are configurable",
+	}, RouteOptions{})
+	if err != nil {
+		t.Fatalf("WriteSections error: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Type != "project" || entries[1].Type != "user" {
+		t.Fatalf("expected project then user entries, got %+v", entries)
+	}
+	if _, ok := entries[0].Sections["technical_insights"]; !ok {
+		t.Errorf("expected technical_insights routed to project, got %v", entries[0].Sections)
+	}
+	if len(entries[0].LinkedIDs) != 0 {
+		t.Error("expected no links without the Linked option")
+	}
+}
+
+func TestWriteSectionsTypeOverride(t *testing.T) {
+	tmpDir := t.TempDir()
+	store, _ := NewJournalMDStore(filepath.Join(tmpDir, "project"), filepath.Join(tmpDir, "user"))
+
+	entries, err := store.WriteSections(map[string]string{
+		"feelings":      "good",
+		"project_notes": "one file please",
+	}, RouteOptions{Type: "user"})
+	if err != nil {
+		t.Fatalf("WriteSections error: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Type != "user" || len(entries[0].Sections) != 2 {
+		t.Fatalf("expected a single user entry, got %+v", entries)
+	}
+
+	if _, err := store.WriteSections(map[string]string{"feelings": "x"}, RouteOptions{Type: "both"}); err == nil {
+		t.Error("expected error for invalid type override")
+	}
+}
+
+func TestWriteSectionsLinkedPair(t *testing.T) {
+	tmpDir := t.TempDir()
+	store, _ := NewJournalMDStore(filepath.Join(tmpDir, "project"), filepath.Join(tmpDir, "user"))
+
+	entries, err := store.WriteSections(map[string]string{
+		"feelings":      "relieved",
+		"project_notes": "migration done",
+	}, RouteOptions{Linked: true})
+	if err != nil {
+		t.Fatalf("WriteSections error: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+
+	project, err := store.ReadEntry(entries[0].FilePath)
+	if err != nil {
+		t.Fatalf("ReadEntry error: %v", err)
+	}
+	user, err := store.ReadEntry(entries[1].FilePath)
+	if err != nil {
+		t.Fatalf("ReadEntry error: %v", err)
+	}
+	if len(project.LinkedIDs) != 1 || project.LinkedIDs[0] != user.ID {
+		t.Errorf("project entry links = %v, want [%s]", project.LinkedIDs, user.ID)
+	}
+	if len(user.LinkedIDs) != 1 || user.LinkedIDs[0] != project.ID {
+		t.Errorf("user entry links = %v, want [%s]", user.LinkedIDs, project.ID)
+	}
+}
+
+func TestWriteSectionsSharesThought(t *testing.T) {
+	tmpDir := t.TempDir()
+	store, _ := NewJournalMDStore(filepath.Join(tmpDir, "project"), filepath.Join(tmpDir, "user"))
+
+	entries, err := store.WriteSections(map[string]string{
+		"feelings":      "curious",
+		"project_notes": "thought IDs tie halves together",
+	}, RouteOptions{})
+	if err != nil {
+		t.Fatalf("WriteSections error: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].ThoughtID == uuid.Nil || entries[0].ThoughtID != entries[1].ThoughtID {
+		t.Errorf("expected a shared thought ID, got %s and %s", entries[0].ThoughtID, entries[1].ThoughtID)
+	}
+	if !entries[0].CreatedAt.Equal(entries[1].CreatedAt) {
+		t.Error("expected split entries to share a creation time")
+	}
+
+	thought, err := store.ReadThought(entries[1].FilePath)
+	if err != nil {
+		t.Fatalf("ReadThought error: %v", err)
+	}
+	if len(thought) != 2 || thought[0].Type != "project" || thought[1].Type != "user" {
+		t.Fatalf("expected project then user entries, got %+v", thought)
+	}
+	if thought[0].ThoughtID != entries[0].ThoughtID {
+		t.Errorf("thought ID not persisted: %s", thought[0].ThoughtID)
+	}
+
+	single, err := store.WriteSections(map[string]string{"feelings": "alone"}, RouteOptions{})
+	if err != nil {
+		t.Fatalf("WriteSections error: %v", err)
+	}
+	if single[0].ThoughtID != uuid.Nil {
+		t.Errorf("expected no thought ID for an unsplit write, got %s", single[0].ThoughtID)
+	}
+	thought, err = store.ReadThought(single[0].FilePath)
+	if err != nil {
+		t.Fatalf("ReadThought error: %v", err)
+	}
+	if len(thought) != 1 {
+		t.Errorf("expected only the entry itself, got %d", len(thought))
+	}
+}
@@ -1,22 +1,106 @@
 // ABOUTME: Interface definition for journal entry storage.
-// ABOUTME: Defines the contract for reading, writing, and listing journal entries.
+// ABOUTME: Defines the contract for reading, writing, editing, listing, and searching journal entries.
 package storage
 
 import (
+	"time"
+
+	"github.com/2389-research/pulse/internal/embeddings"
 	"github.com/2389-research/pulse/internal/models"
 )
 
+// EmbedOptions configures an embedding backfill.
+type EmbedOptions struct {
+	Workers  int                                // concurrent embedders; <= 0 uses the CPU count
+	DryRun   bool                               // report what would be written without writing
+	Force    bool                               // regenerate every sidecar, even current ones
+	Progress func(done, total int, path string) // optional; called after each entry is processed
+}
+
+// EmbedReport summarizes an embedding backfill.
+type EmbedReport struct {
+	Scanned  int // entries examined
+	Missing  int // entries without a sidecar
+	Outdated int // sidecars from another model, unreadable, or older than their entry
+	Written  int // sidecars (re)generated
+	Failed   int // sidecars that could not be written
+}
+
 // JournalStore defines operations for journal entry persistence.
 type JournalStore interface {
+	// Journals returns the names of the store's journals: project, user, then
+	// any others configured. Type selectors are checked against them.
+	Journals() []string
+
 	// WriteEntry persists a journal entry to disk.
 	WriteEntry(entry *models.JournalEntry) error
 
+	// WriteSections routes sections into project and user entries according to
+	// the section schema (or opts.Type) and writes them.
+	WriteSections(sections map[string]string, opts RouteOptions) ([]*models.JournalEntry, error)
+
 	// ReadEntry reads a journal entry from the given file path.
 	ReadEntry(path string) (*models.JournalEntry, error)
 
-	// ListEntries lists journal entries, filtered by type ("project", "user", or "both").
-	// limit caps the number of results. days limits how far back to look (0 = no limit).
-	ListEntries(entryType string, limit int, days int) ([]*models.JournalEntry, error)
+	// UpdateEntry replaces the given sections of an existing entry; empty values
+	// remove a section. The entry keeps its ID and gains an updated_at stamp.
+	UpdateEntry(path string, sections map[string]string) (*models.JournalEntry, error)
+
+	// AppendToSection appends text to one section of an existing entry.
+	AppendToSection(path, section, text string) (*models.JournalEntry, error)
+
+	// DeleteEntry moves an entry into the trash, recording the deletion time and
+	// reason. The returned entry's FilePath points into the trash.
+	DeleteEntry(path, reason string) (*models.JournalEntry, error)
+
+	// RestoreEntry moves a trashed entry back into the journal. path may be the
+	// entry's trash path or its original path.
+	RestoreEntry(path string) (*models.JournalEntry, error)
+
+	// ListTrash lists trashed entries filtered by type, most recently deleted first.
+	ListTrash(entryType string) ([]*models.JournalEntry, error)
+
+	// EmptyTrash permanently removes entries trashed more than olderThan ago
+	// (all of them if zero). Returns the number removed.
+	EmptyTrash(olderThan time.Duration) (int, error)
+
+	// ReadThought reads an entry together with the entries split from the same
+	// write (sharing its thought ID) in either root.
+	ReadThought(path string) ([]*models.JournalEntry, error)
+
+	// ListEntries lists journal entries by type, creation time range, and
+	// metadata, sorted and paged as opts asks.
+	ListEntries(opts ListOptions) ([]*models.JournalEntry, error)
+
+	// Search returns entries matching query, ranked by relevance.
+	Search(query string, opts embeddings.SearchOptions) ([]embeddings.SearchResult, error)
+
+	// ImportEntries writes entries read from another source, keeping their IDs
+	// and creation times and skipping duplicates by ID or content.
+	ImportEntries(entries []*models.JournalEntry, opts ImportOptions) (*ImportReport, error)
+
+	// Reindex rebuilds any search and listing indexes from the entries on disk.
+	// Returns the number of entries indexed.
+	Reindex() (int, error)
+
+	// BackfillEmbeddings writes missing or outdated embedding sidecars.
+	BackfillEmbeddings(opts EmbedOptions) (*EmbedReport, error)
+
+	// EncryptAll encrypts the body of every entry not yet encrypted, including
+	// trashed and archived ones. Requires a passphrase.
+	EncryptAll() (*EncryptionReport, error)
+
+	// DecryptAll rewrites every encrypted entry with a plaintext body.
+	// Requires a passphrase.
+	DecryptAll() (*EncryptionReport, error)
+
+	// GC applies retention rules as of now: deleting old entries or sections
+	// and archiving old days into compressed bundles. dryRun only counts.
+	GC(rules []RetentionRule, now time.Time, dryRun bool) (*GCReport, error)
+
+	// CheckGitExposure reports, for each journal root inside a git work tree,
+	// whether git ignores it and which of its files git tracks.
+	CheckGitExposure() []GitExposure
 
 	// Close releases any resources held by the store.
 	Close() error
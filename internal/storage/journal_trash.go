@@ -0,0 +1,219 @@
+// ABOUTME: Soft deletion of journal entries into a .trash/ directory inside each root.
+// ABOUTME: Records deleted_at and delete_reason in frontmatter and supports listing, restoring, and emptying.
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/harperreed/mdstore"
+
+	"github.com/2389-research/pulse/internal/embeddings"
+	"github.com/2389-research/pulse/internal/models"
+)
+
+// trashDirName is the directory inside each journal root holding deleted
+// entries. It mirrors the root's date layout: .trash/YYYY-MM-DD/<file>.md.
+const trashDirName = ".trash"
+
+// isTrashed reports whether a root-relative slash path lies in the trash.
+func isTrashed(rel string) bool {
+	return strings.HasPrefix(rel, trashDirName+"/")
+}
+
+// DeleteEntry moves the entry at path and its embedding sidecar into the
+// root's trash, recording when and why it was deleted. The returned entry's
+// FilePath points into the trash.
+func (s *JournalMDStore) DeleteEntry(path, reason string) (*models.JournalEntry, error) {
+	loc, err := s.resolveEntryPath(path)
+	if err != nil {
+		if _, archErr := s.resolveArchivedPath(path); archErr == nil {
+			return nil, errArchived(path)
+		}
+		return nil, err
+	}
+	if isTrashed(loc.rel) {
+		return nil, fmt.Errorf("entry %q is already in the trash", path)
+	}
+	trashPath := filepath.Join(loc.absRoot, trashDirName, filepath.FromSlash(loc.rel))
+
+	var entry *models.JournalEntry
+	err = mdstore.WithLock(filepath.Dir(loc.path), func() error {
+		entry, err = s.readEntryFile(loc.path)
+		if err != nil {
+			return err
+		}
+
+		entry.DeletedAt = time.Now()
+		entry.DeleteReason = reason
+		content, err := s.renderEntry(loc.root, entry)
+		if err != nil {
+			return err
+		}
+		if err := mdstore.AtomicWrite(trashPath, []byte(content)); err != nil {
+			return fmt.Errorf("failed to write trashed entry: %w", err)
+		}
+		if err := moveSidecar(loc.path, trashPath); err != nil {
+			return err
+		}
+		if err := os.Remove(loc.path); err != nil {
+			return fmt.Errorf("failed to remove entry: %w", err)
+		}
+		entry.FilePath = trashPath
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.withIndex(loc.root, nil); err != nil {
+		return nil, fmt.Errorf("failed to update index: %w", err)
+	}
+	return entry, nil
+}
+
+// RestoreEntry moves a trashed entry back to its original location and clears
+// its deletion metadata. path may be the entry's trash path or its original path.
+func (s *JournalMDStore) RestoreEntry(path string) (*models.JournalEntry, error) {
+	loc, err := s.resolveTrashedPath(path)
+	if err != nil {
+		return nil, err
+	}
+	rel := strings.TrimPrefix(loc.rel, trashDirName+"/")
+	livePath := filepath.Join(loc.absRoot, filepath.FromSlash(rel))
+	if _, err := os.Stat(livePath); err == nil {
+		return nil, fmt.Errorf("cannot restore: %s already exists", livePath)
+	}
+
+	entry, err := s.readEntryFile(loc.path)
+	if err != nil {
+		return nil, err
+	}
+	entry.DeletedAt = time.Time{}
+	entry.DeleteReason = ""
+
+	if err := mdstore.EnsureDir(filepath.Dir(livePath)); err != nil {
+		return nil, fmt.Errorf("failed to create entry directory: %w", err)
+	}
+	err = mdstore.WithLock(filepath.Dir(livePath), func() error {
+		// Move the sidecar first so writeEntryFile can refresh it in place
+		if err := moveSidecar(loc.path, livePath); err != nil {
+			return err
+		}
+		if err := s.writeEntryFile(loc.root, livePath, entry); err != nil {
+			return err
+		}
+		if err := os.Remove(loc.path); err != nil {
+			return fmt.Errorf("failed to remove trashed entry: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.withIndex(loc.root, nil); err != nil {
+		return nil, fmt.Errorf("failed to update index: %w", err)
+	}
+	return entry, nil
+}
+
+// ListTrash returns trashed entries for the given type filter, most recently
+// deleted first.
+func (s *JournalMDStore) ListTrash(entryType string) ([]*models.JournalEntry, error) {
+	roots, journals, err := s.rootsFor(entryType)
+	if err != nil {
+		return nil, err
+	}
+	var entries []*models.JournalEntry
+	for _, root := range roots {
+		rootEntries, err := listEntriesInRoot(filepath.Join(root, trashDirName), s.cipher)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list trash: %w", err)
+		}
+		for _, entry := range rootEntries {
+			if inJournals(entry.Type, journals) {
+				entries = append(entries, entry)
+			}
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].DeletedAt.After(entries[j].DeletedAt)
+	})
+	return entries, nil
+}
+
+// EmptyTrash permanently removes trashed entries deleted more than olderThan
+// ago, or all of them when olderThan is zero. Returns the number removed.
+func (s *JournalMDStore) EmptyTrash(olderThan time.Duration) (int, error) {
+	entries, err := s.ListTrash("all")
+	if err != nil {
+		return 0, err
+	}
+
+	cutoff := time.Now().Add(-olderThan)
+	removed := 0
+	for _, entry := range entries {
+		if olderThan > 0 && entry.DeletedAt.After(cutoff) {
+			continue
+		}
+		if err := os.Remove(entry.FilePath); err != nil && !os.IsNotExist(err) {
+			return removed, fmt.Errorf("failed to remove trashed entry: %w", err)
+		}
+		if err := os.Remove(embeddings.EmbeddingPath(entry.FilePath)); err != nil && !os.IsNotExist(err) {
+			return removed, fmt.Errorf("failed to remove trashed embedding: %w", err)
+		}
+		// Drop the date directory once it is empty; Remove fails otherwise
+		_ = os.Remove(filepath.Dir(entry.FilePath))
+		removed++
+	}
+	return removed, nil
+}
+
+// resolveTrashedPath locates a trashed entry from either its trash path or
+// the path it had before deletion.
+func (s *JournalMDStore) resolveTrashedPath(path string) (entryLocation, error) {
+	if loc, err := s.resolveEntryPath(path); err == nil {
+		if !isTrashed(loc.rel) {
+			return entryLocation{}, fmt.Errorf("entry %q is not in the trash", path)
+		}
+		return loc, nil
+	}
+
+	// The original file is gone, so map its path into the trash and resolve
+	// that instead; resolveEntryPath re-checks confinement.
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return entryLocation{}, fmt.Errorf("invalid path: %w", err)
+	}
+	for _, root := range s.allRoots() {
+		for _, base := range []string{filepath.Clean(root), resolveRoot(root)} {
+			if absBase, err := filepath.Abs(base); err == nil {
+				base = absBase
+			}
+			if !strings.HasPrefix(absPath, base+string(filepath.Separator)) {
+				continue
+			}
+			rel, _ := filepath.Rel(base, absPath)
+			loc, err := s.resolveEntryPath(filepath.Join(base, trashDirName, rel))
+			if err == nil && isTrashed(loc.rel) {
+				return loc, nil
+			}
+		}
+	}
+	return entryLocation{}, fmt.Errorf("no trashed entry found for %q", path)
+}
+
+// moveSidecar renames the embedding sidecar of from to sit beside to, if one exists.
+func moveSidecar(from, to string) error {
+	err := os.Rename(embeddings.EmbeddingPath(from), embeddings.EmbeddingPath(to))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to move embedding: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+// ABOUTME: Tests for soft deletion of journal entries into the trash.
+// ABOUTME: Covers trash metadata, exclusion from listing and search, restore, and retention.
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/2389-research/pulse/internal/embeddings"
+	"github.com/2389-research/pulse/internal/models"
+)
+
+func TestDeleteEntryMovesToTrash(t *testing.T) {
+	tmpDir := t.TempDir()
+	userDir := filepath.Join(tmpDir, "user")
+	embedder := embeddings.NewHashEmbedder(embeddings.DefaultDimension)
+	store, _ := NewJournalMDStore(filepath.Join(tmpDir, "project"), userDir, WithEmbedder(embedder))
+
+	entry := models.NewJournalEntry(map[string]string{"feelings": "regrettable rant about the build"}, "user")
+	if err := store.WriteEntry(entry); err != nil {
+		t.Fatalf("WriteEntry error: %v", err)
+	}
+
+	trashed, err := store.DeleteEntry(entry.FilePath, "too harsh")
+	if err != nil {
+		t.Fatalf("DeleteEntry error: %v", err)
+	}
+	if !strings.Contains(trashed.FilePath, string(filepath.Separator)+trashDirName+string(filepath.Separator)) {
+		t.Errorf("expected trash path, got %s", trashed.FilePath)
+	}
+	if _, err := os.Stat(entry.FilePath); !os.IsNotExist(err) {
+		t.Errorf("expected original file to be gone, stat err = %v", err)
+	}
+	if _, err := os.Stat(embeddings.EmbeddingPath(trashed.FilePath)); err != nil {
+		t.Errorf("expected sidecar to move with the entry: %v", err)
+	}
+
+	data, err := os.ReadFile(trashed.FilePath)
+	if err != nil {
+		t.Fatalf("failed to read trashed entry: %v", err)
+	}
+	if !strings.Contains(string(data), "deleted_at:") || !strings.Contains(string(data), "delete_reason: too harsh") {
+		t.Errorf("expected deletion metadata in frontmatter, got:\n%s", data)
+	}
+
+	entries, _ := store.ListEntries(ListOptions{})
+	if len(entries) != 0 {
+		t.Errorf("expected trashed entry to be hidden from listing, got %d", len(entries))
+	}
+	results, _ := store.Search("rant", embeddings.SearchOptions{})
+	if len(results) != 0 {
+		t.Errorf("expected trashed entry to be hidden from search, got %d", len(results))
+	}
+	vecResults, _ := embeddings.SearchWithEmbeddings(embedder, []string{userDir}, "rant about the build", embeddings.SearchOptions{})
+	if len(vecResults) != 0 {
+		t.Errorf("expected trashed sidecar to be skipped, got %+v", vecResults)
+	}
+	rootEntries, _ := listEntriesInRoot(userDir, nil)
+	if len(rootEntries) != 0 {
+		t.Errorf("expected listEntriesInRoot to skip the trash, got %d", len(rootEntries))
+	}
+
+	trash, err := store.ListTrash("both")
+	if err != nil {
+		t.Fatalf("ListTrash error: %v", err)
+	}
+	if len(trash) != 1 || trash[0].DeleteReason != "too harsh" || trash[0].DeletedAt.IsZero() {
+		t.Fatalf("unexpected trash listing: %+v", trash)
+	}
+
+	if _, err := store.DeleteEntry(trashed.FilePath, ""); err == nil {
+		t.Error("expected deleting a trashed entry to fail")
+	}
+	if _, err := store.UpdateEntry(trashed.FilePath, map[string]string{"feelings": "x"}); err == nil {
+		t.Error("expected editing a trashed entry to fail")
+	}
+}
+
+func TestRestoreEntry(t *testing.T) {
+	tmpDir := t.TempDir()
+	store, _ := NewJournalMDStore(filepath.Join(tmpDir, "project"), filepath.Join(tmpDir, "user"))
+
+	for _, byOriginal := range []bool{false, true} {
+		entry := models.NewJournalEntry(map[string]string{"project_notes": "keep this"}, "project")
+		if err := store.WriteEntry(entry); err != nil {
+			t.Fatalf("WriteEntry error: %v", err)
+		}
+		trashed, err := store.DeleteEntry(entry.FilePath, "mistake")
+		if err != nil {
+			t.Fatalf("DeleteEntry error: %v", err)
+		}
+
+		path := trashed.FilePath
+		if byOriginal {
+			path = entry.FilePath
+		}
+		restored, err := store.RestoreEntry(path)
+		if err != nil {
+			t.Fatalf("RestoreEntry(byOriginal=%v) error: %v", byOriginal, err)
+		}
+		if restored.ID != entry.ID || !restored.DeletedAt.IsZero() || restored.DeleteReason != "" {
+			t.Errorf("unexpected restored entry: %+v", restored)
+		}
+
+		got, err := store.ReadEntry(entry.FilePath)
+		if err != nil {
+			t.Fatalf("ReadEntry error: %v", err)
+		}
+		if got.Sections["project_notes"] != "keep this" {
+			t.Errorf("restored content = %q", got.Sections["project_notes"])
+		}
+		if _, err := os.Stat(trashed.FilePath); !os.IsNotExist(err) {
+			t.Errorf("expected trash copy to be removed, stat err = %v", err)
+		}
+	}
+
+	entries, _ := store.ListEntries(ListOptions{Type: "project"})
+	if len(entries) != 2 {
+		t.Errorf("expected restored entries to be listed, got %d", len(entries))
+	}
+	if _, err := store.RestoreEntry(entries[0].FilePath); err == nil {
+		t.Error("expected restoring a live entry to fail")
+	}
+}
+
+func TestEmptyTrashHonorsRetention(t *testing.T) {
+	tmpDir := t.TempDir()
+	store, _ := NewJournalMDStore(filepath.Join(tmpDir, "project"), filepath.Join(tmpDir, "user"))
+
+	var trashed []*models.JournalEntry
+	for _, text := range []string{"old", "new"} {
+		entry := models.NewJournalEntry(map[string]string{"feelings": text}, "user")
+		if err := store.WriteEntry(entry); err != nil {
+			t.Fatalf("WriteEntry error: %v", err)
+		}
+		te, err := store.DeleteEntry(entry.FilePath, "")
+		if err != nil {
+			t.Fatalf("DeleteEntry error: %v", err)
+		}
+		trashed = append(trashed, te)
+	}
+
+	// Backdate the first deletion past the retention period
+	old := trashed[0]
+	old.DeletedAt = time.Now().Add(-40 * 24 * time.Hour)
+	content, err := renderJournalEntry(old)
+	if err != nil {
+		t.Fatalf("renderJournalEntry error: %v", err)
+	}
+	if err := os.WriteFile(old.FilePath, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to backdate entry: %v", err)
+	}
+
+	removed, err := store.EmptyTrash(30 * 24 * time.Hour)
+	if err != nil {
+		t.Fatalf("EmptyTrash error: %v", err)
+	}
+	if removed != 1 {
+		t.Errorf("expected 1 expired entry removed, got %d", removed)
+	}
+	if _, err := os.Stat(old.FilePath); !os.IsNotExist(err) {
+		t.Errorf("expected expired entry to be purged, stat err = %v", err)
+	}
+
+	removed, err = store.EmptyTrash(0)
+	if err != nil {
+		t.Fatalf("EmptyTrash error: %v", err)
+	}
+	if removed != 1 {
+		t.Errorf("expected remaining entry removed, got %d", removed)
+	}
+	if trash, _ := store.ListTrash("both"); len(trash) != 0 {
+		t.Errorf("expected empty trash, got %d", len(trash))
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/2389-research/pulse/internal/models"
+	"github.com/2389-research/pulse/internal/redact"
 	"github.com/google/uuid"
 )
 
@@ -45,18 +46,36 @@ type RemoteClient struct {
 	apiKey string
 	teamID string
 	client *http.Client
+
+	redactor *redact.Redactor // optional; scans payloads before they leave the machine
+}
+
+// RemoteOption configures optional RemoteClient behavior.
+type RemoteOption func(*RemoteClient)
+
+// WithPayloadRedactor scans journal sections and post content in outgoing
+// payloads for secrets and personal data. Masked text is sent in place of the
+// original; in block mode the request is never made.
+func WithPayloadRedactor(r *redact.Redactor) RemoteOption {
+	return func(c *RemoteClient) {
+		c.redactor = r
+	}
 }
 
 // NewRemoteClient creates a remote client with the given credentials.
-func NewRemoteClient(apiURL, apiKey, teamID string) *RemoteClient {
+func NewRemoteClient(apiURL, apiKey, teamID string, opts ...RemoteOption) *RemoteClient {
 	apiURL = strings.TrimRight(apiURL, "/")
 	apiURL = strings.TrimSuffix(apiURL, "/v1")
-	return &RemoteClient{
+	c := &RemoteClient{
 		apiURL: apiURL,
 		apiKey: apiKey,
 		teamID: teamID,
 		client: &http.Client{Timeout: 30 * time.Second},
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // teamPath returns the base URL path for team-scoped API endpoints,
@@ -102,8 +121,19 @@ type remoteJournalPayload struct {
 	Sections  map[string]string `json:"sections"`
 }
 
-// CreateJournalEntry posts a journal entry to the remote API.
+// CreateJournalEntry posts a journal entry to the remote API. Callers choose
+// which sections to send with models.SyncableSections; as a safeguard, sections
+// whose policy is never to sync, or that are undeclared, are dropped here too,
+// and nothing is sent if none remain.
 func (r *RemoteClient) CreateJournalEntry(ctx context.Context, sections map[string]string, timestamp time.Time) error {
+	sections, _ = models.SyncableSections(sections, true)
+	if len(sections) == 0 {
+		return nil
+	}
+	sections, _, err := r.redactor.Sections(sections)
+	if err != nil {
+		return err
+	}
 	payload := remoteJournalPayload{
 		TeamID:    r.teamID,
 		Timestamp: timestamp.UnixMilli(),
@@ -202,8 +232,12 @@ func (r *RemoteClient) ReadJournalEntries(ctx context.Context, limit int) ([]*mo
 
 // CreatePost sends a social post to the remote API.
 func (r *RemoteClient) CreatePost(ctx context.Context, post *models.SocialPost) error {
+	content, _, err := r.redactor.Text("content", post.Content)
+	if err != nil {
+		return err
+	}
 	payload := remotePostPayload{
-		Content:    post.Content,
+		Content:    content,
 		AuthorName: post.AuthorName,
 		Tags:       post.Tags,
 	}
@@ -273,6 +273,43 @@ func TestRemoteClientCreateJournalEntryError(t *testing.T) {
 	}
 }
 
+func TestRemoteClientCreateJournalEntryDropsLocalSections(t *testing.T) {
+	t.Cleanup(func() { _ = models.SetSections(models.DefaultSections()) })
+	defs := append(models.DefaultSections(), models.SectionDef{Name: "secrets", Route: "user", Sync: models.SyncNever})
+	if err := models.SetSections(defs); err != nil {
+		t.Fatalf("SetSections error: %v", err)
+	}
+
+	var requests int
+	var payload remoteJournalPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	client := NewRemoteClient(server.URL, "key", "team")
+	err := client.CreateJournalEntry(context.Background(), map[string]string{
+		"project_notes": "shipped",
+		"secrets":       "hunter2",
+		"undeclared":    "?",
+	}, time.Now())
+	if err != nil {
+		t.Fatalf("CreateJournalEntry error: %v", err)
+	}
+	if len(payload.Sections) != 1 || payload.Sections["project_notes"] != "shipped" {
+		t.Errorf("expected only project_notes to be sent, got %v", payload.Sections)
+	}
+
+	if err := client.CreateJournalEntry(context.Background(), map[string]string{"secrets": "hunter2"}, time.Now()); err != nil {
+		t.Fatalf("CreateJournalEntry error: %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("expected no request when nothing may sync, got %d requests", requests)
+	}
+}
+
 func TestRemoteClientReadJournalEntries(t *testing.T) {
 	var receivedAuth string
 	var receivedPath string
@@ -14,11 +14,24 @@ import (
 	"gopkg.in/yaml.v3"
 
 	"github.com/2389-research/pulse/internal/models"
+	"github.com/2389-research/pulse/internal/redact"
 )
 
 // SocialMDStore stores social posts as markdown files in a data directory.
 type SocialMDStore struct {
-	dataDir string // root directory for social data
+	dataDir  string           // root directory for social data
+	redactor *redact.Redactor // optional; scans post content before it is written
+}
+
+// SocialOption configures optional SocialMDStore dependencies.
+type SocialOption func(*SocialMDStore)
+
+// WithPostRedactor scans the content of every created post for secrets and
+// personal data, masking, reporting, or refusing them per its mode.
+func WithPostRedactor(r *redact.Redactor) SocialOption {
+	return func(s *SocialMDStore) {
+		s.redactor = r
+	}
 }
 
 // socialFrontmatter is the YAML frontmatter for social post files.
@@ -37,14 +50,26 @@ type identityFile struct {
 }
 
 // NewSocialMDStore creates a social store with the given data directory.
-func NewSocialMDStore(dataDir string) (*SocialMDStore, error) {
-	return &SocialMDStore{
+func NewSocialMDStore(dataDir string, opts ...SocialOption) (*SocialMDStore, error) {
+	s := &SocialMDStore{
 		dataDir: dataDir,
-	}, nil
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s, nil
 }
 
-// CreatePost persists a social post to disk.
+// CreatePost persists a social post to disk. Redacted content replaces
+// post.Content and the findings are recorded in post.Redactions.
 func (s *SocialMDStore) CreatePost(post *models.SocialPost) error {
+	redacted, findings, err := s.redactor.Text("content", post.Content)
+	if err != nil {
+		return err
+	}
+	post.Content = redacted
+	post.Redactions = findings
+
 	postsDir := filepath.Join(s.dataDir, "posts")
 	dateDir := post.CreatedAt.Format("2006-01-02")
 	timeStr := post.CreatedAt.Format("15-04-05-000000")
@@ -0,0 +1,218 @@
+// ABOUTME: Named journal templates: section scaffolding with guiding questions for common kinds of entries.
+// ABOUTME: Built-in standup, postmortem, and learning templates can be replaced or extended by files in the config directory.
+package templates
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/harperreed/mdstore"
+	"gopkg.in/yaml.v3"
+
+	"github.com/2389-research/pulse/internal/models"
+)
+
+// Template scaffolds a journal entry. Its body holds one "## Title" heading
+// per section, each followed by guiding questions in HTML comments:
+//
+//	## Project Notes
+//	<!-- What did you finish since the last standup? -->
+//
+// Comments are dropped when a filled-in template is written.
+type Template struct {
+	Name        string
+	Description string
+	Tags        []string // recorded on entries written from the template
+	Body        string
+	Path        string // file the template was loaded from; empty for built-ins
+}
+
+// frontmatter is the optional YAML frontmatter of a template file.
+type frontmatter struct {
+	Description string   `yaml:"description,omitempty"`
+	Tags        []string `yaml:"tags,omitempty"`
+}
+
+var builtin = []Template{
+	{
+		Name:        "standup",
+		Description: "What you did, what is next, and what is in the way",
+		Tags:        []string{"standup"},
+		Body: `## Project Notes
+
+<!-- What did you finish since the last standup? -->
+<!-- What are you working on next? -->
+<!-- What is blocking you, and who could unblock it? -->
+
+## Feelings
+
+<!-- How do you feel about the work right now? -->
+`,
+	},
+	{
+		Name:        "postmortem",
+		Description: "What went wrong, why, and what changes because of it",
+		Tags:        []string{"postmortem"},
+		Body: `## Project Notes
+
+<!-- What happened, and when was it noticed? -->
+<!-- What was the impact, and on whom? -->
+<!-- What was the root cause? -->
+<!-- What went well in the response? -->
+<!-- What follow-ups will prevent it happening again, and who owns them? -->
+
+## Technical Insights
+
+<!-- What does this teach you beyond this project? -->
+
+## Feelings
+
+<!-- How did the incident and the response feel? -->
+`,
+	},
+	{
+		Name:        "learning",
+		Description: "Something you learned and where it applies",
+		Tags:        []string{"learning"},
+		Body: `## Technical Insights
+
+<!-- What did you learn? -->
+<!-- How did you learn it: reading, an experiment, a mistake? -->
+<!-- Where else will it apply? -->
+
+## World Knowledge
+
+<!-- Anything else interesting you came across? -->
+`,
+	},
+}
+
+var templateNamePattern = regexp.MustCompile(`^[a-z][a-z0-9]*([_-][a-z0-9]+)*$`)
+
+var commentPattern = regexp.MustCompile(`(?s)<!--(.*?)-->`)
+
+// Builtin returns the built-in templates.
+func Builtin() []Template {
+	out := make([]Template, len(builtin))
+	copy(out, builtin)
+	return out
+}
+
+// Load returns the built-in templates merged with the *.md files in dir,
+// sorted by name. A file named like a built-in, e.g. standup.md, replaces it.
+// A missing dir yields just the built-ins.
+func Load(dir string) ([]Template, error) {
+	byName := make(map[string]Template)
+	for _, t := range builtin {
+		byName[t.Name] = t
+	}
+
+	files, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read templates: %w", err)
+	}
+	for _, file := range files {
+		name, ok := strings.CutSuffix(file.Name(), ".md")
+		if file.IsDir() || !ok {
+			continue
+		}
+		t, err := loadFile(filepath.Join(dir, file.Name()), name)
+		if err != nil {
+			return nil, err
+		}
+		byName[name] = t
+	}
+
+	out := make([]Template, 0, len(byName))
+	for _, t := range byName {
+		out = append(out, t)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+// loadFile reads the template named name from path.
+func loadFile(path, name string) (Template, error) {
+	if !templateNamePattern.MatchString(name) {
+		return Template{}, fmt.Errorf("invalid template name %q: use lowercase letters, digits, - and _", name)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Template{}, fmt.Errorf("failed to read template %s: %w", name, err)
+	}
+	yamlStr, body := mdstore.ParseFrontmatter(string(data))
+	var fm frontmatter
+	if yamlStr != "" {
+		if err := yaml.Unmarshal([]byte(yamlStr), &fm); err != nil {
+			return Template{}, fmt.Errorf("failed to parse template %s: %w", name, err)
+		}
+	}
+	return Template{Name: name, Description: fm.Description, Tags: fm.Tags, Body: body, Path: path}, nil
+}
+
+// Find returns the template called name.
+func Find(templates []Template, name string) (Template, error) {
+	names := make([]string, len(templates))
+	for i, t := range templates {
+		if t.Name == name {
+			return t, nil
+		}
+		names[i] = t.Name
+	}
+	return Template{}, fmt.Errorf("unknown template %q: must be one of: %s", name, strings.Join(names, ", "))
+}
+
+// Sections returns the names of the sections the template scaffolds, in order.
+func (t Template) Sections() []string {
+	var names []string
+	for _, line := range strings.Split(t.Body, "\n") {
+		if heading, ok := strings.CutPrefix(line, "## "); ok {
+			names = append(names, models.SectionKey(heading))
+		}
+	}
+	return names
+}
+
+// Questions returns the guiding questions under each section heading.
+func (t Template) Questions() map[string][]string {
+	questions := make(map[string][]string)
+	for name, content := range models.ParseSections(t.Body) {
+		for _, m := range commentPattern.FindAllStringSubmatch(content, -1) {
+			if q := strings.TrimSpace(m[1]); q != "" {
+				questions[name] = append(questions[name], q)
+			}
+		}
+	}
+	return questions
+}
+
+// Validate checks that every section the template scaffolds is in the
+// active section schema.
+func (t Template) Validate() error {
+	sections := t.Sections()
+	if len(sections) == 0 {
+		return fmt.Errorf("template %s has no \"## \" section headings", t.Name)
+	}
+	for _, name := range sections {
+		if !models.IsValidSection(name) {
+			return fmt.Errorf("template %s uses unknown section %q: valid sections are %s", t.Name, name, models.ValidSectionList())
+		}
+	}
+	return nil
+}
+
+// Fill extracts the sections of a filled-in template, dropping the guiding
+// question comments and any section left empty.
+func Fill(text string) map[string]string {
+	sections := models.ParseSections(commentPattern.ReplaceAllString(text, ""))
+	for name, content := range sections {
+		if content == "" {
+			delete(sections, name)
+		}
+	}
+	return sections
+}
@@ -0,0 +1,101 @@
+// ABOUTME: Tests for journal templates.
+// ABOUTME: Covers the built-ins, loading and overriding from a directory, guiding questions, and filling one in.
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBuiltinTemplatesAreValid(t *testing.T) {
+	for _, tmpl := range Builtin() {
+		if err := tmpl.Validate(); err != nil {
+			t.Errorf("built-in template %s: %v", tmpl.Name, err)
+		}
+		if len(tmpl.Questions()) == 0 {
+			t.Errorf("built-in template %s has no guiding questions", tmpl.Name)
+		}
+	}
+}
+
+func TestLoadMergesDirectory(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"standup.md": "---\ndescription: Short standup\ntags: [daily]\n---\n## Project Notes\n<!-- What changed? -->\n",
+		"retro.md":   "## Feelings\n<!-- How did the week go? -->\n",
+		"notes.txt":  "ignored",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	all, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+	var names []string
+	for _, tmpl := range all {
+		names = append(names, tmpl.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"learning", "postmortem", "retro", "standup"}) {
+		t.Errorf("unexpected templates %v", names)
+	}
+
+	standup, err := Find(all, "standup")
+	if err != nil {
+		t.Fatalf("Find error: %v", err)
+	}
+	if standup.Description != "Short standup" || !reflect.DeepEqual(standup.Tags, []string{"daily"}) || standup.Path == "" {
+		t.Errorf("expected the file to replace the built-in, got %+v", standup)
+	}
+	if q := standup.Questions()["project_notes"]; len(q) != 1 || q[0] != "What changed?" {
+		t.Errorf("unexpected questions %v", q)
+	}
+	if _, err := Find(all, "nope"); err == nil || !strings.Contains(err.Error(), "learning, postmortem") {
+		t.Errorf("expected an unknown template error listing names, got %v", err)
+	}
+
+	if all, err := Load(filepath.Join(dir, "missing")); err != nil || len(all) != len(Builtin()) {
+		t.Errorf("expected the built-ins for a missing directory, got %d, %v", len(all), err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "Bad Name.md"), []byte("## Feelings\n"), 0o600); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+	if _, err := Load(dir); err == nil {
+		t.Error("expected an invalid template name to be rejected")
+	}
+}
+
+func TestValidateRejectsUnknownSections(t *testing.T) {
+	for _, body := range []string{"no headings", "## Feelings\n\n## Bogus\n"} {
+		if err := (Template{Name: "x", Body: body}).Validate(); err == nil {
+			t.Errorf("expected %q to be rejected", body)
+		}
+	}
+}
+
+func TestFill(t *testing.T) {
+	text := `## Project Notes
+
+<!-- What did you finish? -->
+Shipped templates
+<!-- What is next?
+     (spans lines) -->
+
+## Feelings
+
+<!-- How do you feel? -->
+`
+	got := Fill(text)
+	if !reflect.DeepEqual(got, map[string]string{"project_notes": "Shipped templates"}) {
+		t.Errorf("Fill() = %v", got)
+	}
+	if got := Fill(Builtin()[0].Body); len(got) != 0 {
+		t.Errorf("expected an untouched template to fill nothing, got %v", got)
+	}
+}